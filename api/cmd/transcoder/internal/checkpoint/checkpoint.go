@@ -0,0 +1,131 @@
+// Package checkpoint records per-stage completion markers for a transcode
+// job so a retried Cloud Run Job execution can skip work a previous attempt
+// already finished instead of redoing the entire pipeline after one flaky
+// step (e.g. a single dropped segment upload).
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ai-teammate/mytube/api/internal/blob"
+)
+
+// Stage identifies one resumable unit of pipeline work.
+type Stage string
+
+const (
+	StageDownload   Stage = "download"
+	StageThumbnail  Stage = "thumbnail"
+	StageStoryboard Stage = "storyboard"
+	StageUpload     Stage = "upload"
+	StageDBUpdate   Stage = "db_update"
+)
+
+// RenditionStage returns the Stage marking a single rendition's encode as
+// complete, so a resumed job can skip the renditions a previous attempt
+// already finished and uploaded while still re-encoding the ones that
+// didn't make it.
+func RenditionStage(renditionName string) Stage {
+	return Stage("rendition:" + renditionName)
+}
+
+// Store records and queries per-stage completion markers for a video.
+// Implementations must be safe for concurrent use, since rendition stages
+// may be marked done from multiple worker-pool goroutines at once.
+type Store interface {
+	// Done reports whether stage has already completed for videoID.
+	Done(ctx context.Context, videoID string, stage Stage) (bool, error)
+	// MarkDone records stage as complete for videoID.
+	MarkDone(ctx context.Context, videoID string, stage Stage) error
+	// Clear removes every marker in stages for videoID, so FORCE_REPROCESS
+	// can start a video over from a clean slate.
+	Clear(ctx context.Context, videoID string, stages []Stage) error
+}
+
+// BlobStore persists markers as empty objects under
+// videos/<id>/.checkpoints/<stage> in bucket, using the same Backend as the
+// rest of the upload pipeline so resumability needs no extra infrastructure.
+type BlobStore struct {
+	Backend blob.Backend
+	Bucket  string
+}
+
+// NewBlobStore constructs a BlobStore backed by backend, storing markers in
+// bucket (the same HLS output bucket the transcoder already uploads to).
+func NewBlobStore(backend blob.Backend, bucket string) *BlobStore {
+	return &BlobStore{Backend: backend, Bucket: bucket}
+}
+
+func (s *BlobStore) key(videoID string, stage Stage) string {
+	return fmt.Sprintf("videos/%s/.checkpoints/%s", videoID, stage)
+}
+
+// Done reports whether stage's marker object exists for videoID.
+func (s *BlobStore) Done(ctx context.Context, videoID string, stage Stage) (bool, error) {
+	ok, err := s.Backend.Exists(ctx, s.Bucket, s.key(videoID, stage))
+	if err != nil {
+		return false, fmt.Errorf("check checkpoint %s for video %s: %w", stage, videoID, err)
+	}
+	return ok, nil
+}
+
+// MarkDone writes an empty marker object recording stage as complete.
+func (s *BlobStore) MarkDone(ctx context.Context, videoID string, stage Stage) error {
+	wc := s.Backend.NewWriter(ctx, s.Bucket, s.key(videoID, stage))
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("write checkpoint %s for video %s: %w", stage, videoID, err)
+	}
+	return nil
+}
+
+// Clear deletes the marker object for each of stages.
+func (s *BlobStore) Clear(ctx context.Context, videoID string, stages []Stage) error {
+	for _, stage := range stages {
+		if err := s.Backend.Delete(ctx, s.Bucket, s.key(videoID, stage)); err != nil {
+			return fmt.Errorf("clear checkpoint %s for video %s: %w", stage, videoID, err)
+		}
+	}
+	return nil
+}
+
+// MemStore is an in-memory Store for tests, avoiding a fake blob backend.
+type MemStore struct {
+	mu   sync.Mutex
+	done map[string]bool
+}
+
+// NewMemStore constructs an empty MemStore.
+func NewMemStore() *MemStore {
+	return &MemStore{done: map[string]bool{}}
+}
+
+func (s *MemStore) key(videoID string, stage Stage) string {
+	return videoID + "/" + string(stage)
+}
+
+// Done reports whether stage has been marked done for videoID.
+func (s *MemStore) Done(_ context.Context, videoID string, stage Stage) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done[s.key(videoID, stage)], nil
+}
+
+// MarkDone records stage as complete for videoID.
+func (s *MemStore) MarkDone(_ context.Context, videoID string, stage Stage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done[s.key(videoID, stage)] = true
+	return nil
+}
+
+// Clear removes every marker in stages for videoID.
+func (s *MemStore) Clear(_ context.Context, videoID string, stages []Stage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, stage := range stages {
+		delete(s.done, s.key(videoID, stage))
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package checkpoint_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/checkpoint"
+)
+
+// memBackend is a minimal in-memory blob.Backend fake, just enough to
+// exercise BlobStore without a real GCS/S3 dependency.
+type memBackend struct {
+	mu      sync.Mutex
+	objects map[string]bool
+}
+
+func newMemBackend() *memBackend { return &memBackend{objects: map[string]bool{}} }
+
+func (m *memBackend) key(bucket, object string) string { return bucket + "/" + object }
+
+func (m *memBackend) NewReader(context.Context, string, string) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+func (m *memBackend) NewWriter(_ context.Context, bucket, object string) io.WriteCloser {
+	return &memWriter{backend: m, key: m.key(bucket, object)}
+}
+
+func (m *memBackend) Exists(_ context.Context, bucket, object string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.objects[m.key(bucket, object)], nil
+}
+
+func (m *memBackend) Delete(_ context.Context, bucket, object string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, m.key(bucket, object))
+	return nil
+}
+
+type memWriter struct {
+	backend *memBackend
+	key     string
+}
+
+func (w *memWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func (w *memWriter) Close() error {
+	w.backend.mu.Lock()
+	defer w.backend.mu.Unlock()
+	w.backend.objects[w.key] = true
+	return nil
+}
+
+// ── RenditionStage ────────────────────────────────────────────────────────────
+
+func TestRenditionStage_PrefixesName(t *testing.T) {
+	if got, want := checkpoint.RenditionStage("720p"), checkpoint.Stage("rendition:720p"); got != want {
+		t.Errorf("RenditionStage(%q) = %q, want %q", "720p", got, want)
+	}
+}
+
+// ── BlobStore ─────────────────────────────────────────────────────────────────
+
+func TestBlobStore_Done_FalseBeforeMarkDone(t *testing.T) {
+	store := checkpoint.NewBlobStore(newMemBackend(), "hls-bucket")
+
+	done, err := store.Done(context.Background(), "vid", checkpoint.StageDownload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if done {
+		t.Error("expected Done to be false before MarkDone")
+	}
+}
+
+func TestBlobStore_Done_TrueAfterMarkDone(t *testing.T) {
+	store := checkpoint.NewBlobStore(newMemBackend(), "hls-bucket")
+	ctx := context.Background()
+
+	if err := store.MarkDone(ctx, "vid", checkpoint.StageDownload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done, err := store.Done(ctx, "vid", checkpoint.StageDownload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !done {
+		t.Error("expected Done to be true after MarkDone")
+	}
+}
+
+func TestBlobStore_MarkDone_IsolatedPerVideoAndStage(t *testing.T) {
+	store := checkpoint.NewBlobStore(newMemBackend(), "hls-bucket")
+	ctx := context.Background()
+
+	if err := store.MarkDone(ctx, "vid-1", checkpoint.RenditionStage("360p")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if done, _ := store.Done(ctx, "vid-1", checkpoint.RenditionStage("720p")); done {
+		t.Error("expected a different rendition stage to remain unmarked")
+	}
+	if done, _ := store.Done(ctx, "vid-2", checkpoint.RenditionStage("360p")); done {
+		t.Error("expected a different video to remain unmarked")
+	}
+}
+
+func TestBlobStore_Clear_RemovesMarkers(t *testing.T) {
+	store := checkpoint.NewBlobStore(newMemBackend(), "hls-bucket")
+	ctx := context.Background()
+
+	if err := store.MarkDone(ctx, "vid", checkpoint.StageDownload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.MarkDone(ctx, "vid", checkpoint.StageUpload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := store.Clear(ctx, "vid", []checkpoint.Stage{checkpoint.StageDownload, checkpoint.StageUpload}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, stage := range []checkpoint.Stage{checkpoint.StageDownload, checkpoint.StageUpload} {
+		if done, _ := store.Done(ctx, "vid", stage); done {
+			t.Errorf("expected stage %s to be cleared", stage)
+		}
+	}
+}
+
+func TestBlobStore_Clear_AbsentMarkerIsNotError(t *testing.T) {
+	store := checkpoint.NewBlobStore(newMemBackend(), "hls-bucket")
+
+	if err := store.Clear(context.Background(), "vid", []checkpoint.Stage{checkpoint.StageThumbnail}); err != nil {
+		t.Fatalf("expected clearing an unmarked stage to be a no-op, got: %v", err)
+	}
+}
+
+// ── MemStore ───────────────────────────────────────────────────────────────────
+
+func TestMemStore_Done_FalseBeforeMarkDone(t *testing.T) {
+	store := checkpoint.NewMemStore()
+
+	done, err := store.Done(context.Background(), "vid", checkpoint.StageDBUpdate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if done {
+		t.Error("expected Done to be false before MarkDone")
+	}
+}
+
+func TestMemStore_Done_TrueAfterMarkDone(t *testing.T) {
+	store := checkpoint.NewMemStore()
+	ctx := context.Background()
+
+	if err := store.MarkDone(ctx, "vid", checkpoint.StageDBUpdate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done, err := store.Done(ctx, "vid", checkpoint.StageDBUpdate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !done {
+		t.Error("expected Done to be true after MarkDone")
+	}
+}
+
+func TestMemStore_Clear_RemovesMarkers(t *testing.T) {
+	store := checkpoint.NewMemStore()
+	ctx := context.Background()
+
+	_ = store.MarkDone(ctx, "vid", checkpoint.RenditionStage("1080p"))
+	_ = store.Clear(ctx, "vid", []checkpoint.Stage{checkpoint.RenditionStage("1080p")})
+
+	if done, _ := store.Done(ctx, "vid", checkpoint.RenditionStage("1080p")); done {
+		t.Error("expected marker to be cleared")
+	}
+}
@@ -0,0 +1,81 @@
+// Package dash builds MPEG-DASH media presentation description (MPD)
+// manifests from the same CMAF init/media segments FFmpeg writes for HLS, so
+// the two output formats stay decoupled from ffmpeg while sharing files on
+// disk.
+package dash
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Representation describes one adaptive-bitrate rendition's DASH metadata,
+// pointing at the CMAF segments already written for the matching HLS
+// variant.
+type Representation struct {
+	// ID is the DASH Representation @id (e.g. "360p").
+	ID string
+	// Width and Height are the encoded video dimensions in pixels.
+	Width, Height int
+	// Bandwidth is the representation's bitrate in bits/sec.
+	Bandwidth int
+	// InitPath is the init segment's path relative to the manifest.
+	InitPath string
+	// MediaTemplate is the $Number$ segment-name template, relative to the
+	// manifest (e.g. "360p_$Number%05d$.m4s").
+	MediaTemplate string
+}
+
+// ManifestOptions configures BuildMPD.
+type ManifestOptions struct {
+	// DurationSeconds is the media presentation duration. Zero omits
+	// mediaPresentationDuration from the manifest.
+	DurationSeconds float64
+	// SegmentDurationSeconds is the nominal segment duration, matching the
+	// HLS "-hls_time" value.
+	SegmentDurationSeconds int
+	Representations        []Representation
+}
+
+// BuildMPD renders a static-profile MPD referencing opts.Representations via
+// SegmentTemplate, so it never needs to know how playback fetches the
+// underlying CMAF files.
+func BuildMPD(opts ManifestOptions) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" type="static" profiles="urn:mpeg:dash:profile:isoff-live:2011" minBufferTime="PT2S"`)
+	if opts.DurationSeconds > 0 {
+		b.WriteString(fmt.Sprintf(` mediaPresentationDuration="PT%.3fS"`, opts.DurationSeconds))
+	}
+	b.WriteString(">\n  <Period>\n")
+	b.WriteString(`    <AdaptationSet mimeType="video/mp4" segmentAlignment="true" startWithSAP="1">` + "\n")
+
+	segDuration := opts.SegmentDurationSeconds
+	if segDuration <= 0 {
+		segDuration = 6
+	}
+
+	for _, rep := range opts.Representations {
+		b.WriteString(fmt.Sprintf(
+			"      <Representation id=%q bandwidth=\"%d\" width=\"%d\" height=\"%d\">\n",
+			rep.ID, rep.Bandwidth, rep.Width, rep.Height,
+		))
+		b.WriteString(fmt.Sprintf(
+			"        <SegmentTemplate timescale=\"1000\" duration=\"%d000\" startNumber=\"0\" initialization=%q media=%q/>\n",
+			segDuration, rep.InitPath, rep.MediaTemplate,
+		))
+		b.WriteString("      </Representation>\n")
+	}
+
+	b.WriteString("    </AdaptationSet>\n  </Period>\n</MPD>\n")
+	return b.String()
+}
+
+// WriteMPD renders opts and writes it to path.
+func WriteMPD(path string, opts ManifestOptions) error {
+	if err := os.WriteFile(path, []byte(BuildMPD(opts)), 0o644); err != nil {
+		return fmt.Errorf("write MPD %s: %w", path, err)
+	}
+	return nil
+}
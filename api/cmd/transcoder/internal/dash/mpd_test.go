@@ -0,0 +1,66 @@
+package dash_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/dash"
+)
+
+func testRepresentations() []dash.Representation {
+	return []dash.Representation{
+		{ID: "360p", Width: 640, Height: 360, Bandwidth: 564000, InitPath: "360p_init.mp4", MediaTemplate: "360p_$Number%05d$.m4s"},
+		{ID: "720p", Width: 1280, Height: 720, Bandwidth: 1628000, InitPath: "720p_init.mp4", MediaTemplate: "720p_$Number%05d$.m4s"},
+	}
+}
+
+func TestBuildMPD_MatchesGoldenFile(t *testing.T) {
+	got := dash.BuildMPD(dash.ManifestOptions{
+		DurationSeconds:        125.5,
+		SegmentDurationSeconds: 6,
+		Representations:        testRepresentations(),
+	})
+
+	want, err := os.ReadFile(filepath.Join("testdata", "golden.mpd"))
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("BuildMPD() = %q, want %q", got, string(want))
+	}
+}
+
+func TestBuildMPD_OmitsDurationWhenZero(t *testing.T) {
+	got := dash.BuildMPD(dash.ManifestOptions{Representations: testRepresentations()})
+	if strings.Contains(got, "mediaPresentationDuration") {
+		t.Errorf("expected no mediaPresentationDuration when DurationSeconds is 0, got: %s", got)
+	}
+}
+
+func TestBuildMPD_DefaultsSegmentDuration(t *testing.T) {
+	got := dash.BuildMPD(dash.ManifestOptions{Representations: testRepresentations()})
+	if !strings.Contains(got, `duration="6000"`) {
+		t.Errorf("expected default 6s segment duration, got: %s", got)
+	}
+}
+
+func TestBuildMPD_IncludesEachRepresentation(t *testing.T) {
+	got := dash.BuildMPD(dash.ManifestOptions{Representations: testRepresentations()})
+	for _, want := range []string{`id="360p"`, `id="720p"`, "360p_init.mp4", "720p_init.mp4"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BuildMPD() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteMPD_WritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.mpd")
+	if err := dash.WriteMPD(path, dash.ManifestOptions{Representations: testRepresentations()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected manifest file to exist: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// envEncoder names the env var overriding DetectEncoder's automatic choice
+// (see Runner.encoder).
+const envEncoder = "FFMPEG_ENCODER"
+
+// Encoder picks the FFmpeg arguments for a rendition's video encode, so
+// Runner.EncodeRenditions can target a software or GPU-accelerated codec
+// without changing anything else about the HLS/CMAF pipeline.
+type Encoder interface {
+	// Name identifies the encoder for logging, e.g. "nvenc".
+	Name() string
+	// InputArgs returns FFmpeg args to insert before "-i", e.g.
+	// "-hwaccel cuda" or "-vaapi_device /dev/dri/renderD128".
+	InputArgs() []string
+	// VideoArgs returns the video-encode args for rend, reading from input
+	// stream streamIdx (almost always 0; multi-video-stream sources are
+	// not expected from the upload pipeline, but the index is threaded
+	// through so a future multi-stream source doesn't need an interface
+	// change).
+	VideoArgs(rend Rendition, streamIdx int) []string
+}
+
+// SoftwareX264 is the default CPU encoder: libx264 scaled in a plain
+// software filter. It is always available and is what every rendition used
+// before hardware backends existed.
+type SoftwareX264 struct{}
+
+func (SoftwareX264) Name() string { return "x264" }
+
+func (SoftwareX264) InputArgs() []string { return nil }
+
+func (SoftwareX264) VideoArgs(rend Rendition, streamIdx int) []string {
+	return []string{
+		"-map", fmt.Sprintf("0:v:%d", streamIdx),
+		"-c:v", "libx264",
+		"-b:v", rend.VideoBitrate,
+		"-vf", fmt.Sprintf("scale=-2:%d", rend.Height),
+	}
+}
+
+// NVENC encodes on an NVIDIA GPU via h264_nvenc. It requires a CUDA-capable
+// GPU and an FFmpeg build with nvenc support; DetectEncoder only selects it
+// when "ffmpeg -encoders" reports h264_nvenc as available.
+type NVENC struct{}
+
+func (NVENC) Name() string { return "nvenc" }
+
+func (NVENC) InputArgs() []string { return []string{"-hwaccel", "cuda"} }
+
+func (NVENC) VideoArgs(rend Rendition, streamIdx int) []string {
+	return []string{
+		"-map", fmt.Sprintf("0:v:%d", streamIdx),
+		"-c:v", "h264_nvenc",
+		"-preset", "p4",
+		"-b:v", rend.VideoBitrate,
+		"-vf", fmt.Sprintf("scale=-2:%d", rend.Height),
+	}
+}
+
+// VAAPI encodes via Intel/AMD Video Acceleration API, scaling and uploading
+// the frame to the VAAPI surface in the same filter graph since a software
+// scale can't run on hardware-mapped frames.
+type VAAPI struct{}
+
+func (VAAPI) Name() string { return "vaapi" }
+
+func (VAAPI) InputArgs() []string { return []string{"-vaapi_device", "/dev/dri/renderD128"} }
+
+func (VAAPI) VideoArgs(rend Rendition, streamIdx int) []string {
+	return []string{
+		"-map", fmt.Sprintf("0:v:%d", streamIdx),
+		"-c:v", "h264_vaapi",
+		"-b:v", rend.VideoBitrate,
+		"-vf", fmt.Sprintf("format=nv12,hwupload,scale_vaapi=-2:%d", rend.Height),
+	}
+}
+
+// QSV encodes via Intel Quick Sync Video.
+type QSV struct{}
+
+func (QSV) Name() string { return "qsv" }
+
+func (QSV) InputArgs() []string { return []string{"-hwaccel", "qsv"} }
+
+func (QSV) VideoArgs(rend Rendition, streamIdx int) []string {
+	return []string{
+		"-map", fmt.Sprintf("0:v:%d", streamIdx),
+		"-c:v", "h264_qsv",
+		"-b:v", rend.VideoBitrate,
+		"-vf", fmt.Sprintf("scale=-2:%d", rend.Height),
+	}
+}
+
+// encoderByName maps FFMPEG_ENCODER values (and the names DetectEncoder
+// matches against "ffmpeg -encoders" output) to their Encoder.
+func encoderByName(name string) (Encoder, bool) {
+	switch name {
+	case "x264", "":
+		return SoftwareX264{}, true
+	case "nvenc":
+		return NVENC{}, true
+	case "vaapi":
+		return VAAPI{}, true
+	case "qsv":
+		return QSV{}, true
+	default:
+		return nil, false
+	}
+}
+
+// DetectEncoder picks the best available video encoder: FFMPEG_ENCODER, if
+// set, forces a specific backend; otherwise it runs "ffmpeg -hide_banner
+// -encoders" once and prefers, in order, NVENC, VAAPI, QSV, falling back to
+// SoftwareX264 if none of the hardware encoders are compiled in (or ffmpeg
+// itself can't be run, e.g. in a test sandbox). It never returns an error:
+// an unrecognised FFMPEG_ENCODER value is ignored in favour of the automatic
+// probe, and a failed probe falls back to SoftwareX264, rather than aborting
+// the transcode over an encoder choice.
+func DetectEncoder(ctx context.Context) Encoder {
+	if name := strings.TrimSpace(os.Getenv(envEncoder)); name != "" {
+		if enc, ok := encoderByName(name); ok {
+			return enc
+		}
+	}
+
+	out, err := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return SoftwareX264{}
+	}
+	return detectEncoderFromOutput(out)
+}
+
+// detectEncoderFromOutput parses "ffmpeg -encoders" output and picks the
+// best available backend, preferring a GPU encoder over software so Cloud
+// Run Job instances with a GPU attached use it automatically.
+func detectEncoderFromOutput(out []byte) Encoder {
+	text := string(out)
+	switch {
+	case strings.Contains(text, "h264_nvenc"):
+		return NVENC{}
+	case strings.Contains(text, "h264_vaapi"):
+		return VAAPI{}
+	case strings.Contains(text, "h264_qsv"):
+		return QSV{}
+	default:
+		return SoftwareX264{}
+	}
+}
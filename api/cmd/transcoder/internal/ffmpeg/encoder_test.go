@@ -0,0 +1,75 @@
+package ffmpeg_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/ffmpeg"
+)
+
+func TestSoftwareX264_VideoArgs_UsesLibx264(t *testing.T) {
+	rend := ffmpeg.Rendition{Name: "720p", Height: 720, VideoBitrate: "1500k"}
+	args := strings.Join(ffmpeg.SoftwareX264{}.VideoArgs(rend, 0), " ")
+
+	if !strings.Contains(args, "libx264") || !strings.Contains(args, "1500k") {
+		t.Errorf("args = %q, want libx264 and bitrate", args)
+	}
+}
+
+func TestSoftwareX264_InputArgs_Empty(t *testing.T) {
+	enc := ffmpeg.SoftwareX264{}
+	if args := enc.InputArgs(); len(args) != 0 {
+		t.Errorf("InputArgs() = %v, want empty", args)
+	}
+}
+
+func TestNVENC_VideoArgs_UsesH264Nvenc(t *testing.T) {
+	rend := ffmpeg.Rendition{Name: "1080p", Height: 1080, VideoBitrate: "3000k"}
+	args := strings.Join(ffmpeg.NVENC{}.VideoArgs(rend, 0), " ")
+
+	if !strings.Contains(args, "h264_nvenc") || !strings.Contains(args, "p4") {
+		t.Errorf("args = %q, want h264_nvenc and preset p4", args)
+	}
+}
+
+func TestNVENC_InputArgs_RequestsCUDA(t *testing.T) {
+	args := strings.Join(ffmpeg.NVENC{}.InputArgs(), " ")
+	if !strings.Contains(args, "cuda") {
+		t.Errorf("InputArgs() = %q, want -hwaccel cuda", args)
+	}
+}
+
+func TestVAAPI_VideoArgs_UploadsToHardwareSurface(t *testing.T) {
+	rend := ffmpeg.Rendition{Name: "360p", Height: 360, VideoBitrate: "500k"}
+	args := strings.Join(ffmpeg.VAAPI{}.VideoArgs(rend, 0), " ")
+
+	if !strings.Contains(args, "h264_vaapi") || !strings.Contains(args, "scale_vaapi=-2:360") {
+		t.Errorf("args = %q, want h264_vaapi and scale_vaapi", args)
+	}
+}
+
+func TestQSV_VideoArgs_UsesH264Qsv(t *testing.T) {
+	rend := ffmpeg.Rendition{Name: "720p", Height: 720, VideoBitrate: "1500k"}
+	args := strings.Join(ffmpeg.QSV{}.VideoArgs(rend, 0), " ")
+
+	if !strings.Contains(args, "h264_qsv") {
+		t.Errorf("args = %q, want h264_qsv", args)
+	}
+}
+
+func TestEncoders_NameIdentifiesBackend(t *testing.T) {
+	cases := []struct {
+		enc  ffmpeg.Encoder
+		want string
+	}{
+		{ffmpeg.SoftwareX264{}, "x264"},
+		{ffmpeg.NVENC{}, "nvenc"},
+		{ffmpeg.VAAPI{}, "vaapi"},
+		{ffmpeg.QSV{}, "qsv"},
+	}
+	for _, c := range cases {
+		if got := c.enc.Name(); got != c.want {
+			t.Errorf("Name() = %q, want %q", got, c.want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// SourceInfo is the subset of ffprobe's output LadderFor needs to plan a
+// content-aware rendition ladder.
+type SourceInfo struct {
+	Width      int
+	Height     int
+	FrameRate  float64
+	Duration   float64
+	BitDepth   int
+	BitrateBps int
+}
+
+// AnalyzeSource runs ffprobe against path and parses its first video
+// stream's width, height, frame rate, and bit depth, plus duration and
+// average bitrate, into a SourceInfo for LadderFor to plan a ladder from.
+// Callers should treat a failure here as non-fatal and fall back to
+// DefaultRenditions(), since a missing/broken ffprobe binary shouldn't stop
+// an otherwise-transcodable video.
+func AnalyzeSource(ctx context.Context, path string) (*SourceInfo, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_streams",
+		"-show_format",
+		"-of", "json",
+		path,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("ffprobe exited with error: %w\nstderr:\n%s", err, exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+	return parseSourceInfo(out)
+}
+
+// ffprobeSourceOutput mirrors the fields of ffprobe's `-show_format
+// -show_streams -of json` output that parseSourceInfo needs.
+type ffprobeSourceOutput struct {
+	Streams []ffprobeSourceStream `json:"streams"`
+	Format  ffprobeSourceFormat   `json:"format"`
+}
+
+type ffprobeSourceStream struct {
+	CodecType        string `json:"codec_type"`
+	Width            int    `json:"width"`
+	Height           int    `json:"height"`
+	RFrameRate       string `json:"r_frame_rate"`
+	BitsPerRawSample string `json:"bits_per_raw_sample"`
+	BitRate          string `json:"bit_rate"`
+}
+
+type ffprobeSourceFormat struct {
+	Duration string `json:"duration"`
+	BitRate  string `json:"bit_rate"`
+}
+
+// parseSourceInfo decodes raw ffprobe JSON into a SourceInfo, taking the
+// first video stream found. The source's average bitrate prefers the
+// container-level figure (format.bit_rate) and falls back to the video
+// stream's own bit_rate when the container doesn't report one.
+func parseSourceInfo(raw []byte) (*SourceInfo, error) {
+	var out ffprobeSourceOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	info := &SourceInfo{
+		Duration:   parseFloatField(out.Format.Duration),
+		BitrateBps: parseIntField(out.Format.BitRate),
+	}
+	for _, s := range out.Streams {
+		if s.CodecType != "video" {
+			continue
+		}
+		info.Width = s.Width
+		info.Height = s.Height
+		info.FrameRate = parseFrameRate(s.RFrameRate)
+		info.BitDepth = parseIntField(s.BitsPerRawSample)
+		if info.BitrateBps == 0 {
+			info.BitrateBps = parseIntField(s.BitRate)
+		}
+		break
+	}
+	if info.Width == 0 || info.Height == 0 {
+		return nil, fmt.Errorf("no video stream found")
+	}
+	return info, nil
+}
+
+// parseFrameRate converts ffprobe's "30000/1001"-style r_frame_rate into a
+// float, returning 0 for values it can't parse.
+func parseFrameRate(s string) float64 {
+	num, den, ok := strings.Cut(s, "/")
+	if !ok {
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	}
+	n, errN := strconv.ParseFloat(num, 64)
+	d, errD := strconv.ParseFloat(den, 64)
+	if errN != nil || errD != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+func parseFloatField(s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func parseIntField(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// LadderRung is one candidate rendition in a LadderPolicy's rung table: the
+// rendition LadderFor produces at Height when the source's motion factor is
+// exactly 1.0 (see motionFactor).
+type LadderRung struct {
+	Name             string
+	Height           int
+	BaseVideoBitrate int
+	AudioBitrate     string
+}
+
+// LadderPolicy configures LadderFor's candidate rung table and redundancy
+// threshold.
+type LadderPolicy struct {
+	// Rungs is the candidate ladder, ordered by ascending Height. LadderFor
+	// only ever filters and rescales this table; it never invents rungs of
+	// its own.
+	Rungs []LadderRung
+	// MinBitrateDeltaFraction drops a rung whose scaled bitrate is within
+	// this fraction of the next-higher surviving rung's, since two
+	// renditions that close together give an ABR player no meaningfully
+	// different choice. Zero means DefaultLadderPolicy's 0.15 (15%).
+	MinBitrateDeltaFraction float64
+}
+
+// DefaultLadderPolicy returns the rung table LadderFor scales from absent an
+// explicit policy: the same heights and bitrates as DefaultRenditions(),
+// treated as the motion-factor-1.0 baseline.
+func DefaultLadderPolicy() LadderPolicy {
+	return LadderPolicy{
+		Rungs: []LadderRung{
+			{Name: "360p", Height: 360, BaseVideoBitrate: 500_000, AudioBitrate: "64k"},
+			{Name: "720p", Height: 720, BaseVideoBitrate: 1_500_000, AudioBitrate: "128k"},
+			{Name: "1080p", Height: 1080, BaseVideoBitrate: 3_000_000, AudioBitrate: "192k"},
+		},
+		MinBitrateDeltaFraction: 0.15,
+	}
+}
+
+// referenceBitsPerPixelFrame is the bits-per-pixel-per-frame ratio
+// DefaultLadderPolicy's BaseVideoBitrate figures were tuned for (H.264,
+// medium-motion 1080p30-ish content). motionFactor scales output bitrate up
+// or down relative to this baseline.
+const referenceBitsPerPixelFrame = 0.1
+
+// motionFactor estimates how much denser or sparser info's source encode is
+// than the reference content a LadderPolicy's base bitrates were tuned for:
+// a busy, high-motion source needs more bits per pixel to look clean than a
+// static talking-head source at the same resolution. The result is clamped
+// to [0.5, 2.0] so a missing or garbled source bitrate can't collapse the
+// ladder to near-zero, and an outlier can't blow past double the baseline.
+func motionFactor(info *SourceInfo) float64 {
+	if info.Width <= 0 || info.Height <= 0 || info.FrameRate <= 0 || info.BitrateBps <= 0 {
+		return 1.0
+	}
+	bitsPerPixelFrame := float64(info.BitrateBps) / (float64(info.Width) * float64(info.Height) * info.FrameRate)
+	factor := bitsPerPixelFrame / referenceBitsPerPixelFrame
+	switch {
+	case factor < 0.5:
+		return 0.5
+	case factor > 2.0:
+		return 2.0
+	default:
+		return factor
+	}
+}
+
+// LadderFor picks a content-aware rendition ladder for info under policy: it
+// caps candidate rungs at info's source height (never upscaling, except that
+// a source shorter than every rung still gets the lowest rung), scales
+// each surviving rung's video bitrate by info's motionFactor, and drops
+// rungs whose bitrate ends up within policy.MinBitrateDeltaFraction of the
+// next-higher surviving rung to avoid near-redundant renditions. Callers
+// should pass DefaultRenditions() instead when info is nil, e.g. because
+// AnalyzeSource failed.
+func LadderFor(info *SourceInfo, policy LadderPolicy) []Rendition {
+	if info == nil || info.Height <= 0 {
+		return DefaultRenditions()
+	}
+	if len(policy.Rungs) == 0 {
+		policy = DefaultLadderPolicy()
+	}
+	minDelta := policy.MinBitrateDeltaFraction
+	if minDelta <= 0 {
+		minDelta = DefaultLadderPolicy().MinBitrateDeltaFraction
+	}
+
+	factor := motionFactor(info)
+
+	candidates := make([]LadderRung, 0, len(policy.Rungs))
+	for _, rung := range policy.Rungs {
+		if rung.Height <= info.Height {
+			candidates = append(candidates, rung)
+		}
+	}
+	if len(candidates) == 0 {
+		// The source is shorter than every rung; keep the lowest anyway so
+		// there is always at least one rendition.
+		candidates = append(candidates, policy.Rungs[0])
+	}
+
+	scaled := make([]Rendition, len(candidates))
+	for i, rung := range candidates {
+		scaled[i] = Rendition{
+			Name:         rung.Name,
+			Height:       rung.Height,
+			VideoBitrate: formatBitrate(int(float64(rung.BaseVideoBitrate) * factor)),
+			AudioBitrate: rung.AudioBitrate,
+		}
+	}
+
+	return dropRedundantRungs(scaled, minDelta)
+}
+
+// dropRedundantRungs removes rungs whose video bitrate sits within minDelta
+// of the next-higher kept rung's, scanning from the top of the ladder down
+// since the highest rung is always worth keeping.
+func dropRedundantRungs(rungs []Rendition, minDelta float64) []Rendition {
+	if len(rungs) <= 1 {
+		return rungs
+	}
+	kept := []Rendition{rungs[len(rungs)-1]}
+	lastBps := parseBitrate(kept[0].VideoBitrate)
+	for i := len(rungs) - 2; i >= 0; i-- {
+		bps := parseBitrate(rungs[i].VideoBitrate)
+		if lastBps > 0 && float64(lastBps-bps)/float64(lastBps) < minDelta {
+			continue
+		}
+		kept = append(kept, rungs[i])
+		lastBps = bps
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return kept
+}
+
+// formatBitrate formats a bits-per-second value as FFmpeg's "<n>k" bitrate
+// string, rounding to the nearest kbps.
+func formatBitrate(bps int) string {
+	kbps := int(math.Round(float64(bps) / 1000))
+	if kbps < 1 {
+		kbps = 1
+	}
+	return strconv.Itoa(kbps) + "k"
+}
@@ -0,0 +1,88 @@
+package ffmpeg_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/ffmpeg"
+)
+
+// ── LadderFor ──────────────────────────────────────────────────────────────────
+
+func TestLadderFor_NilInfo_FallsBackToDefaultRenditions(t *testing.T) {
+	got := ffmpeg.LadderFor(nil, ffmpeg.DefaultLadderPolicy())
+	if !reflect.DeepEqual(got, ffmpeg.DefaultRenditions()) {
+		t.Errorf("LadderFor(nil, ...) = %+v, want DefaultRenditions()", got)
+	}
+}
+
+func TestLadderFor_CapsAtSourceHeight(t *testing.T) {
+	info := &ffmpeg.SourceInfo{Width: 854, Height: 480, FrameRate: 30, BitrateBps: 1_500_000}
+
+	got := ffmpeg.LadderFor(info, ffmpeg.DefaultLadderPolicy())
+
+	for _, rend := range got {
+		if rend.Height > info.Height {
+			t.Errorf("rendition %s height %d exceeds source height %d", rend.Name, rend.Height, info.Height)
+		}
+	}
+	if len(got) == 0 {
+		t.Fatal("LadderFor returned no renditions")
+	}
+}
+
+func TestLadderFor_ShorterThanEveryRung_KeepsLowestRung(t *testing.T) {
+	info := &ffmpeg.SourceInfo{Width: 160, Height: 90, FrameRate: 24, BitrateBps: 200_000}
+
+	got := ffmpeg.LadderFor(info, ffmpeg.DefaultLadderPolicy())
+
+	if len(got) != 1 {
+		t.Fatalf("len(got) = %d, want 1", len(got))
+	}
+	if got[0].Name != "360p" {
+		t.Errorf("got[0].Name = %q, want the lowest rung (360p)", got[0].Name)
+	}
+}
+
+func TestLadderFor_HighMotionSource_ScalesBitrateAboveBaseline(t *testing.T) {
+	// 1080p30 at a bitrate well above referenceBitsPerPixelFrame's baseline.
+	info := &ffmpeg.SourceInfo{Width: 1920, Height: 1080, FrameRate: 30, BitrateBps: 40_000_000}
+
+	got := ffmpeg.LadderFor(info, ffmpeg.DefaultLadderPolicy())
+
+	base := ffmpeg.DefaultRenditions()
+	baseBitrate := map[string]string{}
+	for _, rend := range base {
+		baseBitrate[rend.Name] = rend.VideoBitrate
+	}
+	for _, rend := range got {
+		if rend.VideoBitrate == baseBitrate[rend.Name] {
+			t.Errorf("rendition %s bitrate %s was not scaled above baseline %s for a high-motion source", rend.Name, rend.VideoBitrate, baseBitrate[rend.Name])
+		}
+	}
+}
+
+func TestLadderFor_DropsLowerRungWithinRedundancyThresholdOfHigherRung(t *testing.T) {
+	policy := ffmpeg.LadderPolicy{
+		Rungs: []ffmpeg.LadderRung{
+			{Name: "360p", Height: 360, BaseVideoBitrate: 500_000, AudioBitrate: "64k"},
+			{Name: "480p", Height: 480, BaseVideoBitrate: 520_000, AudioBitrate: "96k"}, // within 15% of 360p
+			{Name: "1080p", Height: 1080, BaseVideoBitrate: 3_000_000, AudioBitrate: "192k"},
+		},
+		MinBitrateDeltaFraction: 0.15,
+	}
+	info := &ffmpeg.SourceInfo{Width: 1920, Height: 1080, FrameRate: 30, BitrateBps: 3_000_000}
+
+	got := ffmpeg.LadderFor(info, policy)
+
+	names := make([]string, len(got))
+	for i, rend := range got {
+		names[i] = rend.Name
+	}
+	// 360p and 480p scale to near-identical bitrates here; the lower (360p)
+	// should be dropped in favor of keeping 480p at the same bandwidth.
+	want := []string{"480p", "1080p"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("ladder = %v, want %v", names, want)
+	}
+}
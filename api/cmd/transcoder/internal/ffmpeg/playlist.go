@@ -0,0 +1,74 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// WriteMasterPlaylist writes outputDir/index.m3u8 referencing each
+// rendition's own variant playlist. It replaces the master playlist FFmpeg
+// used to generate in a single multi-output invocation, now that each
+// rendition is transcoded by its own ffmpeg process. It is exported so a
+// resumed job can re-run it over the full rendition set after
+// Runner.EncodeRenditions only re-encoded the renditions that were missing.
+func WriteMasterPlaylist(outputDir string, renditions []Rendition) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, rend := range renditions {
+		width, height := Resolution(rend)
+		b.WriteString(fmt.Sprintf(
+			"#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=%q\n%s.m3u8\n",
+			Bandwidth(rend), width, height, rend.Name, rend.Name,
+		))
+	}
+
+	path := filepath.Join(outputDir, "index.m3u8")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("write master playlist: %w", err)
+	}
+	return nil
+}
+
+// Bandwidth estimates a rendition's combined video+audio bitrate in bits/sec
+// (e.g. "1500k" -> 1500000), used for both the HLS BANDWIDTH attribute and
+// DASH Representation @bandwidth.
+func Bandwidth(rend Rendition) int {
+	return parseBitrate(rend.VideoBitrate) + parseBitrate(rend.AudioBitrate)
+}
+
+// Resolution returns a width/height pair derived from the rendition's target
+// height and a 16:9 aspect ratio, since the source aspect ratio isn't known
+// at this layer.
+func Resolution(rend Rendition) (width, height int) {
+	width = rend.Height * 16 / 9
+	// Keep width even, as most encoders require even dimensions.
+	width -= width % 2
+	return width, rend.Height
+}
+
+// parseBitrate converts an FFmpeg bitrate string ("500k", "2m", "128000") to
+// an integer count of bits per second. It returns 0 for values it can't parse
+// rather than erroring, since BANDWIDTH is advisory metadata.
+func parseBitrate(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	multiplier := 1
+	switch suffix := s[len(s)-1]; suffix {
+	case 'k', 'K':
+		multiplier = 1000
+		s = s[:len(s)-1]
+	case 'm', 'M':
+		multiplier = 1000000
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n * multiplier
+}
@@ -0,0 +1,71 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TranscodeProgress is one snapshot parsed from FFmpeg's "-progress pipe:2"
+// output, emitted once per reporting interval (FFmpeg defaults to roughly
+// once a second).
+type TranscodeProgress struct {
+	// Frame is the number of video frames encoded so far.
+	Frame int64
+	// FPS is the current encoding rate in frames per second.
+	FPS float64
+	// Speed is the encoding speed relative to realtime (e.g. 2.5 means
+	// encoding 2.5x faster than the source plays back).
+	Speed float64
+	// OutTime is how far into the output the encode has progressed.
+	OutTime time.Duration
+	// Done is true for the final snapshot of a run ("progress=end").
+	Done bool
+}
+
+// parseProgressField updates cur from a single "key=value" line of FFmpeg's
+// -progress output and reports whether line was the block terminator
+// ("progress=continue" or "progress=end"), at which point cur is a complete
+// snapshot ready to report.
+func parseProgressField(line string, cur *TranscodeProgress) bool {
+	key, val, ok := strings.Cut(line, "=")
+	if !ok {
+		return false
+	}
+	val = strings.TrimSpace(val)
+	switch key {
+	case "frame":
+		cur.Frame, _ = strconv.ParseInt(val, 10, 64)
+	case "fps":
+		cur.FPS, _ = strconv.ParseFloat(val, 64)
+	case "speed":
+		cur.Speed, _ = strconv.ParseFloat(strings.TrimSuffix(val, "x"), 64)
+	case "out_time_ms":
+		// FFmpeg's out_time_ms field is actually microseconds despite the
+		// name (a long-standing quirk of the FFmpeg progress reporter).
+		us, _ := strconv.ParseInt(val, 10, 64)
+		cur.OutTime = time.Duration(us) * time.Microsecond
+	case "progress":
+		cur.Done = val == "end"
+		return true
+	}
+	return false
+}
+
+// scanProgress reads FFmpeg's "-progress pipe:2" key=value blocks from r and
+// calls onProgress once per complete block. It returns once r is exhausted
+// (the process has exited or closed the pipe).
+func scanProgress(r io.Reader, onProgress func(TranscodeProgress)) {
+	scanner := bufio.NewScanner(r)
+	var cur TranscodeProgress
+	for scanner.Scan() {
+		if parseProgressField(scanner.Text(), &cur) {
+			if onProgress != nil {
+				onProgress(cur)
+			}
+			cur = TranscodeProgress{}
+		}
+	}
+}
@@ -2,11 +2,40 @@
 package ffmpeg
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"math"
+	"os"
 	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
 )
 
+// envWorkerPoolSize names the env var overriding the default per-rendition
+// transcode concurrency (see Runner.PoolSize).
+const envWorkerPoolSize = "FFMPEG_WORKER_POOL_SIZE"
+
+// HLSSegmentSeconds is the target duration of each HLS/CMAF media segment,
+// passed to FFmpeg via -hls_time. It is also used to estimate how many
+// segments a rendition's output should contain (see ExpectedSegmentCount).
+const HLSSegmentSeconds = 6
+
+// ExpectedSegmentCount estimates how many media segments a rendition's
+// output should contain for a source of the given duration, rounding up
+// since FFmpeg emits a final short segment for any remainder. It is used to
+// detect a partially-uploaded rendition left behind by an interrupted job,
+// not to validate FFmpeg's actual segmenting decisions exactly.
+func ExpectedSegmentCount(sourceDuration time.Duration) int {
+	seconds := sourceDuration.Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+	return int(math.Ceil(seconds / HLSSegmentSeconds))
+}
+
 // Rendition describes a single HLS output stream.
 type Rendition struct {
 	// Name is used as the variant playlist filename (e.g. "360p").
@@ -28,12 +57,43 @@ func DefaultRenditions() []Rendition {
 	}
 }
 
+// FilterByMaxHeight returns the subset of renditions whose Height does not
+// exceed sourceHeight, preserving order. Encoding a rendition taller than the
+// source upscales it, wasting cycles and producing soft/artifacted output, so
+// callers should probe the source height and filter DefaultRenditions()
+// through this before transcoding. A non-positive sourceHeight (e.g. an
+// unprobed source) disables filtering and returns renditions unchanged.
+func FilterByMaxHeight(renditions []Rendition, sourceHeight int) []Rendition {
+	if sourceHeight <= 0 {
+		return renditions
+	}
+	filtered := make([]Rendition, 0, len(renditions))
+	for _, rend := range renditions {
+		if rend.Height <= sourceHeight {
+			filtered = append(filtered, rend)
+		}
+	}
+	return filtered
+}
+
 // CommandRunner abstracts exec.CommandContext so tests can inject a stub.
 type CommandRunner interface {
 	// Run executes name with args and returns any error.
 	Run(ctx context.Context, name string, args ...string) error
 }
 
+// ProgressReporter is an optional CommandRunner capability: implementations
+// that can stream FFmpeg's "-progress pipe:2" output implement this so
+// Runner.EncodeRenditions can report TranscodeProgress as an encode runs
+// instead of only learning the outcome once the process exits. Stub
+// CommandRunners used in tests need not implement it; Runner falls back to
+// plain Run when they don't.
+type ProgressReporter interface {
+	// RunWithProgress runs name with args like Run, calling onProgress once
+	// per progress block parsed from the process's stderr.
+	RunWithProgress(ctx context.Context, name string, args []string, onProgress func(TranscodeProgress)) error
+}
+
 // ExecCommandRunner is the real CommandRunner that shells out to the system.
 type ExecCommandRunner struct{}
 
@@ -47,10 +107,57 @@ func (ExecCommandRunner) Run(ctx context.Context, name string, args ...string) e
 	return nil
 }
 
+// RunWithProgress runs name with args, parsing FFmpeg's "-progress pipe:2"
+// blocks from stderr and calling onProgress as each one completes.
+// renditionArgs already appends "-progress pipe:2 -nostats" to every
+// rendition's FFmpeg invocation, so this works for any rendition encode
+// without special-casing.
+func (ExecCommandRunner) RunWithProgress(ctx context.Context, name string, args []string, onProgress func(TranscodeProgress)) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("open stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start ffmpeg: %w", err)
+	}
+
+	var captured bytes.Buffer
+	scanDone := make(chan struct{})
+	go func() {
+		defer close(scanDone)
+		scanProgress(io.TeeReader(stderr, &captured), onProgress)
+	}()
+	<-scanDone
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("ffmpeg exited with error: %w\noutput:\n%s", err, captured.String())
+	}
+	return nil
+}
+
 // Runner wraps FFmpeg commands for HLS transcoding and thumbnail extraction.
 type Runner struct {
 	// Cmd is the command executor; defaults to ExecCommandRunner{}.
 	Cmd CommandRunner
+	// PoolSize bounds how many renditions TranscodeHLS transcodes
+	// concurrently. Zero means read FFMPEG_WORKER_POOL_SIZE, falling back to
+	// runtime.NumCPU() if that is unset or invalid.
+	PoolSize int
+	// OnRenditionDone, if set, is called once for each rendition that
+	// transcodes successfully, in completion order. It may be called from
+	// multiple worker-pool goroutines concurrently and must be safe for
+	// concurrent use.
+	OnRenditionDone func(Rendition)
+	// OnProgress, if set and Cmd implements ProgressReporter, is called with
+	// each rendition's TranscodeProgress as it encodes. Like OnRenditionDone,
+	// it may be called from multiple worker-pool goroutines concurrently and
+	// must be safe for concurrent use.
+	OnProgress func(Rendition, TranscodeProgress)
+	// Encoder picks the video codec and filter graph each rendition is
+	// encoded with. Nil means SoftwareX264{}; callers wanting automatic
+	// hardware acceleration should set this to DetectEncoder(ctx)'s result.
+	Encoder Encoder
 }
 
 // NewRunner constructs a Runner with the real ExecCommandRunner.
@@ -58,43 +165,169 @@ func NewRunner() *Runner {
 	return &Runner{Cmd: ExecCommandRunner{}}
 }
 
-// TranscodeHLS runs FFmpeg to produce an adaptive HLS output from inputPath.
-// outputDir must exist; FFmpeg writes the master playlist as index.m3u8 and
-// variant playlists + segments under outputDir.
+// poolSize resolves the effective worker pool size for TranscodeHLS.
+func (r *Runner) poolSize() int {
+	if r.PoolSize > 0 {
+		return r.PoolSize
+	}
+	if v := os.Getenv(envWorkerPoolSize); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return runtime.NumCPU()
+}
+
+// encoder resolves the Encoder backend renditionArgs encodes with.
+func (r *Runner) encoder() Encoder {
+	if r.Encoder != nil {
+		return r.Encoder
+	}
+	return SoftwareX264{}
+}
+
+// TranscodeHLS produces a complete adaptive HLS output from inputPath: every
+// rendition is encoded via EncodeRenditions, then the master "index.m3u8" is
+// written referencing all of them.
 func (r *Runner) TranscodeHLS(ctx context.Context, inputPath, outputDir string, renditions []Rendition) error {
+	if err := r.EncodeRenditions(ctx, inputPath, outputDir, renditions); err != nil {
+		return err
+	}
+	return WriteMasterPlaylist(outputDir, renditions)
+}
+
+// EncodeRenditions transcodes inputPath into renditions' independent
+// FFmpeg-encoded HLS/CMAF outputs under outputDir, one invocation per
+// rendition dispatched through a bounded WorkerPool so multi-core instances
+// keep every core busy instead of running one multiplexed ffmpeg process.
+// outputDir must exist; each rendition writes "<name>.m3u8" plus its
+// segments under outputDir. Unlike TranscodeHLS, it does not write the
+// master playlist, so a resumed job can pass only the renditions still
+// missing and have the caller write the master playlist separately once
+// over the full rendition set (see WriteMasterPlaylist).
+//
+// If any rendition's transcode fails, the context passed to the remaining
+// in-flight renditions is cancelled (terminating their FFmpeg processes) and
+// the first error encountered is returned.
+func (r *Runner) EncodeRenditions(ctx context.Context, inputPath, outputDir string, renditions []Rendition) error {
 	if len(renditions) == 0 {
 		return fmt.Errorf("at least one rendition is required")
 	}
 
-	args := []string{"-y", "-i", inputPath}
+	pool := NewWorkerPool(r.poolSize(), len(renditions))
+	defer pool.Close()
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	// Build one output stream per rendition.
+	enc := r.encoder()
+	// failed holds the first rendition error in the order failures happen;
+	// the worker that stores it cancels the siblings right away.
+	failed := make(chan error, 1)
+	results := make([]<-chan Result, len(renditions))
 	for i, rend := range renditions {
-		args = append(args,
-			"-map", "0:v:0",
-			"-map", "0:a:0",
-			fmt.Sprintf("-c:v:%d", i), "libx264",
-			fmt.Sprintf("-b:v:%d", i), rend.VideoBitrate,
-			fmt.Sprintf("-vf:v:%d", i), fmt.Sprintf("scale=-2:%d", rend.Height),
-			fmt.Sprintf("-c:a:%d", i), "aac",
-			fmt.Sprintf("-b:a:%d", i), rend.AudioBitrate,
-		)
-	}
-
-	// HLS muxer settings.
+		rend := rend
+		rc, err := pool.Submit(Job{
+			Ctx:       jobCtx,
+			Rendition: rend,
+			Run: func(ctx context.Context) error {
+				args := renditionArgs(enc, inputPath, outputDir, rend)
+				var err error
+				if pr, ok := r.Cmd.(ProgressReporter); ok && r.OnProgress != nil {
+					err = pr.RunWithProgress(ctx, "ffmpeg", args, func(p TranscodeProgress) {
+						r.OnProgress(rend, p)
+					})
+				} else {
+					err = r.Cmd.Run(ctx, "ffmpeg", args...)
+				}
+				if err != nil {
+					select {
+					case failed <- fmt.Errorf("transcode rendition %s: %w", rend.Name, err):
+						cancel()
+					default:
+					}
+					return err
+				}
+				if r.OnRenditionDone != nil {
+					r.OnRenditionDone(rend)
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			cancel()
+			return fmt.Errorf("submit rendition %s: %w", rend.Name, err)
+		}
+		results[i] = rc
+	}
+
+	for _, rc := range results {
+		<-rc
+	}
+	select {
+	case err := <-failed:
+		return err
+	default:
+		return nil
+	}
+}
+
+// TranscodeHLSParallel is an alias for TranscodeHLS, kept for callers that
+// know the pipeline by the "parallel" name: every rendition here is already
+// dispatched as its own ffmpeg process through a bounded WorkerPool (see
+// EncodeRenditions), not multiplexed through one invocation, so there is no
+// separate parallel code path to maintain.
+func (r *Runner) TranscodeHLSParallel(ctx context.Context, inputPath, outputDir string, renditions []Rendition) error {
+	return r.TranscodeHLS(ctx, inputPath, outputDir, renditions)
+}
+
+// CMAFInitFilename returns the fragmented-MP4 initialization segment name
+// TranscodeHLS writes for rend, relative to its output directory. The DASH
+// manifest builder references this same file so HLS and DASH share media
+// segments instead of each format encoding its own copy.
+func CMAFInitFilename(rend Rendition) string {
+	return rend.Name + "_init.mp4"
+}
+
+// CMAFSegmentTemplate returns the DASH $Number$ segment-name template for
+// rend's media segments, matching the sequence TranscodeHLS writes via
+// -hls_segment_filename.
+func CMAFSegmentTemplate(rend Rendition) string {
+	return rend.Name + "_$Number%05d$.m4s"
+}
+
+// cmafSegmentFilename is CMAFSegmentTemplate's counterpart in FFmpeg's own
+// printf-style numbering syntax, used for -hls_segment_filename.
+func cmafSegmentFilename(outputDir string, rend Rendition) string {
+	return outputDir + "/" + rend.Name + "_%05d.m4s"
+}
+
+// renditionArgs builds the FFmpeg arguments for a single rendition's
+// independent HLS output, encoding video through enc (see Encoder). Segments
+// are written as fragmented MP4 (CMAF) so the same init/media segments can be
+// referenced by a sibling DASH manifest. "-progress pipe:2 -nostats" makes
+// FFmpeg emit machine-readable progress blocks on stderr instead of its
+// default human-readable stats line, which RunWithProgress parses into
+// TranscodeProgress values.
+func renditionArgs(enc Encoder, inputPath, outputDir string, rend Rendition) []string {
+	args := []string{"-y", "-nostats", "-progress", "pipe:2"}
+	args = append(args, enc.InputArgs()...)
+	args = append(args, "-i", inputPath)
+	args = append(args, enc.VideoArgs(rend, 0)...)
 	args = append(args,
+		"-map", "0:a:0",
+		"-c:a", "aac",
+		"-b:a", rend.AudioBitrate,
 		"-f", "hls",
-		"-hls_time", "6",
+		"-hls_time", strconv.Itoa(HLSSegmentSeconds),
 		"-hls_playlist_type", "vod",
 		"-hls_flags", "independent_segments",
-		"-hls_segment_type", "mpegts",
-		"-hls_segment_filename", outputDir+"/%v_%03d.ts",
-		"-master_pl_name", "index.m3u8",
-		"-var_stream_map", buildStreamMap(renditions),
-		outputDir+"/%v.m3u8",
+		"-hls_segment_type", "fmp4",
+		"-hls_fmp4_init_filename", CMAFInitFilename(rend),
+		"-hls_segment_filename", cmafSegmentFilename(outputDir, rend),
+		outputDir+"/"+rend.Name+".m3u8",
 	)
-
-	return r.Cmd.Run(ctx, "ffmpeg", args...)
+	return args
 }
 
 // ExtractThumbnail extracts a single frame at offset seconds from inputPath
@@ -110,16 +343,3 @@ func (r *Runner) ExtractThumbnail(ctx context.Context, inputPath, destPath strin
 	}
 	return r.Cmd.Run(ctx, "ffmpeg", args...)
 }
-
-// buildStreamMap produces the -var_stream_map value, e.g.:
-// "v:0,a:0 v:1,a:1 v:2,a:2"
-func buildStreamMap(renditions []Rendition) string {
-	result := ""
-	for i := range renditions {
-		if i > 0 {
-			result += " "
-		}
-		result += fmt.Sprintf("v:%d,a:%d,name:%s", i, i, renditions[i].Name)
-	}
-	return result
-}
@@ -3,8 +3,13 @@ package ffmpeg_test
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/ffmpeg"
 )
@@ -12,8 +17,9 @@ import (
 // ── stub CommandRunner ─────────────────────────────────────────────────────────
 
 type stubRunner struct {
-	err       error
-	calls     []call
+	mu    sync.Mutex
+	err   error
+	calls []call
 }
 
 type call struct {
@@ -22,10 +28,28 @@ type call struct {
 }
 
 func (s *stubRunner) Run(_ context.Context, name string, args ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.calls = append(s.calls, call{name: name, args: args})
 	return s.err
 }
 
+func (s *stubRunner) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.calls)
+}
+
+func (s *stubRunner) allArgs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var all []string
+	for _, c := range s.calls {
+		all = append(all, c.args...)
+	}
+	return all
+}
+
 // ── DefaultRenditions ─────────────────────────────────────────────────────────
 
 func TestDefaultRenditions_Count(t *testing.T) {
@@ -65,6 +89,64 @@ func TestDefaultRenditions_Heights(t *testing.T) {
 	}
 }
 
+// ── FilterByMaxHeight ─────────────────────────────────────────────────────────
+
+func TestFilterByMaxHeight_DropsTallerRenditions(t *testing.T) {
+	got := ffmpeg.FilterByMaxHeight(ffmpeg.DefaultRenditions(), 480)
+	if len(got) != 1 || got[0].Name != "360p" {
+		t.Errorf("got %v, want only 360p", got)
+	}
+}
+
+func TestFilterByMaxHeight_KeepsExactMatch(t *testing.T) {
+	got := ffmpeg.FilterByMaxHeight(ffmpeg.DefaultRenditions(), 720)
+	want := []string{"360p", "720p"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d renditions, want %d: %v", len(got), len(want), got)
+	}
+	for i, rend := range got {
+		if rend.Name != want[i] {
+			t.Errorf("rendition[%d].Name = %q, want %q", i, rend.Name, want[i])
+		}
+	}
+}
+
+func TestFilterByMaxHeight_ZeroHeight_ReturnsAllUnchanged(t *testing.T) {
+	got := ffmpeg.FilterByMaxHeight(ffmpeg.DefaultRenditions(), 0)
+	if len(got) != 3 {
+		t.Errorf("got %d renditions, want 3 (unfiltered)", len(got))
+	}
+}
+
+func TestFilterByMaxHeight_HeightAboveAll_ReturnsAllUnchanged(t *testing.T) {
+	got := ffmpeg.FilterByMaxHeight(ffmpeg.DefaultRenditions(), 4320)
+	if len(got) != 3 {
+		t.Errorf("got %d renditions, want 3", len(got))
+	}
+}
+
+// ── ExpectedSegmentCount ──────────────────────────────────────────────────────
+
+func TestExpectedSegmentCount_ExactMultiple(t *testing.T) {
+	got := ffmpeg.ExpectedSegmentCount(30 * time.Second)
+	if want := 5; got != want {
+		t.Errorf("ExpectedSegmentCount(30s) = %d, want %d", got, want)
+	}
+}
+
+func TestExpectedSegmentCount_RoundsUpForRemainder(t *testing.T) {
+	got := ffmpeg.ExpectedSegmentCount(31 * time.Second)
+	if want := 6; got != want {
+		t.Errorf("ExpectedSegmentCount(31s) = %d, want %d", got, want)
+	}
+}
+
+func TestExpectedSegmentCount_ZeroDuration_ReturnsZero(t *testing.T) {
+	if got := ffmpeg.ExpectedSegmentCount(0); got != 0 {
+		t.Errorf("ExpectedSegmentCount(0) = %d, want 0", got)
+	}
+}
+
 // ── NewRunner ─────────────────────────────────────────────────────────────────
 
 func TestNewRunner_NotNil(t *testing.T) {
@@ -76,106 +158,126 @@ func TestNewRunner_NotNil(t *testing.T) {
 
 // ── TranscodeHLS ──────────────────────────────────────────────────────────────
 
-func TestTranscodeHLS_CallsFFmpeg(t *testing.T) {
+func TestTranscodeHLS_CallsFFmpegOncePerRendition(t *testing.T) {
 	stub := &stubRunner{}
-	r := &ffmpeg.Runner{Cmd: stub}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 2}
+	renditions := ffmpeg.DefaultRenditions()
 
-	err := r.TranscodeHLS(context.Background(), "input.mp4", "/out", ffmpeg.DefaultRenditions())
+	err := r.TranscodeHLS(context.Background(), "input.mp4", t.TempDir(), renditions)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(stub.calls) != 1 {
-		t.Fatalf("expected 1 FFmpeg call, got %d", len(stub.calls))
+	if stub.callCount() != len(renditions) {
+		t.Fatalf("expected %d FFmpeg calls (one per rendition), got %d", len(renditions), stub.callCount())
 	}
-	if stub.calls[0].name != "ffmpeg" {
-		t.Errorf("command name = %q, want ffmpeg", stub.calls[0].name)
+	for _, c := range stub.calls {
+		if c.name != "ffmpeg" {
+			t.Errorf("command name = %q, want ffmpeg", c.name)
+		}
 	}
 }
 
 func TestTranscodeHLS_ArgsContainInputPath(t *testing.T) {
 	stub := &stubRunner{}
-	r := &ffmpeg.Runner{Cmd: stub}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1}
 
-	_ = r.TranscodeHLS(context.Background(), "/video/raw.mp4", "/hls", ffmpeg.DefaultRenditions())
+	_ = r.TranscodeHLS(context.Background(), "/video/raw.mp4", t.TempDir(), ffmpeg.DefaultRenditions())
 
-	args := stub.calls[0].args
 	found := false
-	for _, a := range args {
+	for _, a := range stub.allArgs() {
 		if a == "/video/raw.mp4" {
 			found = true
 			break
 		}
 	}
 	if !found {
-		t.Errorf("args do not contain input path: %v", args)
+		t.Errorf("args do not contain input path: %v", stub.allArgs())
 	}
 }
 
 func TestTranscodeHLS_ArgsContainOutputDir(t *testing.T) {
 	stub := &stubRunner{}
-	r := &ffmpeg.Runner{Cmd: stub}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1}
+	outDir := t.TempDir()
 
-	_ = r.TranscodeHLS(context.Background(), "in.mp4", "/hls/out", ffmpeg.DefaultRenditions())
+	_ = r.TranscodeHLS(context.Background(), "in.mp4", outDir, ffmpeg.DefaultRenditions())
 
-	args := stub.calls[0].args
 	found := false
-	for _, a := range args {
-		if strings.Contains(a, "/hls/out") {
+	for _, a := range stub.allArgs() {
+		if strings.Contains(a, outDir) {
 			found = true
 			break
 		}
 	}
 	if !found {
-		t.Errorf("args do not reference output dir: %v", args)
+		t.Errorf("args do not reference output dir: %v", stub.allArgs())
 	}
 }
 
-func TestTranscodeHLS_ArgsContainMasterPlaylist(t *testing.T) {
+func TestTranscodeHLS_WritesMasterPlaylist(t *testing.T) {
 	stub := &stubRunner{}
-	r := &ffmpeg.Runner{Cmd: stub}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 2}
+	outDir := t.TempDir()
 
-	_ = r.TranscodeHLS(context.Background(), "in.mp4", "/out", ffmpeg.DefaultRenditions())
+	if err := r.TranscodeHLS(context.Background(), "in.mp4", outDir, ffmpeg.DefaultRenditions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	args := stub.calls[0].args
-	found := false
-	for _, a := range args {
-		if a == "index.m3u8" {
-			found = true
-			break
-		}
+	data, err := os.ReadFile(filepath.Join(outDir, "index.m3u8"))
+	if err != nil {
+		t.Fatalf("expected master playlist to be written: %v", err)
 	}
-	if !found {
-		t.Errorf("args do not include master playlist name 'index.m3u8': %v", args)
+	content := string(data)
+	if !strings.HasPrefix(content, "#EXTM3U") {
+		t.Errorf("master playlist missing #EXTM3U header: %q", content)
+	}
+	for _, rend := range ffmpeg.DefaultRenditions() {
+		if !strings.Contains(content, rend.Name+".m3u8") {
+			t.Errorf("master playlist missing reference to %s.m3u8: %q", rend.Name, content)
+		}
 	}
 }
 
 func TestTranscodeHLS_ArgsContainHLSFormat(t *testing.T) {
 	stub := &stubRunner{}
-	r := &ffmpeg.Runner{Cmd: stub}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1}
 
-	_ = r.TranscodeHLS(context.Background(), "in.mp4", "/out", ffmpeg.DefaultRenditions())
+	_ = r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), ffmpeg.DefaultRenditions())
 
-	args := stub.calls[0].args
 	found := false
-	for _, a := range args {
+	for _, a := range stub.allArgs() {
 		if a == "hls" {
 			found = true
 			break
 		}
 	}
 	if !found {
-		t.Errorf("args do not contain '-f hls': %v", args)
+		t.Errorf("args do not contain '-f hls': %v", stub.allArgs())
+	}
+}
+
+func TestTranscodeHLS_ArgsContainProgressFlags(t *testing.T) {
+	stub := &stubRunner{}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1}
+
+	_ = r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), ffmpeg.DefaultRenditions())
+
+	argsStr := strings.Join(stub.allArgs(), " ")
+	for _, want := range []string{"-nostats", "-progress", "pipe:2"} {
+		if !strings.Contains(argsStr, want) {
+			t.Errorf("args missing %q: %s", want, argsStr)
+		}
 	}
 }
 
 func TestTranscodeHLS_ArgsContainAllRenditionBitrates(t *testing.T) {
 	stub := &stubRunner{}
-	r := &ffmpeg.Runner{Cmd: stub}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1}
 	renditions := ffmpeg.DefaultRenditions()
 
-	_ = r.TranscodeHLS(context.Background(), "in.mp4", "/out", renditions)
+	_ = r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), renditions)
 
-	argsStr := strings.Join(stub.calls[0].args, " ")
+	argsStr := strings.Join(stub.allArgs(), " ")
 	for _, rend := range renditions {
 		if !strings.Contains(argsStr, rend.VideoBitrate) {
 			t.Errorf("args missing video bitrate %q: %s", rend.VideoBitrate, argsStr)
@@ -183,29 +285,266 @@ func TestTranscodeHLS_ArgsContainAllRenditionBitrates(t *testing.T) {
 	}
 }
 
+func TestTranscodeHLS_DefaultEncoder_UsesLibx264(t *testing.T) {
+	stub := &stubRunner{}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1}
+
+	_ = r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), ffmpeg.DefaultRenditions())
+
+	argsStr := strings.Join(stub.allArgs(), " ")
+	if !strings.Contains(argsStr, "libx264") {
+		t.Errorf("args missing libx264: %s", argsStr)
+	}
+}
+
+func TestTranscodeHLS_ConfiguredEncoder_OverridesDefault(t *testing.T) {
+	stub := &stubRunner{}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1, Encoder: ffmpeg.NVENC{}}
+
+	_ = r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), ffmpeg.DefaultRenditions())
+
+	argsStr := strings.Join(stub.allArgs(), " ")
+	if !strings.Contains(argsStr, "h264_nvenc") || strings.Contains(argsStr, "libx264") {
+		t.Errorf("args = %q, want h264_nvenc only", argsStr)
+	}
+}
+
+func TestTranscodeHLSParallel_SameBehaviorAsTranscodeHLS(t *testing.T) {
+	stub := &stubRunner{}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 2}
+	renditions := ffmpeg.DefaultRenditions()
+
+	if err := r.TranscodeHLSParallel(context.Background(), "in.mp4", t.TempDir(), renditions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.callCount() != len(renditions) {
+		t.Errorf("callCount = %d, want one ffmpeg invocation per rendition (%d)", stub.callCount(), len(renditions))
+	}
+}
+
 func TestTranscodeHLS_EmptyRenditions_ReturnsError(t *testing.T) {
 	stub := &stubRunner{}
 	r := &ffmpeg.Runner{Cmd: stub}
 
-	err := r.TranscodeHLS(context.Background(), "in.mp4", "/out", nil)
+	err := r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), nil)
 	if err == nil {
 		t.Fatal("expected error for empty renditions")
 	}
-	if len(stub.calls) != 0 {
+	if stub.callCount() != 0 {
 		t.Error("FFmpeg must not be called when renditions is empty")
 	}
 }
 
 func TestTranscodeHLS_FFmpegError_Propagated(t *testing.T) {
 	stub := &stubRunner{err: errors.New("ffmpeg failed")}
-	r := &ffmpeg.Runner{Cmd: stub}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1}
 
-	err := r.TranscodeHLS(context.Background(), "in.mp4", "/out", ffmpeg.DefaultRenditions())
+	err := r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), ffmpeg.DefaultRenditions())
 	if err == nil {
 		t.Fatal("expected error to be propagated")
 	}
 }
 
+func TestTranscodeHLS_OneRenditionFailing_ReturnsErrorAndSkipsMasterPlaylist(t *testing.T) {
+	outDir := t.TempDir()
+	stub := &stubRunner{err: errors.New("encode failed")}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 3}
+
+	err := r.TranscodeHLS(context.Background(), "in.mp4", outDir, ffmpeg.DefaultRenditions())
+	if err == nil {
+		t.Fatal("expected error when a rendition fails")
+	}
+	if _, statErr := os.Stat(filepath.Join(outDir, "index.m3u8")); statErr == nil {
+		t.Error("master playlist must not be written when a rendition fails")
+	}
+}
+
+func TestTranscodeHLS_OnRenditionDone_CalledOncePerSuccessfulRendition(t *testing.T) {
+	stub := &stubRunner{}
+	renditions := ffmpeg.DefaultRenditions()
+
+	var mu sync.Mutex
+	var done []string
+	r := &ffmpeg.Runner{
+		Cmd:      stub,
+		PoolSize: 3,
+		OnRenditionDone: func(rend ffmpeg.Rendition) {
+			mu.Lock()
+			defer mu.Unlock()
+			done = append(done, rend.Name)
+		},
+	}
+
+	if err := r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), renditions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(done) != len(renditions) {
+		t.Fatalf("OnRenditionDone called %d times, want %d: %v", len(done), len(renditions), done)
+	}
+}
+
+func TestTranscodeHLS_OnRenditionDone_NotCalledForFailedRendition(t *testing.T) {
+	stub := &stubRunner{err: errors.New("encode failed")}
+	var calls int32
+	r := &ffmpeg.Runner{
+		Cmd:             stub,
+		PoolSize:        3,
+		OnRenditionDone: func(ffmpeg.Rendition) { atomic.AddInt32(&calls, 1) },
+	}
+
+	_ = r.TranscodeHLS(context.Background(), "in.mp4", t.TempDir(), ffmpeg.DefaultRenditions())
+
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("OnRenditionDone called %d times, want 0 when every rendition fails", calls)
+	}
+}
+
+// ── EncodeRenditions / WriteMasterPlaylist ────────────────────────────────────
+
+func TestEncodeRenditions_DoesNotWriteMasterPlaylist(t *testing.T) {
+	outDir := t.TempDir()
+	stub := &stubRunner{}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 2}
+
+	if err := r.EncodeRenditions(context.Background(), "in.mp4", outDir, ffmpeg.DefaultRenditions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "index.m3u8")); err == nil {
+		t.Error("EncodeRenditions must not write the master playlist; that's WriteMasterPlaylist's job")
+	}
+}
+
+func TestEncodeRenditions_OnlyEncodesPassedRenditions(t *testing.T) {
+	stub := &stubRunner{}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 2}
+
+	pending := []ffmpeg.Rendition{{Name: "720p", Height: 720, VideoBitrate: "1500k", AudioBitrate: "128k"}}
+	if err := r.EncodeRenditions(context.Background(), "in.mp4", t.TempDir(), pending); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := stub.callCount(); got != 1 {
+		t.Errorf("ffmpeg invocation count = %d, want 1 for a single pending rendition", got)
+	}
+}
+
+func TestEncodeRenditions_EmptyRenditions_ReturnsError(t *testing.T) {
+	r := &ffmpeg.Runner{Cmd: &stubRunner{}}
+
+	if err := r.EncodeRenditions(context.Background(), "in.mp4", t.TempDir(), nil); err == nil {
+		t.Fatal("expected error for empty renditions")
+	}
+}
+
+func TestWriteMasterPlaylist_ReferencesFullRenditionSetAfterPartialEncode(t *testing.T) {
+	outDir := t.TempDir()
+	stub := &stubRunner{}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1}
+
+	all := ffmpeg.DefaultRenditions()
+	pending := all[len(all)-1:] // pretend every other rendition was already done
+
+	if err := r.EncodeRenditions(context.Background(), "in.mp4", outDir, pending); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ffmpeg.WriteMasterPlaylist(outDir, all); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, "index.m3u8"))
+	if err != nil {
+		t.Fatalf("read master playlist: %v", err)
+	}
+	for _, rend := range all {
+		if !strings.Contains(string(data), rend.Name+".m3u8") {
+			t.Errorf("master playlist missing reference to rendition %q not re-encoded this run:\n%s", rend.Name, data)
+		}
+	}
+}
+
+// ── OnProgress ─────────────────────────────────────────────────────────────────
+
+// stubProgressRunner is a CommandRunner that also implements ProgressReporter,
+// reporting a fixed sequence of progress snapshots instead of actually
+// shelling out, so tests can exercise Runner's ProgressReporter wiring
+// without a real ffmpeg binary.
+type stubProgressRunner struct {
+	snapshots []ffmpeg.TranscodeProgress
+	err       error
+}
+
+func (s *stubProgressRunner) Run(ctx context.Context, name string, args ...string) error {
+	return s.err
+}
+
+func (s *stubProgressRunner) RunWithProgress(ctx context.Context, name string, args []string, onProgress func(ffmpeg.TranscodeProgress)) error {
+	for _, snap := range s.snapshots {
+		onProgress(snap)
+	}
+	return s.err
+}
+
+func TestEncodeRenditions_OnProgress_ReportedForProgressReporterCmd(t *testing.T) {
+	stub := &stubProgressRunner{snapshots: []ffmpeg.TranscodeProgress{
+		{Frame: 10, Done: false},
+		{Frame: 20, Done: true},
+	}}
+
+	var mu sync.Mutex
+	var reported []ffmpeg.TranscodeProgress
+	r := &ffmpeg.Runner{
+		Cmd:      stub,
+		PoolSize: 1,
+		OnProgress: func(_ ffmpeg.Rendition, p ffmpeg.TranscodeProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, p)
+		},
+	}
+
+	pending := []ffmpeg.Rendition{{Name: "720p", Height: 720, VideoBitrate: "1500k", AudioBitrate: "128k"}}
+	if err := r.EncodeRenditions(context.Background(), "in.mp4", t.TempDir(), pending); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 2 {
+		t.Fatalf("OnProgress called %d times, want 2: %v", len(reported), reported)
+	}
+	if !reported[1].Done {
+		t.Error("final reported snapshot should have Done=true")
+	}
+}
+
+func TestEncodeRenditions_OnProgressUnset_DoesNotUseProgressReporter(t *testing.T) {
+	stub := &stubProgressRunner{snapshots: []ffmpeg.TranscodeProgress{{Frame: 1}}}
+	r := &ffmpeg.Runner{Cmd: stub, PoolSize: 1} // OnProgress left nil
+
+	if err := r.EncodeRenditions(context.Background(), "in.mp4", t.TempDir(), ffmpeg.DefaultRenditions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Nothing to assert beyond "did not panic calling a nil OnProgress" --
+	// RunWithProgress is simply never invoked when OnProgress is nil.
+}
+
+func TestEncodeRenditions_PlainCommandRunner_IgnoresOnProgress(t *testing.T) {
+	stub := &stubRunner{} // does not implement ProgressReporter
+	r := &ffmpeg.Runner{
+		Cmd:      stub,
+		PoolSize: 1,
+		OnProgress: func(ffmpeg.Rendition, ffmpeg.TranscodeProgress) {
+			t.Error("OnProgress must not be called for a non-ProgressReporter Cmd")
+		},
+	}
+
+	if err := r.EncodeRenditions(context.Background(), "in.mp4", t.TempDir(), ffmpeg.DefaultRenditions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 // ── ExtractThumbnail ──────────────────────────────────────────────────────────
 
 func TestExtractThumbnail_CallsFFmpeg(t *testing.T) {
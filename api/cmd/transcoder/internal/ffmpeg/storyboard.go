@@ -0,0 +1,192 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// storyboardSpritePattern is the FFmpeg output filename pattern for
+// ExtractStoryboard's sprite sheets; "%03d" supports up to 1000 sheets,
+// comfortably more than any realistic video needs at the default interval.
+const storyboardSpritePattern = "storyboard_%03d.jpg"
+
+// StoryboardOptions configures ExtractStoryboard's sprite-sheet generation.
+// A zero value is usable: StoryboardDefaults() fills in every unset field.
+type StoryboardOptions struct {
+	// IntervalSeconds is how often a frame is captured for the storyboard.
+	// Zero means StoryboardDefaults()'s 10 seconds.
+	IntervalSeconds int
+	// TileWidth and TileHeight are a single thumbnail's pixel size within a
+	// sprite sheet. Zero means StoryboardDefaults()'s 160x90.
+	TileWidth  int
+	TileHeight int
+	// Columns and Rows size the sprite sheet grid. Zero means
+	// StoryboardDefaults()'s 10x10 (100 thumbnails per sheet).
+	Columns int
+	Rows    int
+	// SourceDuration is the probed source duration. It is used to compute
+	// how many capture intervals the video actually has, so the WebVTT
+	// sidecar doesn't emit a cue for a padding frame in a partially-filled
+	// final sprite sheet. Leaving it zero treats every sprite sheet as full.
+	SourceDuration time.Duration
+}
+
+// withDefaults returns o with every zero field replaced by
+// StoryboardDefaults()'s value.
+func (o StoryboardOptions) withDefaults() StoryboardOptions {
+	d := StoryboardDefaults()
+	if o.IntervalSeconds == 0 {
+		o.IntervalSeconds = d.IntervalSeconds
+	}
+	if o.TileWidth == 0 {
+		o.TileWidth = d.TileWidth
+	}
+	if o.TileHeight == 0 {
+		o.TileHeight = d.TileHeight
+	}
+	if o.Columns == 0 {
+		o.Columns = d.Columns
+	}
+	if o.Rows == 0 {
+		o.Rows = d.Rows
+	}
+	return o
+}
+
+// StoryboardDefaults returns the options ExtractStoryboard uses for any
+// field left zero in the caller's StoryboardOptions: a frame every 10
+// seconds, 160x90 tiles, arranged 10x10 per sprite sheet.
+func StoryboardDefaults() StoryboardOptions {
+	return StoryboardOptions{
+		IntervalSeconds: 10,
+		TileWidth:       160,
+		TileHeight:      90,
+		Columns:         10,
+		Rows:            10,
+	}
+}
+
+// StoryboardIndex describes a generated storyboard: one or more sprite
+// sheets tiling thumbnails captured at a fixed interval, plus the WebVTT
+// sidecar mapping timecodes to sprite regions for a player's hover-scrub
+// preview.
+type StoryboardIndex struct {
+	// SpriteFiles lists the sprite sheet filenames, relative to the
+	// ExtractStoryboard outDir, in playback order.
+	SpriteFiles []string
+	// VTTFile is the WebVTT sidecar filename, relative to outDir.
+	VTTFile string
+	// IntervalSeconds, TileWidth, TileHeight, Columns, and Rows mirror the
+	// StoryboardOptions (after defaulting) the storyboard was generated
+	// with, so callers don't need to thread the options through separately
+	// to interpret SpriteFiles/VTTFile.
+	IntervalSeconds int
+	TileWidth       int
+	TileHeight      int
+	Columns         int
+	Rows            int
+}
+
+// ExtractStoryboard generates one or more sprite sheets tiling thumbnails
+// captured from inputPath at opts.IntervalSeconds, plus a WebVTT sidecar
+// ("storyboard.vtt") whose cues map timecodes to "<sprite>.jpg#xywh=…"
+// regions, for a player's hover-scrub preview. outDir must exist.
+func (r *Runner) ExtractStoryboard(ctx context.Context, inputPath, outDir string, opts StoryboardOptions) (*StoryboardIndex, error) {
+	opts = opts.withDefaults()
+
+	args := []string{
+		"-y", "-i", inputPath,
+		"-vf", fmt.Sprintf("fps=1/%d,scale=%d:%d,tile=%dx%d", opts.IntervalSeconds, opts.TileWidth, opts.TileHeight, opts.Columns, opts.Rows),
+		"-vsync", "vfr",
+		filepath.Join(outDir, storyboardSpritePattern),
+	}
+	if err := r.Cmd.Run(ctx, "ffmpeg", args...); err != nil {
+		return nil, fmt.Errorf("extract storyboard: %w", err)
+	}
+
+	spriteFiles, err := storyboardSpriteFiles(outDir)
+	if err != nil {
+		return nil, fmt.Errorf("list storyboard sprites: %w", err)
+	}
+
+	index := &StoryboardIndex{
+		SpriteFiles:     spriteFiles,
+		VTTFile:         "storyboard.vtt",
+		IntervalSeconds: opts.IntervalSeconds,
+		TileWidth:       opts.TileWidth,
+		TileHeight:      opts.TileHeight,
+		Columns:         opts.Columns,
+		Rows:            opts.Rows,
+	}
+
+	if err := writeStoryboardVTT(filepath.Join(outDir, index.VTTFile), index, opts); err != nil {
+		return nil, fmt.Errorf("write storyboard vtt: %w", err)
+	}
+	return index, nil
+}
+
+// storyboardSpriteFiles returns the sprite sheet filenames FFmpeg wrote to
+// outDir, relative to outDir and in playback order.
+func storyboardSpriteFiles(outDir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(outDir, "storyboard_*.jpg"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	files := make([]string, len(matches))
+	for i, m := range matches {
+		files[i] = filepath.Base(m)
+	}
+	return files, nil
+}
+
+// writeStoryboardVTT writes a WebVTT file to path with one cue per capture
+// interval, each pointing at its tile's region within the sprite sheet that
+// holds it. If opts.SourceDuration is set, cues stop once they would cover a
+// padding frame in a partially-filled final sprite sheet; otherwise every
+// sprite sheet is treated as fully populated.
+func writeStoryboardVTT(path string, index *StoryboardIndex, opts StoryboardOptions) error {
+	tilesPerSheet := index.Columns * index.Rows
+	totalIntervals := len(index.SpriteFiles) * tilesPerSheet
+	if opts.SourceDuration > 0 {
+		n := int(math.Ceil(opts.SourceDuration.Seconds() / float64(opts.IntervalSeconds)))
+		if n < totalIntervals {
+			totalIntervals = n
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for i := 0; i < totalIntervals; i++ {
+		sheet := index.SpriteFiles[i/tilesPerSheet]
+		tile := i % tilesPerSheet
+		x := (tile % index.Columns) * index.TileWidth
+		y := (tile / index.Columns) * index.TileHeight
+
+		start := time.Duration(i) * time.Duration(opts.IntervalSeconds) * time.Second
+		end := start + time.Duration(opts.IntervalSeconds)*time.Second
+
+		fmt.Fprintf(&b, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n",
+			formatVTTTimestamp(start), formatVTTTimestamp(end), sheet, x, y, index.TileWidth, index.TileHeight)
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// formatVTTTimestamp formats d as a WebVTT "HH:MM:SS.mmm" cue timestamp.
+func formatVTTTimestamp(d time.Duration) string {
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	d -= s * time.Second
+	ms := d / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
@@ -0,0 +1,162 @@
+package ffmpeg_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/ffmpeg"
+)
+
+// spriteWritingRunner is a CommandRunner that, instead of shelling out to a
+// real ffmpeg, writes spriteCount fake sprite sheet files into the outDir
+// ExtractStoryboard passed it (the last arg), so ExtractStoryboard's
+// post-Run sprite glob has something to find.
+type spriteWritingRunner struct {
+	spriteCount int
+	err         error
+	calls       []call
+}
+
+func (s *spriteWritingRunner) Run(_ context.Context, name string, args ...string) error {
+	s.calls = append(s.calls, call{name: name, args: args})
+	if s.err != nil {
+		return s.err
+	}
+	outDir := filepath.Dir(args[len(args)-1])
+	for i := 0; i < s.spriteCount; i++ {
+		name := filepath.Join(outDir, fmt.Sprintf("storyboard_%03d.jpg", i))
+		if err := os.WriteFile(name, []byte("jpeg"), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExtractStoryboard_CallsFFmpegOnce(t *testing.T) {
+	stub := &spriteWritingRunner{spriteCount: 1}
+	r := &ffmpeg.Runner{Cmd: stub}
+
+	_, err := r.ExtractStoryboard(context.Background(), "input.mp4", t.TempDir(), ffmpeg.StoryboardOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stub.calls) != 1 {
+		t.Fatalf("expected 1 FFmpeg call, got %d", len(stub.calls))
+	}
+}
+
+func TestExtractStoryboard_ArgsContainInputPathAndInterval(t *testing.T) {
+	stub := &spriteWritingRunner{spriteCount: 1}
+	r := &ffmpeg.Runner{Cmd: stub}
+
+	_, _ = r.ExtractStoryboard(context.Background(), "/video/raw.mp4", t.TempDir(), ffmpeg.StoryboardOptions{IntervalSeconds: 5})
+
+	argsStr := strings.Join(stub.calls[0].args, " ")
+	if !strings.Contains(argsStr, "/video/raw.mp4") {
+		t.Errorf("args missing input path: %s", argsStr)
+	}
+	if !strings.Contains(argsStr, "fps=1/5") {
+		t.Errorf("args missing fps filter for 5s interval: %s", argsStr)
+	}
+}
+
+func TestExtractStoryboard_DefaultOptions_UsedWhenZero(t *testing.T) {
+	stub := &spriteWritingRunner{spriteCount: 1}
+	r := &ffmpeg.Runner{Cmd: stub}
+
+	index, err := r.ExtractStoryboard(context.Background(), "in.mp4", t.TempDir(), ffmpeg.StoryboardOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := ffmpeg.StoryboardDefaults()
+	if index.IntervalSeconds != d.IntervalSeconds || index.TileWidth != d.TileWidth || index.TileHeight != d.TileHeight || index.Columns != d.Columns || index.Rows != d.Rows {
+		t.Errorf("index = %+v, want defaults %+v", index, d)
+	}
+}
+
+func TestExtractStoryboard_IndexListsSpriteFilesInOrder(t *testing.T) {
+	stub := &spriteWritingRunner{spriteCount: 3}
+	r := &ffmpeg.Runner{Cmd: stub}
+
+	index, err := r.ExtractStoryboard(context.Background(), "in.mp4", t.TempDir(), ffmpeg.StoryboardOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"storyboard_000.jpg", "storyboard_001.jpg", "storyboard_002.jpg"}
+	if len(index.SpriteFiles) != len(want) {
+		t.Fatalf("SpriteFiles = %v, want %v", index.SpriteFiles, want)
+	}
+	for i, f := range want {
+		if index.SpriteFiles[i] != f {
+			t.Errorf("SpriteFiles[%d] = %q, want %q", i, index.SpriteFiles[i], f)
+		}
+	}
+}
+
+func TestExtractStoryboard_WritesVTTFile(t *testing.T) {
+	stub := &spriteWritingRunner{spriteCount: 1}
+	r := &ffmpeg.Runner{Cmd: stub}
+	outDir := t.TempDir()
+
+	index, err := r.ExtractStoryboard(context.Background(), "in.mp4", outDir, ffmpeg.StoryboardOptions{IntervalSeconds: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, index.VTTFile))
+	if err != nil {
+		t.Fatalf("expected VTT file to be written: %v", err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "WEBVTT\n") {
+		t.Errorf("VTT missing WEBVTT header: %q", content)
+	}
+	if !strings.Contains(content, "storyboard_000.jpg#xywh=0,0,160,90") {
+		t.Errorf("VTT missing first tile's xywh region: %q", content)
+	}
+}
+
+func TestExtractStoryboard_SourceDuration_TruncatesPaddingCues(t *testing.T) {
+	stub := &spriteWritingRunner{spriteCount: 1}
+	r := &ffmpeg.Runner{Cmd: stub}
+	outDir := t.TempDir()
+
+	// 100 tiles per sheet by default; a 25s source at a 10s interval should
+	// only get 3 cues (0-10s, 10-20s, 20-30s), not the full 100-tile sheet.
+	index, err := r.ExtractStoryboard(context.Background(), "in.mp4", outDir, ffmpeg.StoryboardOptions{SourceDuration: 25 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, index.VTTFile))
+	if err != nil {
+		t.Fatalf("read VTT: %v", err)
+	}
+	cueCount := strings.Count(string(data), "-->")
+	if cueCount != 3 {
+		t.Errorf("cue count = %d, want 3 for a 25s source at a 10s interval", cueCount)
+	}
+}
+
+func TestExtractStoryboard_FFmpegError_Propagated(t *testing.T) {
+	stub := &spriteWritingRunner{err: errors.New("ffmpeg failed")}
+	r := &ffmpeg.Runner{Cmd: stub}
+
+	_, err := r.ExtractStoryboard(context.Background(), "in.mp4", t.TempDir(), ffmpeg.StoryboardOptions{})
+	if err == nil {
+		t.Fatal("expected error to be propagated")
+	}
+}
+
+func TestStoryboardDefaults(t *testing.T) {
+	d := ffmpeg.StoryboardDefaults()
+	if d.IntervalSeconds != 10 || d.TileWidth != 160 || d.TileHeight != 90 || d.Columns != 10 || d.Rows != 10 {
+		t.Errorf("StoryboardDefaults() = %+v, want {10 160 90 10 10}", d)
+	}
+}
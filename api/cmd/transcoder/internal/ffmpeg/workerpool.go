@@ -0,0 +1,96 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+)
+
+// defaultMaxQueueDepth bounds how many pending jobs a WorkerPool will buffer
+// before Submit starts rejecting work, so a stall never grows goroutines or
+// memory unboundedly.
+const defaultMaxQueueDepth = 64
+
+// Job is a unit of work dispatched to a WorkerPool: an independent ffmpeg
+// invocation for one Rendition. Ctx governs the invocation's lifetime; when it
+// is cancelled the worker running Job must stop the in-flight process
+// (ExecCommandRunner does this via exec.CommandContext).
+type Job struct {
+	Ctx       context.Context
+	Rendition Rendition
+	Run       func(ctx context.Context) error
+}
+
+// Result is the outcome of one Job.
+type Result struct {
+	Rendition Rendition
+	Err       error
+}
+
+// WorkerPool runs Jobs with bounded concurrency across N goroutines, each
+// executing one ffmpeg process at a time. It exists so TranscodeHLS can
+// dispatch one ffmpeg invocation per Rendition instead of multiplexing every
+// rendition through a single process, keeping multi-core Cloud Run instances
+// busy.
+type WorkerPool struct {
+	queue chan queuedJob
+	done  chan struct{}
+}
+
+type queuedJob struct {
+	job    Job
+	result chan Result
+}
+
+// NewWorkerPool starts size worker goroutines backed by a queue of depth
+// maxQueueDepth (defaultMaxQueueDepth when <= 0). Workers run until Close is
+// called.
+func NewWorkerPool(size, maxQueueDepth int) *WorkerPool {
+	if size <= 0 {
+		size = 1
+	}
+	if maxQueueDepth <= 0 {
+		maxQueueDepth = defaultMaxQueueDepth
+	}
+
+	p := &WorkerPool{
+		queue: make(chan queuedJob, maxQueueDepth),
+		done:  make(chan struct{}),
+	}
+	for i := 0; i < size; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *WorkerPool) worker() {
+	for qj := range p.queue {
+		ctx := qj.job.Ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		err := qj.job.Run(ctx)
+		qj.result <- Result{Rendition: qj.job.Rendition, Err: err}
+	}
+}
+
+// Submit enqueues job and returns a channel that receives exactly one Result
+// once it completes. If the queue is already at maxQueueDepth, Submit returns
+// an error immediately instead of blocking the caller or growing the queue
+// further — back-pressure surfaces as a submit error rather than unbounded
+// goroutine growth.
+func (p *WorkerPool) Submit(job Job) (<-chan Result, error) {
+	result := make(chan Result, 1)
+	select {
+	case p.queue <- queuedJob{job: job, result: result}:
+		return result, nil
+	default:
+		return nil, fmt.Errorf("ffmpeg worker pool: queue is full")
+	}
+}
+
+// Close stops accepting new jobs and shuts down worker goroutines once the
+// queue drains. It is safe to call once all submitted jobs' results have been
+// received.
+func (p *WorkerPool) Close() {
+	close(p.queue)
+}
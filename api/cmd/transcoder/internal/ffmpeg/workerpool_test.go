@@ -0,0 +1,152 @@
+package ffmpeg_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/ffmpeg"
+)
+
+func TestWorkerPool_RunsAllJobs(t *testing.T) {
+	pool := ffmpeg.NewWorkerPool(2, 8)
+	defer pool.Close()
+
+	var chans []<-chan ffmpeg.Result
+	for i := 0; i < 5; i++ {
+		rc, err := pool.Submit(ffmpeg.Job{
+			Ctx:       context.Background(),
+			Rendition: ffmpeg.Rendition{Name: "r"},
+			Run:       func(context.Context) error { return nil },
+		})
+		if err != nil {
+			t.Fatalf("submit %d: unexpected error: %v", i, err)
+		}
+		chans = append(chans, rc)
+	}
+
+	for i, rc := range chans {
+		res := <-rc
+		if res.Err != nil {
+			t.Errorf("job %d: unexpected error: %v", i, res.Err)
+		}
+	}
+}
+
+func TestWorkerPool_RespectsConcurrencyBound(t *testing.T) {
+	const size = 3
+	pool := ffmpeg.NewWorkerPool(size, 16)
+	defer pool.Close()
+
+	var (
+		current int32
+		peak    int32
+		mu      sync.Mutex
+	)
+	recordPeak := func(n int32) {
+		mu.Lock()
+		defer mu.Unlock()
+		if n > peak {
+			peak = n
+		}
+	}
+
+	var chans []<-chan ffmpeg.Result
+	for i := 0; i < 10; i++ {
+		rc, err := pool.Submit(ffmpeg.Job{
+			Ctx: context.Background(),
+			Run: func(context.Context) error {
+				n := atomic.AddInt32(&current, 1)
+				recordPeak(n)
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&current, -1)
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("submit %d: unexpected error: %v", i, err)
+		}
+		chans = append(chans, rc)
+	}
+
+	for _, rc := range chans {
+		<-rc
+	}
+
+	if peak > size {
+		t.Errorf("observed concurrency %d exceeds pool size %d", peak, size)
+	}
+}
+
+func TestWorkerPool_Submit_QueueFullReturnsError(t *testing.T) {
+	block := make(chan struct{})
+	pool := ffmpeg.NewWorkerPool(1, 1)
+	defer func() {
+		close(block)
+		pool.Close()
+	}()
+
+	// Occupy the single worker so the queue (depth 1) fills up.
+	started := make(chan struct{})
+	if _, err := pool.Submit(ffmpeg.Job{Ctx: context.Background(), Run: func(context.Context) error {
+		close(started)
+		<-block
+		return nil
+	}}); err != nil {
+		t.Fatalf("unexpected error occupying worker: %v", err)
+	}
+	<-started
+	if _, err := pool.Submit(ffmpeg.Job{Ctx: context.Background(), Run: func(context.Context) error { return nil }}); err != nil {
+		t.Fatalf("unexpected error filling queue: %v", err)
+	}
+
+	if _, err := pool.Submit(ffmpeg.Job{Ctx: context.Background(), Run: func(context.Context) error { return nil }}); err == nil {
+		t.Fatal("expected error when queue is full")
+	}
+}
+
+func TestWorkerPool_PropagatesJobError(t *testing.T) {
+	pool := ffmpeg.NewWorkerPool(1, 4)
+	defer pool.Close()
+
+	wantErr := errors.New("boom")
+	rc, err := pool.Submit(ffmpeg.Job{Ctx: context.Background(), Run: func(context.Context) error { return wantErr }})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	res := <-rc
+	if !errors.Is(res.Err, wantErr) {
+		t.Errorf("result error = %v, want %v", res.Err, wantErr)
+	}
+}
+
+func TestWorkerPool_CancelledContextStopsJob(t *testing.T) {
+	pool := ffmpeg.NewWorkerPool(1, 4)
+	defer pool.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	rc, err := pool.Submit(ffmpeg.Job{
+		Ctx: ctx,
+		Run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	<-started
+	cancel()
+
+	res := <-rc
+	if res.Err == nil {
+		t.Fatal("expected job to observe context cancellation")
+	}
+}
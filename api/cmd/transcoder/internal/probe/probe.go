@@ -0,0 +1,247 @@
+// Package probe runs ffprobe against a downloaded raw upload and validates it
+// against a configurable acceptance policy before the transcoder commits
+// Cloud Run compute to it. Rejecting an obviously-bad file here (no video
+// stream, absurd duration, unsupported codec) takes a second or two, versus
+// several minutes of ffmpeg failing deep inside the transcode step.
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaInfo is the subset of ffprobe's output the transcoder cares about.
+type MediaInfo struct {
+	Duration   time.Duration
+	Width      int
+	Height     int
+	VideoCodec string
+	AudioCodec string
+	Bitrate    int
+	HasVideo   bool
+	HasAudio   bool
+}
+
+// RejectedError indicates the probed file failed policy validation. Callers
+// should treat it distinctly from a generic probe or transcode failure: the
+// caller never ran ffmpeg, and Reason is safe to surface to end users.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("rejected: %s", e.Reason)
+}
+
+// Policy bounds what MediaInfo is accepted for transcoding. A zero value for
+// MaxDurationSeconds or MaxInputHeight means "no limit"; a nil or empty
+// AllowedVideoCodecs means "allow any codec".
+type Policy struct {
+	MaxDurationSeconds int
+	MaxInputHeight     int
+	AllowedVideoCodecs []string
+}
+
+// PolicyFromEnv reads Policy from the environment:
+//
+//	MAX_DURATION_SECONDS — reject videos longer than this many seconds (0/unset = no limit)
+//	MAX_INPUT_HEIGHT     — reject videos taller than this many pixels (0/unset = no limit)
+//	ALLOWED_VIDEO_CODECS — comma-separated codec allow-list (unset = allow any)
+func PolicyFromEnv() Policy {
+	var codecs []string
+	if v := os.Getenv("ALLOWED_VIDEO_CODECS"); v != "" {
+		for _, c := range strings.Split(v, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				codecs = append(codecs, c)
+			}
+		}
+	}
+	return Policy{
+		MaxDurationSeconds: envInt("MAX_DURATION_SECONDS"),
+		MaxInputHeight:     envInt("MAX_INPUT_HEIGHT"),
+		AllowedVideoCodecs: codecs,
+	}
+}
+
+func envInt(key string) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// check validates info against p, returning a *RejectedError describing the
+// first violation found, or nil if info is acceptable.
+func (p Policy) check(info MediaInfo) error {
+	if !info.HasVideo {
+		return &RejectedError{Reason: "no video stream"}
+	}
+	if info.Duration <= 0 {
+		return &RejectedError{Reason: "zero duration"}
+	}
+	if p.MaxDurationSeconds > 0 && info.Duration > time.Duration(p.MaxDurationSeconds)*time.Second {
+		return &RejectedError{Reason: fmt.Sprintf("duration %s exceeds maximum of %ds", info.Duration, p.MaxDurationSeconds)}
+	}
+	if p.MaxInputHeight > 0 && info.Height > p.MaxInputHeight {
+		return &RejectedError{Reason: fmt.Sprintf("height %dp exceeds maximum of %dp", info.Height, p.MaxInputHeight)}
+	}
+	if len(p.AllowedVideoCodecs) > 0 && !contains(p.AllowedVideoCodecs, info.VideoCodec) {
+		return &RejectedError{Reason: fmt.Sprintf("video codec %q is not in the allowed list %v", info.VideoCodec, p.AllowedVideoCodecs)}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandRunner abstracts exec.CommandContext so tests can inject canned
+// ffprobe JSON output.
+type CommandRunner interface {
+	Output(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// ExecCommandRunner is the real CommandRunner that shells out to the system.
+type ExecCommandRunner struct{}
+
+// Output runs name with args and returns its stdout.
+func (ExecCommandRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("ffprobe exited with error: %w\nstderr:\n%s", err, exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+	return out, nil
+}
+
+// Prober runs ffprobe on a media file and validates the result against Policy.
+type Prober struct {
+	// Cmd is the command executor; defaults to ExecCommandRunner{}.
+	Cmd CommandRunner
+	// Policy bounds what probed files are accepted for transcoding.
+	Policy Policy
+}
+
+// NewProber constructs a Prober enforcing policy, using the real ffprobe binary.
+func NewProber(policy Policy) *Prober {
+	return &Prober{Cmd: ExecCommandRunner{}, Policy: policy}
+}
+
+// Probe runs ffprobe on path, parses its JSON output into a MediaInfo, and
+// checks it against p.Policy. It returns a *RejectedError if the file fails
+// policy, or a plain error if ffprobe itself fails or its output can't be
+// parsed.
+func (p *Prober) Probe(ctx context.Context, path string) (MediaInfo, error) {
+	out, err := p.Cmd.Output(ctx, "ffprobe",
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		path,
+	)
+	if err != nil {
+		return MediaInfo{}, fmt.Errorf("probe %s: %w", path, err)
+	}
+
+	info, err := parseMediaInfo(out)
+	if err != nil {
+		return MediaInfo{}, fmt.Errorf("parse ffprobe output for %s: %w", path, err)
+	}
+
+	if err := p.Policy.check(info); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+// ffprobeOutput mirrors the fields of ffprobe's `-show_format -show_streams
+// -print_format json` output that parseMediaInfo needs.
+type ffprobeOutput struct {
+	Streams []ffprobeStream `json:"streams"`
+	Format  ffprobeFormat   `json:"format"`
+}
+
+type ffprobeStream struct {
+	CodecType string `json:"codec_type"`
+	CodecName string `json:"codec_name"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+}
+
+type ffprobeFormat struct {
+	Duration string `json:"duration"`
+	BitRate  string `json:"bit_rate"`
+}
+
+// parseMediaInfo decodes raw ffprobe JSON into a MediaInfo, taking the first
+// video and audio stream found.
+func parseMediaInfo(raw []byte) (MediaInfo, error) {
+	var out ffprobeOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return MediaInfo{}, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	info := MediaInfo{
+		Duration: parseDurationSeconds(out.Format.Duration),
+		Bitrate:  parseBitRate(out.Format.BitRate),
+	}
+	for _, s := range out.Streams {
+		switch s.CodecType {
+		case "video":
+			if info.HasVideo {
+				continue
+			}
+			info.HasVideo = true
+			info.VideoCodec = s.CodecName
+			info.Width = s.Width
+			info.Height = s.Height
+		case "audio":
+			if info.HasAudio {
+				continue
+			}
+			info.HasAudio = true
+			info.AudioCodec = s.CodecName
+		}
+	}
+	return info, nil
+}
+
+// parseDurationSeconds converts ffprobe's "123.456000"-style duration string
+// to a time.Duration, returning 0 for values it can't parse.
+func parseDurationSeconds(s string) time.Duration {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(f * float64(time.Second))
+}
+
+// parseBitRate converts ffprobe's decimal bit_rate string to an int,
+// returning 0 for values it can't parse rather than erroring, since bitrate
+// is only used as advisory metadata here.
+func parseBitRate(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0
+	}
+	return n
+}
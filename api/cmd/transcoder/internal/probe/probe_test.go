@@ -0,0 +1,209 @@
+package probe_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/probe"
+)
+
+// ── stub CommandRunner ─────────────────────────────────────────────────────────
+
+type stubRunner struct {
+	out []byte
+	err error
+}
+
+func (s *stubRunner) Output(_ context.Context, _ string, _ ...string) ([]byte, error) {
+	return s.out, s.err
+}
+
+const validJSON = `{
+	"streams": [
+		{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080},
+		{"codec_type": "audio", "codec_name": "aac"}
+	],
+	"format": {"duration": "125.040000", "bit_rate": "2500000"}
+}`
+
+// ── Probe ─────────────────────────────────────────────────────────────────────
+
+func TestProbe_ValidFile_ReturnsMediaInfo(t *testing.T) {
+	p := &probe.Prober{Cmd: &stubRunner{out: []byte(validJSON)}}
+
+	info, err := p.Probe(context.Background(), "/tmp/raw_input.mp4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Width != 1920 || info.Height != 1080 {
+		t.Errorf("resolution = %dx%d, want 1920x1080", info.Width, info.Height)
+	}
+	if info.VideoCodec != "h264" {
+		t.Errorf("VideoCodec = %q, want h264", info.VideoCodec)
+	}
+	if info.AudioCodec != "aac" {
+		t.Errorf("AudioCodec = %q, want aac", info.AudioCodec)
+	}
+	if !info.HasVideo || !info.HasAudio {
+		t.Errorf("HasVideo=%v HasAudio=%v, want both true", info.HasVideo, info.HasAudio)
+	}
+	if info.Duration.Seconds() != 125.04 {
+		t.Errorf("Duration = %v, want 125.04s", info.Duration)
+	}
+	if info.Bitrate != 2500000 {
+		t.Errorf("Bitrate = %d, want 2500000", info.Bitrate)
+	}
+}
+
+func TestProbe_CommandError_ReturnsError(t *testing.T) {
+	p := &probe.Prober{Cmd: &stubRunner{err: errors.New("exit status 1")}}
+
+	if _, err := p.Probe(context.Background(), "/tmp/raw_input.mp4"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestProbe_InvalidJSON_ReturnsError(t *testing.T) {
+	p := &probe.Prober{Cmd: &stubRunner{out: []byte("not json")}}
+
+	if _, err := p.Probe(context.Background(), "/tmp/raw_input.mp4"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestProbe_NoVideoStream_ReturnsRejectedError(t *testing.T) {
+	const audioOnlyJSON = `{"streams": [{"codec_type": "audio", "codec_name": "aac"}], "format": {"duration": "10.0"}}`
+	p := &probe.Prober{Cmd: &stubRunner{out: []byte(audioOnlyJSON)}}
+
+	_, err := p.Probe(context.Background(), "/tmp/raw_input.mp4")
+	var rejErr *probe.RejectedError
+	if !errors.As(err, &rejErr) {
+		t.Fatalf("expected *probe.RejectedError, got %v", err)
+	}
+}
+
+func TestProbe_ZeroDuration_ReturnsRejectedError(t *testing.T) {
+	const zeroDurationJSON = `{
+		"streams": [{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080}],
+		"format": {"duration": "0.0"}
+	}`
+	p := &probe.Prober{Cmd: &stubRunner{out: []byte(zeroDurationJSON)}}
+
+	_, err := p.Probe(context.Background(), "/tmp/raw_input.mp4")
+	var rejErr *probe.RejectedError
+	if !errors.As(err, &rejErr) {
+		t.Fatalf("expected *probe.RejectedError, got %v", err)
+	}
+}
+
+func TestProbe_DurationExceedsPolicy_ReturnsRejectedError(t *testing.T) {
+	p := &probe.Prober{
+		Cmd:    &stubRunner{out: []byte(validJSON)},
+		Policy: probe.Policy{MaxDurationSeconds: 60},
+	}
+
+	_, err := p.Probe(context.Background(), "/tmp/raw_input.mp4")
+	var rejErr *probe.RejectedError
+	if !errors.As(err, &rejErr) {
+		t.Fatalf("expected *probe.RejectedError, got %v", err)
+	}
+}
+
+func TestProbe_HeightExceedsPolicy_ReturnsRejectedError(t *testing.T) {
+	p := &probe.Prober{
+		Cmd:    &stubRunner{out: []byte(validJSON)},
+		Policy: probe.Policy{MaxInputHeight: 720},
+	}
+
+	_, err := p.Probe(context.Background(), "/tmp/raw_input.mp4")
+	var rejErr *probe.RejectedError
+	if !errors.As(err, &rejErr) {
+		t.Fatalf("expected *probe.RejectedError, got %v", err)
+	}
+}
+
+func TestProbe_CodecNotAllowed_ReturnsRejectedError(t *testing.T) {
+	p := &probe.Prober{
+		Cmd:    &stubRunner{out: []byte(validJSON)},
+		Policy: probe.Policy{AllowedVideoCodecs: []string{"vp9", "av1"}},
+	}
+
+	_, err := p.Probe(context.Background(), "/tmp/raw_input.mp4")
+	var rejErr *probe.RejectedError
+	if !errors.As(err, &rejErr) {
+		t.Fatalf("expected *probe.RejectedError, got %v", err)
+	}
+}
+
+func TestProbe_CodecAllowed_Passes(t *testing.T) {
+	p := &probe.Prober{
+		Cmd:    &stubRunner{out: []byte(validJSON)},
+		Policy: probe.Policy{AllowedVideoCodecs: []string{"h264", "hevc"}},
+	}
+
+	if _, err := p.Probe(context.Background(), "/tmp/raw_input.mp4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestProbe_WithinPolicy_Passes(t *testing.T) {
+	p := &probe.Prober{
+		Cmd:    &stubRunner{out: []byte(validJSON)},
+		Policy: probe.Policy{MaxDurationSeconds: 600, MaxInputHeight: 1080},
+	}
+
+	if _, err := p.Probe(context.Background(), "/tmp/raw_input.mp4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// ── RejectedError ─────────────────────────────────────────────────────────────
+
+func TestRejectedError_Error_IncludesReason(t *testing.T) {
+	err := &probe.RejectedError{Reason: "no video stream"}
+	if got := err.Error(); got != "rejected: no video stream" {
+		t.Errorf("Error() = %q, want %q", got, "rejected: no video stream")
+	}
+}
+
+// ── NewProber ─────────────────────────────────────────────────────────────────
+
+func TestNewProber_NotNil(t *testing.T) {
+	p := probe.NewProber(probe.Policy{})
+	if p == nil {
+		t.Fatal("NewProber() returned nil")
+	}
+}
+
+// ── PolicyFromEnv ─────────────────────────────────────────────────────────────
+
+func TestPolicyFromEnv_Defaults(t *testing.T) {
+	p := probe.PolicyFromEnv()
+	if p.MaxDurationSeconds != 0 {
+		t.Errorf("MaxDurationSeconds = %d, want 0", p.MaxDurationSeconds)
+	}
+	if p.MaxInputHeight != 0 {
+		t.Errorf("MaxInputHeight = %d, want 0", p.MaxInputHeight)
+	}
+	if len(p.AllowedVideoCodecs) != 0 {
+		t.Errorf("AllowedVideoCodecs = %v, want empty", p.AllowedVideoCodecs)
+	}
+}
+
+func TestPolicyFromEnv_ReadsVars(t *testing.T) {
+	t.Setenv("MAX_DURATION_SECONDS", "3600")
+	t.Setenv("MAX_INPUT_HEIGHT", "2160")
+	t.Setenv("ALLOWED_VIDEO_CODECS", "h264, hevc")
+
+	p := probe.PolicyFromEnv()
+	if p.MaxDurationSeconds != 3600 {
+		t.Errorf("MaxDurationSeconds = %d, want 3600", p.MaxDurationSeconds)
+	}
+	if p.MaxInputHeight != 2160 {
+		t.Errorf("MaxInputHeight = %d, want 2160", p.MaxInputHeight)
+	}
+	if len(p.AllowedVideoCodecs) != 2 || p.AllowedVideoCodecs[0] != "h264" || p.AllowedVideoCodecs[1] != "hevc" {
+		t.Errorf("AllowedVideoCodecs = %v, want [h264 hevc]", p.AllowedVideoCodecs)
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+)
+
+// azureBackend implements Backend on top of Azure Blob Storage.
+// "azblob://<container>/<blob>" URIs map bucket→container and object→blob.
+type azureBackend struct {
+	client *azblob.Client
+}
+
+func init() {
+	Register("azblob", func(ctx context.Context) (Backend, error) {
+		account := getenv("AZURE_STORAGE_ACCOUNT", "")
+		key := getenv("AZURE_STORAGE_KEY", "")
+		if account == "" || key == "" {
+			return nil, fmt.Errorf("AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY env vars are required")
+		}
+
+		cred, err := azblob.NewSharedKeyCredential(account, key)
+		if err != nil {
+			return nil, fmt.Errorf("azure shared key credential: %w", err)
+		}
+
+		serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+		client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("azure client: %w", err)
+		}
+		return &azureBackend{client: client}, nil
+	})
+}
+
+// NewReader opens a blob reader for container/blob.
+func (b *azureBackend) NewReader(ctx context.Context, container, blob string) (io.ReadCloser, error) {
+	resp, err := b.client.DownloadStream(ctx, container, blob, nil)
+	if err != nil {
+		return nil, fmt.Errorf("azure download azblob://%s/%s: %w", container, blob, err)
+	}
+	return resp.Body, nil
+}
+
+// NewWriter opens a blob writer for container/blob. Azure's UploadStream call
+// is not incremental, so writes are buffered in-memory and uploaded on Close.
+func (b *azureBackend) NewWriter(ctx context.Context, container, blob string) io.WriteCloser {
+	return &azureWriter{ctx: ctx, client: b.client, container: container, blob: blob}
+}
+
+type azureWriter struct {
+	ctx       context.Context
+	client    *azblob.Client
+	container string
+	blob      string
+	buf       []byte
+}
+
+func (w *azureWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+func (w *azureWriter) Close() error {
+	_, err := w.client.UploadBuffer(w.ctx, w.container, w.blob, w.buf, nil)
+	if err != nil {
+		return fmt.Errorf("azure upload azblob://%s/%s: %w", w.container, w.blob, err)
+	}
+	return nil
+}
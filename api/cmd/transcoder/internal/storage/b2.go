@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Backblaze B2 exposes an S3-compatible API, so "b2://" reuses s3Backend
+// against the region-specific B2 endpoint (e.g. https://s3.us-west-002.backblazeb2.com).
+func init() {
+	Register("b2", func(ctx context.Context) (Backend, error) {
+		endpoint := getenv("B2_ENDPOINT", "")
+		if endpoint == "" {
+			return nil, fmt.Errorf("B2_ENDPOINT env var is not set")
+		}
+
+		cfg, err := config.LoadDefaultConfig(ctx,
+			config.WithRegion(getenv("B2_REGION", "us-west-002")),
+			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+				getenv("B2_KEY_ID", ""),
+				getenv("B2_APPLICATION_KEY", ""),
+				"",
+			)),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("load b2 config: %w", err)
+		}
+
+		client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		})
+		return &s3Backend{client: client}, nil
+	})
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Backend is a named object-storage implementation providing both read and
+// write access, selected by the scheme of an object URI (e.g. "s3://").
+type Backend interface {
+	ObjectReader
+	ObjectWriter
+}
+
+// BackendFactory constructs a Backend for a given scheme. Factories are
+// registered from an init() in the backend's own file so adding a new
+// provider never requires touching this file.
+type BackendFactory func(ctx context.Context) (Backend, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]BackendFactory{}
+)
+
+// Register adds a BackendFactory under scheme (e.g. "s3", "gs", "b2", "azblob").
+// It panics on a duplicate registration, mirroring database/sql.Register.
+func Register(scheme string, factory BackendFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[scheme]; dup {
+		panic("storage: Register called twice for scheme " + scheme)
+	}
+	registry[scheme] = factory
+}
+
+// Open constructs the Backend registered for scheme.
+func Open(ctx context.Context, scheme string) (Backend, error) {
+	registryMu.RLock()
+	factory, ok := registry[scheme]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("storage: no backend registered for scheme %q", scheme)
+	}
+	return factory(ctx)
+}
+
+// ObjectURI is a parsed bucket-qualified object reference of the form
+// "<scheme>://<bucket>/<object...>".
+type ObjectURI struct {
+	Scheme string
+	Bucket string
+	Object string
+}
+
+// ParseURI parses a bucket-qualified object URI such as
+// "s3://my-bucket/videos/a.mp4" or "gs://mytube-hls-output/videos/<id>/index.m3u8".
+func ParseURI(raw string) (ObjectURI, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ObjectURI{}, fmt.Errorf("parse object uri %q: %w", raw, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return ObjectURI{}, fmt.Errorf("object uri %q must be of the form scheme://bucket/key", raw)
+	}
+	return ObjectURI{
+		Scheme: u.Scheme,
+		Bucket: u.Host,
+		Object: strings.TrimPrefix(u.Path, "/"),
+	}, nil
+}
+
+// OpenURI parses raw and opens the Backend registered for its scheme in one step.
+func OpenURI(ctx context.Context, raw string) (Backend, ObjectURI, error) {
+	uri, err := ParseURI(raw)
+	if err != nil {
+		return nil, ObjectURI{}, err
+	}
+	backend, err := Open(ctx, uri.Scheme)
+	if err != nil {
+		return nil, ObjectURI{}, err
+	}
+	return backend, uri, nil
+}
@@ -0,0 +1,69 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/storage"
+)
+
+func TestParseURI_Success(t *testing.T) {
+	uri, err := storage.ParseURI("s3://my-bucket/videos/abc/index.m3u8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uri.Scheme != "s3" {
+		t.Errorf("scheme = %q, want %q", uri.Scheme, "s3")
+	}
+	if uri.Bucket != "my-bucket" {
+		t.Errorf("bucket = %q, want %q", uri.Bucket, "my-bucket")
+	}
+	if uri.Object != "videos/abc/index.m3u8" {
+		t.Errorf("object = %q, want %q", uri.Object, "videos/abc/index.m3u8")
+	}
+}
+
+func TestParseURI_MissingScheme(t *testing.T) {
+	if _, err := storage.ParseURI("my-bucket/videos/abc/index.m3u8"); err == nil {
+		t.Fatal("expected error for missing scheme")
+	}
+}
+
+func TestParseURI_MissingBucket(t *testing.T) {
+	if _, err := storage.ParseURI("s3:///videos/abc/index.m3u8"); err == nil {
+		t.Fatal("expected error for missing bucket")
+	}
+}
+
+func TestRegisterAndOpen(t *testing.T) {
+	const scheme = "teststorage-backend-registry"
+	storage.Register(scheme, func(_ context.Context) (storage.Backend, error) {
+		return nil, nil
+	})
+
+	if _, err := storage.Open(context.Background(), scheme); err != nil {
+		t.Fatalf("unexpected error opening registered scheme: %v", err)
+	}
+}
+
+func TestRegister_PanicsOnDuplicate(t *testing.T) {
+	const scheme = "teststorage-backend-dup"
+	storage.Register(scheme, func(_ context.Context) (storage.Backend, error) {
+		return nil, nil
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on duplicate Register")
+		}
+	}()
+	storage.Register(scheme, func(_ context.Context) (storage.Backend, error) {
+		return nil, nil
+	})
+}
+
+func TestOpen_UnknownScheme(t *testing.T) {
+	if _, err := storage.Open(context.Background(), "does-not-exist"); err == nil {
+		t.Fatal("expected error for unknown scheme")
+	}
+}
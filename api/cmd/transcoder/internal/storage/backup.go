@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupMode names when HLS output is mirrored to the secondary destination.
+// BackupSink itself does not act on it: Mirror runs once each time it is
+// called, in either mode.
+type BackupMode string
+
+const (
+	// BackupModeImmediate mirrors right after the primary upload succeeds.
+	BackupModeImmediate BackupMode = "immediate"
+	// BackupModePeriodic names mirroring on a fixed Interval. This package
+	// has no scheduler for it; nothing here calls Mirror on a timer.
+	BackupModePeriodic BackupMode = "periodic"
+)
+
+// BackupSinkOptions configures a BackupSink. DestURI follows the same
+// scheme://bucket/prefix convention as the rest of the storage package (e.g.
+// "s3://mytube-hls-backup/videos" for a different cloud/region than the
+// primary HLS_BUCKET).
+type BackupSinkOptions struct {
+	DestURI  string
+	Mode     BackupMode
+	Interval time.Duration
+	// Compress, when true, bundles the mirrored tree into a single gzipped
+	// tarball instead of uploading each file individually — cheaper for cold
+	// storage classes that charge per-object.
+	Compress bool
+}
+
+// objectManifest records the SHA-256 digest of every object uploaded for one
+// video, keyed by path relative to the video's prefix. It doubles as the
+// integrity check and the skip-if-unchanged fingerprint for subsequent runs.
+type objectManifest struct {
+	VideoID string            `json:"video_id"`
+	Objects map[string]string `json:"objects"` // relative path → sha256 hex digest
+}
+
+// BackupSink mirrors a transcoder job's HLS output tree to a secondary
+// destination after the primary upload has succeeded, for disaster recovery.
+type BackupSink struct {
+	// Backend is the destination Backend resolved from Options.DestURI's scheme.
+	Backend Backend
+	Options BackupSinkOptions
+}
+
+// NewBackupSink resolves opts.DestURI's scheme through the backend registry
+// and returns a ready-to-use BackupSink.
+func NewBackupSink(ctx context.Context, opts BackupSinkOptions) (*BackupSink, error) {
+	uri, err := ParseURI(opts.DestURI)
+	if err != nil {
+		return nil, fmt.Errorf("parse backup destination %q: %w", opts.DestURI, err)
+	}
+	backend, err := Open(ctx, uri.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("resolve backup destination %q: %w", opts.DestURI, err)
+	}
+	return &BackupSink{Backend: backend, Options: opts}, nil
+}
+
+// Mirror walks srcDir (the local HLS output for videoID) and copies every
+// file to the backup destination, skipping files whose content hash matches
+// the previous manifest (with Options.Compress set, the whole tree is
+// uploaded again as one tarball and nothing is skipped). It uploads a
+// manifest-of-manifests (sha256 per
+// object) alongside the data so integrity can be verified independently of
+// the primary bucket.
+func (b *BackupSink) Mirror(ctx context.Context, videoID, srcDir string) error {
+	uri, err := ParseURI(b.Options.DestURI)
+	if err != nil {
+		return fmt.Errorf("parse backup dest uri: %w", err)
+	}
+	prefix := fmt.Sprintf("%s/%s", uri.Object, videoID)
+
+	manifest, err := buildManifest(videoID, srcDir)
+	if err != nil {
+		return fmt.Errorf("build backup manifest: %w", err)
+	}
+
+	previous, _ := b.fetchManifest(ctx, uri.Bucket, prefix) // missing/corrupt previous manifest: back up fully
+
+	if b.Options.Compress {
+		return b.mirrorCompressed(ctx, uri.Bucket, prefix, srcDir, manifest)
+	}
+	return b.mirrorFiles(ctx, uri.Bucket, prefix, srcDir, manifest, previous)
+}
+
+func (b *BackupSink) mirrorFiles(ctx context.Context, bucket, prefix, srcDir string, manifest, previous *objectManifest) error {
+	for rel, digest := range manifest.Objects {
+		if previous != nil && previous.Objects[rel] == digest {
+			continue // skip-if-unchanged
+		}
+
+		f, err := os.Open(filepath.Join(srcDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return fmt.Errorf("open %s for backup: %w", rel, err)
+		}
+		wc := b.Backend.NewWriter(ctx, bucket, prefix+"/"+rel)
+		_, copyErr := io.Copy(wc, f)
+		f.Close()
+		if copyErr != nil {
+			_ = wc.Close()
+			return fmt.Errorf("backup copy %s: %w", rel, copyErr)
+		}
+		if err := wc.Close(); err != nil {
+			return fmt.Errorf("finalise backup of %s: %w", rel, err)
+		}
+	}
+
+	return b.uploadManifest(ctx, bucket, prefix, manifest)
+}
+
+func (b *BackupSink) mirrorCompressed(ctx context.Context, bucket, prefix, srcDir string, manifest *objectManifest) error {
+	wc := b.Backend.NewWriter(ctx, bucket, prefix+"/backup.tar.gz")
+	gz := gzip.NewWriter(wc)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		_ = tw.Close()
+		_ = gz.Close()
+		_ = wc.Close()
+		return fmt.Errorf("build backup tarball: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar writer: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("close gzip writer: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("finalise backup tarball: %w", err)
+	}
+
+	return b.uploadManifest(ctx, bucket, prefix, manifest)
+}
+
+func (b *BackupSink) uploadManifest(ctx context.Context, bucket, prefix string, manifest *objectManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("marshal backup manifest: %w", err)
+	}
+	wc := b.Backend.NewWriter(ctx, bucket, prefix+"/manifest.json")
+	if _, err := wc.Write(data); err != nil {
+		_ = wc.Close()
+		return fmt.Errorf("write backup manifest: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("finalise backup manifest: %w", err)
+	}
+	return nil
+}
+
+func (b *BackupSink) fetchManifest(ctx context.Context, bucket, prefix string) (*objectManifest, error) {
+	rc, err := b.Backend.NewReader(ctx, bucket, prefix+"/manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var manifest objectManifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("decode previous backup manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// buildManifest computes the SHA-256 digest of every file under srcDir,
+// keyed by its slash-separated path relative to srcDir.
+func buildManifest(videoID, srcDir string) (*objectManifest, error) {
+	manifest := &objectManifest{VideoID: videoID, Objects: map[string]string{}}
+
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		manifest.Objects[filepath.ToSlash(rel)] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
@@ -0,0 +1,131 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/storage"
+)
+
+// memBackend is an in-memory storage.Backend fake for exercising BackupSink
+// without a real cloud provider.
+type memBackend struct {
+	mu      sync.Mutex
+	objects map[string][]byte // "bucket/object" → content
+}
+
+func newMemBackend() *memBackend {
+	return &memBackend{objects: map[string][]byte{}}
+}
+
+func (m *memBackend) key(bucket, object string) string { return bucket + "/" + object }
+
+func (m *memBackend) NewReader(_ context.Context, bucket, object string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[m.key(bucket, object)]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memBackend) NewWriter(_ context.Context, bucket, object string) io.WriteCloser {
+	return &memWriter{backend: m, key: m.key(bucket, object)}
+}
+
+type memWriter struct {
+	backend *memBackend
+	key     string
+	buf     bytes.Buffer
+}
+
+func (w *memWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *memWriter) Close() error {
+	w.backend.mu.Lock()
+	defer w.backend.mu.Unlock()
+	w.backend.objects[w.key] = append([]byte(nil), w.buf.Bytes()...)
+	return nil
+}
+
+func writeTestHLSTree(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "index.m3u8"), []byte("#EXTM3U"), 0o644); err != nil {
+		t.Fatalf("write index.m3u8: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "0.ts"), []byte("segment-data"), 0o644); err != nil {
+		t.Fatalf("write 0.ts: %v", err)
+	}
+}
+
+func TestBackupSink_Mirror_UploadsAllFilesAndManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeTestHLSTree(t, dir)
+
+	backend := newMemBackend()
+	sink := &storage.BackupSink{Backend: backend, Options: storage.BackupSinkOptions{DestURI: "mem://backup-bucket/videos"}}
+
+	if err := sink.Mirror(context.Background(), "vid123", dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"videos/vid123/index.m3u8", "videos/vid123/0.ts", "videos/vid123/manifest.json"} {
+		if _, ok := backend.objects[backend.key("backup-bucket", want)]; !ok {
+			t.Errorf("expected object %q to exist in backup destination", want)
+		}
+	}
+}
+
+func TestBackupSink_Mirror_SkipsUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestHLSTree(t, dir)
+
+	backend := newMemBackend()
+	sink := &storage.BackupSink{Backend: backend, Options: storage.BackupSinkOptions{DestURI: "mem://backup-bucket/videos"}}
+
+	if err := sink.Mirror(context.Background(), "vid123", dir); err != nil {
+		t.Fatalf("first mirror: unexpected error: %v", err)
+	}
+
+	// Tamper with the backed-up index.m3u8 to prove the second Mirror call
+	// skips files whose local content has not changed since the previous
+	// manifest, leaving the backup's copy exactly as it was.
+	backend.objects[backend.key("backup-bucket", "videos/vid123/index.m3u8")] = []byte("tampered")
+
+	if err := sink.Mirror(context.Background(), "vid123", dir); err != nil {
+		t.Fatalf("second mirror: unexpected error: %v", err)
+	}
+
+	got := string(backend.objects[backend.key("backup-bucket", "videos/vid123/index.m3u8")])
+	if got != "tampered" {
+		t.Errorf("expected unchanged index.m3u8 to be skipped on re-mirror, got %q", got)
+	}
+}
+
+func TestBackupSink_Mirror_Compressed(t *testing.T) {
+	dir := t.TempDir()
+	writeTestHLSTree(t, dir)
+
+	backend := newMemBackend()
+	sink := &storage.BackupSink{
+		Backend: backend,
+		Options: storage.BackupSinkOptions{DestURI: "mem://backup-bucket/videos", Compress: true},
+	}
+
+	if err := sink.Mirror(context.Background(), "vid123", dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := backend.objects[backend.key("backup-bucket", "videos/vid123/backup.tar.gz")]; !ok {
+		t.Error("expected a single tarball object when Compress is set")
+	}
+	if _, ok := backend.objects[backend.key("backup-bucket", "videos/vid123/index.m3u8")]; ok {
+		t.Error("did not expect individual files to be uploaded when Compress is set")
+	}
+}
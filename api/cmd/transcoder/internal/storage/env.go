@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// objectStoreSchemes maps the friendly OBJECT_STORE names operators set to
+// the URI scheme each provider is registered under, so callers that just
+// want "whichever backend is configured" don't need to know the scheme
+// naming used by ParseURI/OpenURI.
+var objectStoreSchemes = map[string]string{
+	"gcs":   "gs",
+	"s3":    "s3",
+	"azure": "azblob",
+	"minio": "minio",
+	"b2":    "b2",
+}
+
+// OpenFromEnv opens the Backend selected by the OBJECT_STORE environment
+// variable (one of "gcs", "s3", "azure", "minio", "b2"; defaults to "gcs").
+// It lets the transcoder job and any other consumer of this package speak to
+// whichever backend is configured without code changes, while still reusing
+// the per-scheme registry that ParseURI/OpenURI rely on for bucket-qualified
+// URIs.
+func OpenFromEnv(ctx context.Context) (Backend, error) {
+	store := getenv("OBJECT_STORE", "gcs")
+	scheme, ok := objectStoreSchemes[store]
+	if !ok {
+		return nil, fmt.Errorf("storage: unknown OBJECT_STORE %q (want one of gcs, s3, azure, minio, b2)", store)
+	}
+	return Open(ctx, scheme)
+}
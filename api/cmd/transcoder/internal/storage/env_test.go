@@ -0,0 +1,39 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/storage"
+)
+
+func TestOpenFromEnv_UnknownObjectStore_ReturnsError(t *testing.T) {
+	t.Setenv("OBJECT_STORE", "not-a-real-backend")
+
+	if _, err := storage.OpenFromEnv(context.Background()); err == nil {
+		t.Fatal("expected error for unknown OBJECT_STORE value")
+	}
+}
+
+func TestOpenFromEnv_S3_DelegatesToSchemeRegistry(t *testing.T) {
+	t.Setenv("OBJECT_STORE", "s3")
+
+	wantBackend, wantErr := storage.Open(context.Background(), "s3")
+	gotBackend, gotErr := storage.OpenFromEnv(context.Background())
+	if (gotErr == nil) != (wantErr == nil) {
+		t.Fatalf("OpenFromEnv(s3) error = %v, want same outcome as Open(ctx, \"s3\") = %v", gotErr, wantErr)
+	}
+	if gotErr == nil && (gotBackend == nil) != (wantBackend == nil) {
+		t.Fatalf("OpenFromEnv(s3) backend nilness differs from Open(ctx, \"s3\")")
+	}
+}
+
+func TestOpenFromEnv_EmptyObjectStore_DefaultsToGCSScheme(t *testing.T) {
+	t.Setenv("OBJECT_STORE", "")
+
+	_, gotErr := storage.OpenFromEnv(context.Background())
+	_, wantErr := storage.Open(context.Background(), "gs")
+	if (gotErr == nil) != (wantErr == nil) {
+		t.Fatalf("OpenFromEnv(\"\") error = %v, want same outcome as Open(ctx, \"gs\") = %v", gotErr, wantErr)
+	}
+}
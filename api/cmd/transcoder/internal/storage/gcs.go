@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
 // ObjectReader abstracts GCS object reads so tests can inject a stub.
@@ -59,15 +60,27 @@ func (d *Downloader) Download(ctx context.Context, bucket, objectPath, destPath
 
 // Uploader uploads local files to GCS.
 type Uploader struct {
-	Writer ObjectWriter
+	Writer  ObjectWriter
+	Options UploaderOptions
 }
 
-// NewUploader constructs an Uploader backed by the provided ObjectWriter.
+// NewUploader constructs an Uploader backed by the provided ObjectWriter,
+// using DefaultUploaderOptions.
 func NewUploader(w ObjectWriter) *Uploader {
-	return &Uploader{Writer: w}
+	return NewUploaderWithOptions(w, DefaultUploaderOptions())
+}
+
+// NewUploaderWithOptions constructs an Uploader with caller-supplied concurrency
+// and chunking settings. Zero-valued fields in opts fall back to their default.
+func NewUploaderWithOptions(w ObjectWriter, opts UploaderOptions) *Uploader {
+	return &Uploader{Writer: w, Options: opts.withDefaults()}
 }
 
 // UploadFile copies a local file at srcPath to gs://<bucket>/<objectPath>.
+// When Writer also implements ResumableObjectWriter and the file is larger
+// than one chunk, the upload is split into ChunkSize pieces and each chunk is
+// retried independently on failure (see uploadResumable); otherwise it falls
+// back to a single streamed write.
 func (u *Uploader) UploadFile(ctx context.Context, bucket, objectPath, srcPath string) error {
 	f, err := os.Open(srcPath)
 	if err != nil {
@@ -75,6 +88,18 @@ func (u *Uploader) UploadFile(ctx context.Context, bucket, objectPath, srcPath s
 	}
 	defer f.Close()
 
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat local file %s: %w", srcPath, err)
+	}
+
+	if resumable, ok := u.Writer.(ResumableObjectWriter); ok && info.Size() > int64(u.Options.ChunkSize) {
+		if err := u.uploadResumable(ctx, resumable, bucket, objectPath, f, info.Size()); err != nil {
+			return fmt.Errorf("resumable upload %s to gs://%s/%s: %w", srcPath, bucket, objectPath, err)
+		}
+		return nil
+	}
+
 	wc := u.Writer.NewWriter(ctx, bucket, objectPath)
 	if _, err := io.Copy(wc, f); err != nil {
 		_ = wc.Close()
@@ -87,19 +112,49 @@ func (u *Uploader) UploadFile(ctx context.Context, bucket, objectPath, srcPath s
 }
 
 // UploadDir walks srcDir and uploads every file to gs://<bucket>/<prefix>/<relPath>.
+// Uploads run concurrently, bounded by Options.MaxConcurrency; the first
+// failure is returned once all in-flight uploads have finished.
 func (u *Uploader) UploadDir(ctx context.Context, bucket, prefix, srcDir string) error {
-	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+	var paths []string
+	if err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if info.IsDir() {
 			return nil
 		}
+		paths = append(paths, path)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	sem := make(chan struct{}, u.Options.MaxConcurrency)
+	errCh := make(chan error, len(paths))
+	var wg sync.WaitGroup
+
+	for _, path := range paths {
 		rel, err := filepath.Rel(srcDir, path)
 		if err != nil {
 			return fmt.Errorf("rel path for %s: %w", path, err)
 		}
 		objectPath := prefix + "/" + rel
-		return u.UploadFile(ctx, bucket, objectPath, path)
-	})
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(path, objectPath string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := u.UploadFile(ctx, bucket, objectPath, path); err != nil {
+				errCh <- err
+			}
+		}(path, objectPath)
+	}
+
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		return err
+	}
+	return nil
 }
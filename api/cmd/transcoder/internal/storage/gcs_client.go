@@ -7,6 +7,26 @@ import (
 	"cloud.google.com/go/storage"
 )
 
+// gcsBackend adapts a *storage.Client to the Backend interface so "gs://"
+// URIs can be resolved through the same registry as the other providers.
+type gcsBackend struct {
+	*GCSObjectReader
+	*GCSObjectWriter
+}
+
+func init() {
+	Register("gs", func(ctx context.Context) (Backend, error) {
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &gcsBackend{
+			GCSObjectReader: NewGCSObjectReader(client),
+			GCSObjectWriter: NewGCSObjectWriter(client),
+		}, nil
+	})
+}
+
 // GCSObjectReader implements ObjectReader using the real GCS client.
 type GCSObjectReader struct {
 	client *storage.Client
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// MinIO (and other S3-compatible endpoints such as DigitalOcean Spaces) speak
+// the S3 API, so the backend reuses s3Backend with a custom endpoint and
+// path-style addressing rather than duplicating the reader/writer logic.
+func init() {
+	Register("minio", func(ctx context.Context) (Backend, error) {
+		endpoint := getenv("MINIO_ENDPOINT", "")
+		if endpoint == "" {
+			return nil, fmt.Errorf("MINIO_ENDPOINT env var is not set")
+		}
+
+		cfg, err := config.LoadDefaultConfig(ctx,
+			config.WithRegion(getenv("MINIO_REGION", "us-east-1")),
+			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+				getenv("MINIO_ACCESS_KEY_ID", ""),
+				getenv("MINIO_SECRET_ACCESS_KEY", ""),
+				"",
+			)),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("load minio config: %w", err)
+		}
+
+		client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		})
+		return &s3Backend{client: client}, nil
+	})
+}
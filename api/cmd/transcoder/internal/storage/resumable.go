@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// defaultChunkSize is the default chunk size for resumable uploads (16 MiB).
+	defaultChunkSize = 16 << 20
+	// minChunkSize is the smallest chunk size callers may configure (256 KiB).
+	minChunkSize = 256 << 10
+	// defaultMaxConcurrency bounds how many files UploadDir uploads at once.
+	defaultMaxConcurrency = 32
+	// defaultMaxRetries bounds per-chunk retry attempts before giving up.
+	defaultMaxRetries = 5
+)
+
+// ProgressFunc is invoked after each chunk of an object upload completes.
+// bytesSent is cumulative; totalBytes is the full object size.
+type ProgressFunc func(objectPath string, bytesSent, totalBytes int64)
+
+// UploaderOptions configures Uploader concurrency and chunking behaviour.
+type UploaderOptions struct {
+	// MaxConcurrency bounds how many objects UploadDir uploads in parallel.
+	// Defaults to 32 when zero.
+	MaxConcurrency int
+	// ChunkSize is the size of each resumable upload chunk in bytes.
+	// Defaults to 16 MiB when zero; values below 256 KiB are raised to it.
+	ChunkSize int
+	// MaxRetries bounds how many times a single failed chunk is retried with
+	// exponential backoff before the upload fails. Defaults to 5 when zero.
+	MaxRetries int
+	// OnProgress, if set, is called after every successfully uploaded chunk.
+	OnProgress ProgressFunc
+}
+
+// DefaultUploaderOptions returns the options used by NewUploader.
+func DefaultUploaderOptions() UploaderOptions {
+	return UploaderOptions{
+		MaxConcurrency: defaultMaxConcurrency,
+		ChunkSize:      defaultChunkSize,
+		MaxRetries:     defaultMaxRetries,
+	}
+}
+
+// withDefaults fills zero-valued fields with their defaults and enforces the
+// chunk-size floor.
+func (o UploaderOptions) withDefaults() UploaderOptions {
+	if o.MaxConcurrency <= 0 {
+		o.MaxConcurrency = defaultMaxConcurrency
+	}
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = defaultChunkSize
+	}
+	if o.ChunkSize < minChunkSize {
+		o.ChunkSize = minChunkSize
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = defaultMaxRetries
+	}
+	return o
+}
+
+// ResumableSession uploads one object as a sequence of byte-range chunks.
+type ResumableSession interface {
+	// UploadChunk uploads the byte range [offset, offset+len(chunk)) of the
+	// object. final is true for the last chunk so the backend can finalise
+	// the upload (e.g. send a Content-Range with a known total size).
+	UploadChunk(ctx context.Context, offset int64, chunk []byte, final bool) error
+}
+
+// ResumableObjectWriter is implemented by backends that support chunked,
+// resumable uploads via an initial session followed by PUT-per-chunk calls.
+// Backends that don't implement it fall back to the plain ObjectWriter path.
+type ResumableObjectWriter interface {
+	// NewResumableSession opens a resumable upload session for bucket/object
+	// of the given total size.
+	NewResumableSession(ctx context.Context, bucket, object string, size int64) (ResumableSession, error)
+}
+
+// uploadResumable uploads r (of the given total size) to bucket/object in
+// Options.ChunkSize pieces, retrying each chunk up to Options.MaxRetries
+// times with exponential backoff before giving up on the whole upload.
+func (u *Uploader) uploadResumable(ctx context.Context, w ResumableObjectWriter, bucket, object string, r io.Reader, size int64) error {
+	session, err := w.NewResumableSession(ctx, bucket, object, size)
+	if err != nil {
+		return fmt.Errorf("open resumable session: %w", err)
+	}
+
+	buf := make([]byte, u.Options.ChunkSize)
+	var sent int64
+
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n == 0 && readErr == io.EOF {
+			break
+		}
+		if readErr != nil && readErr != io.ErrUnexpectedEOF && readErr != io.EOF {
+			return fmt.Errorf("read chunk at offset %d: %w", sent, readErr)
+		}
+
+		chunk := buf[:n]
+		final := sent+int64(n) >= size
+
+		if err := u.uploadChunkWithRetry(ctx, session, sent, chunk, final); err != nil {
+			return err
+		}
+
+		sent += int64(n)
+		if u.Options.OnProgress != nil {
+			u.Options.OnProgress(object, sent, size)
+		}
+
+		if final {
+			break
+		}
+	}
+	return nil
+}
+
+// uploadChunkWithRetry uploads one chunk, retrying on error with exponential
+// backoff (100ms, 200ms, 400ms, ...) up to Options.MaxRetries times, i.e.
+// at most Options.MaxRetries+1 attempts in all.
+func (u *Uploader) uploadChunkWithRetry(ctx context.Context, session ResumableSession, offset int64, chunk []byte, final bool) error {
+	backoff := 100 * time.Millisecond
+	var lastErr error
+	for attempt := 0; attempt <= u.Options.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		lastErr = session.UploadChunk(ctx, offset, chunk, final)
+		if lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("upload chunk at offset %d failed after %d attempts: %w", offset, u.Options.MaxRetries+1, lastErr)
+}
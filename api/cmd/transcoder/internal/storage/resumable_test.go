@@ -0,0 +1,102 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/storage"
+)
+
+// fakeResumableWriter implements both ObjectWriter and ResumableObjectWriter.
+type fakeResumableWriter struct {
+	mu       sync.Mutex
+	chunks   [][]byte
+	failOnce map[int64]bool // offsets that fail exactly once before succeeding
+}
+
+func (f *fakeResumableWriter) NewWriter(_ context.Context, _, _ string) io.WriteCloser {
+	panic("not used in these tests")
+}
+
+func (f *fakeResumableWriter) NewResumableSession(_ context.Context, _, _ string, _ int64) (storage.ResumableSession, error) {
+	return &fakeResumableSession{parent: f}, nil
+}
+
+type fakeResumableSession struct {
+	parent *fakeResumableWriter
+}
+
+func (s *fakeResumableSession) UploadChunk(_ context.Context, offset int64, chunk []byte, _ bool) error {
+	s.parent.mu.Lock()
+	defer s.parent.mu.Unlock()
+
+	if s.parent.failOnce[offset] {
+		delete(s.parent.failOnce, offset)
+		return errors.New("transient chunk upload error")
+	}
+	cp := append([]byte(nil), chunk...)
+	s.parent.chunks = append(s.parent.chunks, cp)
+	return nil
+}
+
+func TestUploader_UploadFile_ResumableChunking(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "big.ts")
+	content := make([]byte, 3*256*1024+10) // spans multiple 256KiB chunks
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	if err := os.WriteFile(srcPath, content, 0o644); err != nil {
+		t.Fatalf("write src file: %v", err)
+	}
+
+	w := &fakeResumableWriter{failOnce: map[int64]bool{}}
+	ul := storage.NewUploaderWithOptions(w, storage.UploaderOptions{ChunkSize: 256 * 1024})
+
+	var progressCalls int
+	ul.Options.OnProgress = func(_ string, _, _ int64) { progressCalls++ }
+
+	if err := ul.UploadFile(context.Background(), "bucket", "obj", srcPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var total int
+	for _, c := range w.chunks {
+		total += len(c)
+	}
+	if total != len(content) {
+		t.Errorf("uploaded %d bytes, want %d", total, len(content))
+	}
+	if progressCalls != len(w.chunks) {
+		t.Errorf("progress callback called %d times, want %d", progressCalls, len(w.chunks))
+	}
+}
+
+func TestUploader_UploadFile_ResumableRetriesFailedChunk(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "big.ts")
+	content := make([]byte, 256*1024+1)
+	if err := os.WriteFile(srcPath, content, 0o644); err != nil {
+		t.Fatalf("write src file: %v", err)
+	}
+
+	w := &fakeResumableWriter{failOnce: map[int64]bool{0: true}}
+	ul := storage.NewUploaderWithOptions(w, storage.UploaderOptions{ChunkSize: 256 * 1024, MaxRetries: 2})
+
+	if err := ul.UploadFile(context.Background(), "bucket", "obj", srcPath); err != nil {
+		t.Fatalf("expected retry to succeed, got error: %v", err)
+	}
+}
+
+func TestUploaderOptions_WithDefaults_EnforcesChunkFloor(t *testing.T) {
+	stub := &stubWriter{wc: &stubWriteCloser{}}
+	ul := storage.NewUploaderWithOptions(stub, storage.UploaderOptions{ChunkSize: 1024})
+	if ul.Options.ChunkSize < 256*1024 {
+		t.Errorf("chunk size = %d, want at least the 256KiB floor", ul.Options.ChunkSize)
+	}
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// s3Backend implements Backend on top of AWS S3.
+type s3Backend struct {
+	client *s3.Client
+}
+
+func init() {
+	Register("s3", func(ctx context.Context) (Backend, error) {
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(getenv("AWS_REGION", "us-east-1")))
+		if err != nil {
+			return nil, fmt.Errorf("load aws config: %w", err)
+		}
+		return &s3Backend{client: s3.NewFromConfig(cfg)}, nil
+	})
+}
+
+// NewReader opens an S3 object reader for bucket/object.
+func (b *s3Backend) NewReader(ctx context.Context, bucket, object string) (io.ReadCloser, error) {
+	out, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 get object s3://%s/%s: %w", bucket, object, err)
+	}
+	return out.Body, nil
+}
+
+// NewWriter opens an S3 object writer for bucket/object. Because the S3 PutObject
+// API has no streaming-write variant, the write is buffered in a temp file and
+// the PutObject call happens on Close.
+func (b *s3Backend) NewWriter(ctx context.Context, bucket, object string) io.WriteCloser {
+	return &s3Writer{ctx: ctx, client: b.client, bucket: bucket, object: object}
+}
+
+// s3Writer buffers writes to a temp file and uploads on Close, avoiding
+// holding an entire HLS segment or manifest in memory.
+type s3Writer struct {
+	ctx     context.Context
+	client  *s3.Client
+	bucket  string
+	object  string
+	tmp     *os.File
+	openErr error
+}
+
+func (w *s3Writer) ensureTmp() error {
+	if w.tmp != nil || w.openErr != nil {
+		return w.openErr
+	}
+	f, err := os.CreateTemp("", "s3-upload-*")
+	if err != nil {
+		w.openErr = fmt.Errorf("create temp upload buffer: %w", err)
+		return w.openErr
+	}
+	w.tmp = f
+	return nil
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	if err := w.ensureTmp(); err != nil {
+		return 0, err
+	}
+	return w.tmp.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	if w.openErr != nil {
+		return w.openErr
+	}
+	if w.tmp == nil {
+		// Nothing was ever written; upload a zero-byte object for parity
+		// with the GCS writer, which finalises even empty writes.
+		_, err := w.client.PutObject(w.ctx, &s3.PutObjectInput{
+			Bucket: aws.String(w.bucket),
+			Key:    aws.String(w.object),
+			Body:   nil,
+		})
+		return err
+	}
+	defer os.Remove(w.tmp.Name())
+	defer w.tmp.Close()
+
+	if _, err := w.tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind upload buffer: %w", err)
+	}
+	_, err := w.client.PutObject(w.ctx, &s3.PutObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(w.object),
+		Body:   w.tmp,
+	})
+	if err != nil {
+		return fmt.Errorf("s3 put object s3://%s/%s: %w", w.bucket, w.object, err)
+	}
+	return nil
+}
+
+// getenv returns the value of the environment variable named by key, or
+// fallback when the variable is unset or empty.
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
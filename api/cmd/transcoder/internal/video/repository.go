@@ -4,37 +4,118 @@ package video
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+
+	"github.com/ai-teammate/mytube/api/internal/repository"
 )
 
+// ErrNotFound is returned by UpdateVideosTx when a row's video ID doesn't
+// match any video.
+var ErrNotFound = errors.New("video: not found")
+
+// ErrConflict is returned by UpdateVideosTx when a row's current version
+// doesn't match Update.Version, i.e. another write landed first — see
+// Update.Version.
+var ErrConflict = errors.New("video: version conflict")
+
 // Status represents the processing state of a video.
 type Status string
 
 const (
-	StatusReady  Status = "ready"
-	StatusFailed Status = "failed"
+	StatusReady    Status = "ready"
+	StatusFailed   Status = "failed"
+	StatusRejected Status = "rejected"
+)
+
+// Manifest type values for Update.ManifestType.
+const (
+	// ManifestTypeHLS means only HLSManifestPath is populated.
+	ManifestTypeHLS = "hls"
+	// ManifestTypeBoth means both HLSManifestPath and DASHManifestPath are
+	// populated; the player should prefer DASH except on iOS/Safari, which
+	// only supports native HLS playback.
+	ManifestTypeBoth = "hls+dash"
+	// ManifestTypeDASH means only DASHManifestPath is populated, e.g. when
+	// the transcoder was configured with TRANSCODE_FORMATS=dash.
+	ManifestTypeDASH = "dash"
 )
 
 // Update holds the fields to be written to the videos row on completion.
 type Update struct {
 	HLSManifestPath string
-	ThumbnailURL    string
-	Status          Status
+	// DASHManifestPath is the gs:// path to the MPEG-DASH manifest generated
+	// alongside the HLS output. Empty when DASH packaging was skipped.
+	DASHManifestPath string
+	// ManifestType tells the player which manifest format(s) this video's
+	// output supports, e.g. ManifestTypeBoth once both HLSManifestPath and
+	// DASHManifestPath are populated, so iOS/Safari clients can keep using
+	// native HLS while others prefer DASH.
+	ManifestType string
+	ThumbnailURL string
+	// StoryboardVTTPath is the gs:// path to the WebVTT sidecar mapping
+	// timecodes to storyboard sprite regions, for the player's hover-scrub
+	// preview. Empty when storyboard generation was skipped.
+	StoryboardVTTPath string
+	Status            Status
+	// DurationSeconds is the probed source duration, rounded down to the
+	// nearest second, so the API can display it without a second probe.
+	DurationSeconds int
+	// Width and Height are the probed source resolution in pixels. Zero
+	// means the probe step didn't run or didn't find a video stream.
+	Width, Height int
+	// VideoCodec and AudioCodec are the probed source's codec names (e.g.
+	// "h264", "aac"), so the API can display real source metadata instead
+	// of just "processing → ready".
+	VideoCodec, AudioCodec string
+	// VideoID identifies the row to write. Only consulted by UpdateVideosTx;
+	// UpdateVideo and MarkFailed take the video ID as a separate argument
+	// for backwards compatibility with existing call sites.
+	VideoID string
+	// Version, when non-zero, guards the write with an optimistic
+	// concurrency check: UpdateVideosTx only applies the update if the
+	// row's current version column still matches, returning ErrConflict
+	// otherwise. This stops a late-arriving write (e.g. a failure reported
+	// by an abandoned job) from clobbering a newer one (e.g. a retry that
+	// already wrote StatusReady). Zero skips the check.
+	Version int
+}
+
+// Rendition is one per-variant HLS output the transcoder writes after an
+// ffmpeg ladder finishes.
+type Rendition struct {
+	// Resolution is the variant's frame size, e.g. "1280x720".
+	Resolution string
+	// BitrateKbps is the variant's target video bitrate in kbit/s.
+	BitrateKbps int
+	// Path is the gs:// path to the variant's media playlist.
+	Path string
+	// Codec is the variant's video codec, e.g. "h264", "av1".
+	Codec string
 }
 
 // Querier abstracts *sql.DB so that tests can inject a stub.
 type Querier interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 // Repository updates video records via the Querier.
 type Repository struct {
-	db Querier
+	db  Querier
+	uow *repository.UnitOfWork
 }
 
-// NewRepository constructs a Repository backed by the provided Querier.
+// NewRepository constructs a Repository backed by the provided Querier. If db
+// is a *sql.DB, UpdateVideosTx and UpsertRenditions run inside a transaction
+// (see repository.UnitOfWork); otherwise — e.g. a stub in tests — they apply
+// each row directly through db, un-batched.
 func NewRepository(db Querier) *Repository {
-	return &Repository{db: db}
+	r := &Repository{db: db}
+	if sqlDB, ok := db.(*sql.DB); ok {
+		r.uow = repository.NewUnitOfWork(sqlDB)
+	}
+	return r
 }
 
 // UpdateVideo applies u to the videos row identified by videoID.
@@ -42,12 +123,20 @@ func NewRepository(db Querier) *Repository {
 func (r *Repository) UpdateVideo(ctx context.Context, videoID string, u Update) error {
 	const query = `
 		UPDATE videos
-		SET hls_manifest_path = $1,
-		    thumbnail_url      = $2,
-		    status             = $3
-		WHERE id = $4`
+		SET hls_manifest_path   = $1,
+		    thumbnail_url       = $2,
+		    status              = $3,
+		    dash_manifest_path  = $4,
+		    duration_seconds    = $5,
+		    manifest_type       = $6,
+		    storyboard_vtt_path = $7,
+		    width               = $8,
+		    height              = $9,
+		    video_codec         = $10,
+		    audio_codec         = $11
+		WHERE id = $12`
 
-	res, err := r.db.ExecContext(ctx, query, u.HLSManifestPath, u.ThumbnailURL, string(u.Status), videoID)
+	res, err := r.db.ExecContext(ctx, query, u.HLSManifestPath, u.ThumbnailURL, string(u.Status), u.DASHManifestPath, u.DurationSeconds, u.ManifestType, u.StoryboardVTTPath, u.Width, u.Height, u.VideoCodec, u.AudioCodec, videoID)
 	if err != nil {
 		return fmt.Errorf("update video %s: %w", videoID, err)
 	}
@@ -61,6 +150,19 @@ func (r *Repository) UpdateVideo(ctx context.Context, videoID string, u Update)
 	return nil
 }
 
+// UpdateProgress writes the video's current transcode completion percentage
+// (0-100), so the API can surface real progress to users instead of just
+// "processing → ready". It is a best-effort call like MarkFailed: a dropped
+// progress tick should never fail an otherwise-successful transcode.
+func (r *Repository) UpdateProgress(ctx context.Context, videoID string, pct int) error {
+	const query = `UPDATE videos SET progress_pct = $1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, pct, videoID)
+	if err != nil {
+		return fmt.Errorf("update progress for video %s: %w", videoID, err)
+	}
+	return nil
+}
+
 // MarkFailed sets the video status to "failed".
 // It is a best-effort call â€” errors are returned but callers may choose to log
 // and ignore them so as not to mask the original failure.
@@ -72,3 +174,146 @@ func (r *Repository) MarkFailed(ctx context.Context, videoID string) error {
 	}
 	return nil
 }
+
+// Status returns the current status of the video row identified by
+// videoID, so a resumed job can treat an already-"ready" video as a
+// completed run and exit without re-transcoding it.
+func (r *Repository) Status(ctx context.Context, videoID string) (Status, error) {
+	const query = `SELECT status FROM videos WHERE id = $1`
+	var status string
+	if err := r.db.QueryRowContext(ctx, query, videoID).Scan(&status); err != nil {
+		return "", fmt.Errorf("status for video %s: %w", videoID, err)
+	}
+	return Status(status), nil
+}
+
+// MarkRejected sets the video status to "rejected" and records reason in
+// failure_reason, for videos that failed pre-flight probe validation rather
+// than a mid-pipeline failure. Like MarkFailed, it is a best-effort call.
+func (r *Repository) MarkRejected(ctx context.Context, videoID, reason string) error {
+	const query = `UPDATE videos SET status = $1, failure_reason = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, string(StatusRejected), reason, videoID)
+	if err != nil {
+		return fmt.Errorf("mark video %s rejected: %w", videoID, err)
+	}
+	return nil
+}
+
+// updateVideosSQL is UpdateVideosTx's per-row statement. The version check
+// is folded into the WHERE clause ($13 = 0 OR version = $13) rather than a
+// separate read, so it's enforced atomically with the write; version is
+// bumped on every successful write so a later conflicting update sees it.
+const updateVideosSQL = `
+	UPDATE videos
+	SET hls_manifest_path   = $1,
+	    thumbnail_url       = $2,
+	    status              = $3,
+	    dash_manifest_path  = $4,
+	    duration_seconds    = $5,
+	    manifest_type       = $6,
+	    storyboard_vtt_path = $7,
+	    width               = $8,
+	    height              = $9,
+	    video_codec         = $10,
+	    audio_codec         = $11,
+	    version             = version + 1
+	WHERE id = $12 AND ($13 = 0 OR version = $13)`
+
+// UpdateVideosTx applies every update in updates atomically: either all of
+// them are written, or (on the first ErrNotFound/ErrConflict/query error)
+// none are, leaving the caller free to retry the whole batch.
+func (r *Repository) UpdateVideosTx(ctx context.Context, updates []Update) error {
+	apply := func(q Querier) error {
+		for _, u := range updates {
+			if err := r.updateOne(ctx, q, u); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if r.uow == nil {
+		return apply(r.db)
+	}
+	return r.uow.WithTx(ctx, func(tx *sql.Tx) error {
+		return apply(tx)
+	})
+}
+
+// updateOne applies a single Update via q and classifies a no-op write as
+// ErrNotFound (no such video) or ErrConflict (video exists, but Version is
+// stale).
+func (r *Repository) updateOne(ctx context.Context, q Querier, u Update) error {
+	res, err := q.ExecContext(ctx, updateVideosSQL,
+		u.HLSManifestPath, u.ThumbnailURL, string(u.Status), u.DASHManifestPath,
+		u.DurationSeconds, u.ManifestType, u.StoryboardVTTPath, u.Width, u.Height,
+		u.VideoCodec, u.AudioCodec, u.VideoID, u.Version)
+	if err != nil {
+		return fmt.Errorf("update video %s: %w", u.VideoID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for video %s: %w", u.VideoID, err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	exists, err := r.rowExists(ctx, q, u.VideoID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: video %s", ErrNotFound, u.VideoID)
+	}
+	return fmt.Errorf("%w: video %s", ErrConflict, u.VideoID)
+}
+
+// rowExists reports whether videoID names an existing video row, so
+// updateOne can tell a missing row from a stale version after a 0-row
+// update.
+func (r *Repository) rowExists(ctx context.Context, q Querier, videoID string) (bool, error) {
+	const query = `SELECT 1 FROM videos WHERE id = $1`
+	var one int
+	err := q.QueryRowContext(ctx, query, videoID).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check video %s exists: %w", videoID, err)
+	}
+	return true, nil
+}
+
+// upsertRenditionSQL upserts one Rendition, keyed on (video_id, resolution)
+// so re-running the same ffmpeg ladder (e.g. on retry) overwrites rather
+// than duplicates a variant's row.
+const upsertRenditionSQL = `
+	INSERT INTO video_renditions (video_id, resolution, bitrate_kbps, path, codec)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (video_id, resolution) DO UPDATE
+	SET bitrate_kbps = EXCLUDED.bitrate_kbps,
+	    path         = EXCLUDED.path,
+	    codec        = EXCLUDED.codec`
+
+// UpsertRenditions writes renditions for videoID in a single transaction, so
+// a retry of the ffmpeg ladder never leaves players reading a half-written
+// set of variants.
+func (r *Repository) UpsertRenditions(ctx context.Context, videoID string, renditions []Rendition) error {
+	apply := func(q Querier) error {
+		for _, rend := range renditions {
+			_, err := q.ExecContext(ctx, upsertRenditionSQL, videoID, rend.Resolution, rend.BitrateKbps, rend.Path, rend.Codec)
+			if err != nil {
+				return fmt.Errorf("upsert rendition %s for video %s: %w", rend.Resolution, videoID, err)
+			}
+		}
+		return nil
+	}
+
+	if r.uow == nil {
+		return apply(r.db)
+	}
+	return r.uow.WithTx(ctx, func(tx *sql.Tx) error {
+		return apply(tx)
+	})
+}
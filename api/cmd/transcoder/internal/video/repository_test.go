@@ -3,12 +3,94 @@ package video_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
 	"testing"
 
 	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/video"
 )
 
+// ── minimal fake SQL driver, for stubbing QueryRowContext ─────────────────────
+// Registers a "video-fakedb" driver that allows tests to return a
+// pre-configured status column without a real database.
+
+func init() {
+	sql.Register("video-fakedb", &fakeDriver{})
+}
+
+var dsnCounter int64
+
+func nextDSN() string {
+	n := atomic.AddInt64(&dsnCounter, 1)
+	return fmt.Sprintf("video-dsn-%d", n)
+}
+
+var statusRegistry = map[string]string{}
+
+type fakeDriver struct{}
+
+func (*fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{status: statusRegistry[name]}, nil
+}
+
+type fakeConn struct{ status string }
+
+func (c *fakeConn) Prepare(_ string) (driver.Stmt, error) { return &fakeStmt{status: c.status}, nil }
+func (c *fakeConn) Close() error                          { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)             { return &fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (*fakeTx) Commit() error   { return nil }
+func (*fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct{ status string }
+
+func (*fakeStmt) Close() error  { return nil }
+func (*fakeStmt) NumInput() int { return -1 }
+func (*fakeStmt) Exec(_ []driver.Value) (driver.Result, error) {
+	return fakeDriverResult{}, nil
+}
+func (s *fakeStmt) Query(_ []driver.Value) (driver.Rows, error) {
+	return &fakeRows{status: s.status}, nil
+}
+
+type fakeDriverResult struct{}
+
+func (fakeDriverResult) LastInsertId() (int64, error) { return 0, nil }
+func (fakeDriverResult) RowsAffected() (int64, error) { return 1, nil }
+
+// fakeRows yields a single "status" column row, or no rows at all when
+// status is empty, so tests can exercise both the found and sql.ErrNoRows
+// paths.
+type fakeRows struct {
+	status string
+	read   bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"status"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.status == "" || r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = r.status
+	return nil
+}
+
+// statusDB returns a *sql.DB whose QueryRowContext yields status (or no rows
+// when status is "").
+func statusDB(status string) *sql.DB {
+	dsn := nextDSN()
+	statusRegistry[dsn] = status
+	db, _ := sql.Open("video-fakedb", dsn)
+	return db
+}
+
 // ── stubQuerier ───────────────────────────────────────────────────────────────
 
 type stubQuerier struct {
@@ -17,15 +99,46 @@ type stubQuerier struct {
 	execErr      error
 	lastQuery    string
 	lastArgs     []interface{}
+	// status backs QueryRowContext for TestStatus* cases.
+	status string
+
+	// execCalls records every ExecContext call's args in order, for
+	// UpdateVideosTx/UpsertRenditions tests that apply more than one row per
+	// call; lastArgs above keeps reflecting only the most recent call.
+	execCalls [][]interface{}
+	// rowsAffectedQueue, when non-empty, supplies a per-call RowsAffected
+	// value, consumed in FIFO order, so a bulk test can make only some of
+	// several rows match. Falls back to rowsAffected once exhausted.
+	rowsAffectedQueue []int64
+	// existsOverride, when set, controls rowExists's outcome directly for
+	// UpdateVideosTx's ErrNotFound/ErrConflict tests, instead of reusing
+	// status.
+	existsOverride *bool
 }
 
 func (s *stubQuerier) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
 	s.lastQuery = query
 	s.lastArgs = args
+	s.execCalls = append(s.execCalls, args)
 	if s.execErr != nil {
 		return nil, s.execErr
 	}
-	return &stubResult{rowsAffected: s.rowsAffected, err: s.rowsErr}, nil
+	affected := s.rowsAffected
+	if len(s.rowsAffectedQueue) > 0 {
+		affected = s.rowsAffectedQueue[0]
+		s.rowsAffectedQueue = s.rowsAffectedQueue[1:]
+	}
+	return &stubResult{rowsAffected: affected, err: s.rowsErr}, nil
+}
+
+func (s *stubQuerier) QueryRowContext(ctx context.Context, _ string, _ ...interface{}) *sql.Row {
+	if s.existsOverride != nil {
+		if *s.existsOverride {
+			return statusDB("1").QueryRowContext(ctx, "SELECT status")
+		}
+		return statusDB("").QueryRowContext(ctx, "SELECT status")
+	}
+	return statusDB(s.status).QueryRowContext(ctx, "SELECT status")
 }
 
 type stubResult struct {
@@ -106,6 +219,92 @@ func TestUpdateVideo_PassesStatusReady(t *testing.T) {
 	}
 }
 
+func TestUpdateVideo_PassesDASHManifestPath(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpdateVideo(context.Background(), "vid", video.Update{
+		DASHManifestPath: "gs://hls-bucket/videos/vid/manifest.mpd",
+		Status:           video.StatusReady,
+	})
+
+	if len(q.lastArgs) < 4 || q.lastArgs[3] != "gs://hls-bucket/videos/vid/manifest.mpd" {
+		t.Errorf("DASHManifestPath not passed correctly, args = %v", q.lastArgs)
+	}
+}
+
+func TestUpdateVideo_PassesDurationSeconds(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpdateVideo(context.Background(), "vid", video.Update{
+		Status:          video.StatusReady,
+		DurationSeconds: 125,
+	})
+
+	if len(q.lastArgs) < 5 || q.lastArgs[4] != 125 {
+		t.Errorf("DurationSeconds not passed correctly, args = %v", q.lastArgs)
+	}
+}
+
+func TestUpdateVideo_PassesManifestType(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpdateVideo(context.Background(), "vid", video.Update{
+		Status:       video.StatusReady,
+		ManifestType: video.ManifestTypeBoth,
+	})
+
+	if len(q.lastArgs) < 6 || q.lastArgs[5] != video.ManifestTypeBoth {
+		t.Errorf("ManifestType not passed correctly, args = %v", q.lastArgs)
+	}
+}
+
+func TestUpdateVideo_PassesStoryboardVTTPath(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpdateVideo(context.Background(), "vid", video.Update{
+		Status:            video.StatusReady,
+		StoryboardVTTPath: "gs://hls-bucket/videos/vid/storyboard/storyboard.vtt",
+	})
+
+	if len(q.lastArgs) < 7 || q.lastArgs[6] != "gs://hls-bucket/videos/vid/storyboard/storyboard.vtt" {
+		t.Errorf("StoryboardVTTPath not passed correctly, args = %v", q.lastArgs)
+	}
+}
+
+func TestUpdateVideo_PassesWidthAndHeight(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpdateVideo(context.Background(), "vid", video.Update{
+		Status: video.StatusReady,
+		Width:  1920,
+		Height: 1080,
+	})
+
+	if len(q.lastArgs) < 9 || q.lastArgs[7] != 1920 || q.lastArgs[8] != 1080 {
+		t.Errorf("Width/Height not passed correctly, args = %v", q.lastArgs)
+	}
+}
+
+func TestUpdateVideo_PassesVideoCodecAndAudioCodec(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpdateVideo(context.Background(), "vid", video.Update{
+		Status:     video.StatusReady,
+		VideoCodec: "h264",
+		AudioCodec: "aac",
+	})
+
+	if len(q.lastArgs) < 11 || q.lastArgs[9] != "h264" || q.lastArgs[10] != "aac" {
+		t.Errorf("VideoCodec/AudioCodec not passed correctly, args = %v", q.lastArgs)
+	}
+}
+
 func TestUpdateVideo_PassesVideoID(t *testing.T) {
 	q := &stubQuerier{rowsAffected: 1}
 	repo := video.NewRepository(q)
@@ -114,7 +313,7 @@ func TestUpdateVideo_PassesVideoID(t *testing.T) {
 		Status: video.StatusReady,
 	})
 
-	if len(q.lastArgs) < 4 || q.lastArgs[3] != "expected-id" {
+	if len(q.lastArgs) < 12 || q.lastArgs[11] != "expected-id" {
 		t.Errorf("video ID not passed as last arg, args = %v", q.lastArgs)
 	}
 }
@@ -149,6 +348,37 @@ func TestUpdateVideo_RowsAffectedError_ReturnsError(t *testing.T) {
 	}
 }
 
+// ── UpdateProgress ────────────────────────────────────────────────────────────
+
+func TestUpdateProgress_Success(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	if err := repo.UpdateProgress(context.Background(), "vid", 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateProgress_PassesPercentAndVideoID(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpdateProgress(context.Background(), "vid", 42)
+
+	if len(q.lastArgs) != 2 || q.lastArgs[0] != 42 || q.lastArgs[1] != "vid" {
+		t.Errorf("args = %v, want [42 vid]", q.lastArgs)
+	}
+}
+
+func TestUpdateProgress_ExecError_ReturnsError(t *testing.T) {
+	q := &stubQuerier{execErr: errors.New("db error")}
+	repo := video.NewRepository(q)
+
+	if err := repo.UpdateProgress(context.Background(), "vid", 42); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
 // ── MarkFailed ────────────────────────────────────────────────────────────────
 
 func TestMarkFailed_Success(t *testing.T) {
@@ -191,6 +421,84 @@ func TestMarkFailed_ExecError_ReturnsError(t *testing.T) {
 	}
 }
 
+// ── MarkRejected ──────────────────────────────────────────────────────────────
+
+func TestMarkRejected_Success(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	if err := repo.MarkRejected(context.Background(), "vid", "no video stream"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMarkRejected_PassesStatusRejected(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.MarkRejected(context.Background(), "vid", "no video stream")
+
+	if len(q.lastArgs) < 1 || q.lastArgs[0] != "rejected" {
+		t.Errorf("status arg = %v, want 'rejected'", q.lastArgs)
+	}
+}
+
+func TestMarkRejected_PassesReason(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.MarkRejected(context.Background(), "vid", "duration 7200s exceeds maximum of 3600s")
+
+	if len(q.lastArgs) < 2 || q.lastArgs[1] != "duration 7200s exceeds maximum of 3600s" {
+		t.Errorf("reason arg = %v, want the rejection reason", q.lastArgs)
+	}
+}
+
+func TestMarkRejected_PassesVideoID(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.MarkRejected(context.Background(), "expected-id", "no video stream")
+
+	if len(q.lastArgs) < 3 || q.lastArgs[2] != "expected-id" {
+		t.Errorf("video ID arg = %v, want 'expected-id'", q.lastArgs)
+	}
+}
+
+func TestMarkRejected_ExecError_ReturnsError(t *testing.T) {
+	q := &stubQuerier{execErr: errors.New("db down")}
+	repo := video.NewRepository(q)
+
+	if err := repo.MarkRejected(context.Background(), "vid", "no video stream"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// ── Status ────────────────────────────────────────────────────────────────────
+
+func TestStatus_ReturnsReady(t *testing.T) {
+	q := &stubQuerier{status: "ready"}
+	repo := video.NewRepository(q)
+
+	status, err := repo.Status(context.Background(), "vid")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != video.StatusReady {
+		t.Errorf("Status() = %q, want %q", status, video.StatusReady)
+	}
+}
+
+func TestStatus_NotFound_ReturnsError(t *testing.T) {
+	q := &stubQuerier{status: ""}
+	repo := video.NewRepository(q)
+
+	_, err := repo.Status(context.Background(), "missing-id")
+	if err == nil {
+		t.Fatal("expected error for a video with no row")
+	}
+}
+
 // ── Status constants ──────────────────────────────────────────────────────────
 
 func TestStatusConstants(t *testing.T) {
@@ -200,4 +508,168 @@ func TestStatusConstants(t *testing.T) {
 	if string(video.StatusFailed) != "failed" {
 		t.Errorf("StatusFailed = %q, want %q", video.StatusFailed, "failed")
 	}
+	if string(video.StatusRejected) != "rejected" {
+		t.Errorf("StatusRejected = %q, want %q", video.StatusRejected, "rejected")
+	}
+}
+
+// ── UpdateVideosTx ────────────────────────────────────────────────────────────
+
+func TestUpdateVideosTx_Success_AppliesEveryRow(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	err := repo.UpdateVideosTx(context.Background(), []video.Update{
+		{VideoID: "vid-1", Status: video.StatusReady},
+		{VideoID: "vid-2", Status: video.StatusFailed},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(q.execCalls) != 2 {
+		t.Fatalf("expected 2 ExecContext calls, got %d", len(q.execCalls))
+	}
+}
+
+func TestUpdateVideosTx_PassesVideoIDAndVersion(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpdateVideosTx(context.Background(), []video.Update{
+		{VideoID: "vid-1", Status: video.StatusReady, Version: 3},
+	})
+
+	if len(q.lastArgs) < 13 || q.lastArgs[11] != "vid-1" || q.lastArgs[12] != 3 {
+		t.Errorf("VideoID/Version not passed correctly, args = %v", q.lastArgs)
+	}
+}
+
+func TestUpdateVideosTx_ExecError_StopsAndReturnsError(t *testing.T) {
+	q := &stubQuerier{execErr: errors.New("db down")}
+	repo := video.NewRepository(q)
+
+	err := repo.UpdateVideosTx(context.Background(), []video.Update{
+		{VideoID: "vid-1", Status: video.StatusReady},
+		{VideoID: "vid-2", Status: video.StatusReady},
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(q.execCalls) != 1 {
+		t.Errorf("expected the second row to be skipped after the first error, got %d calls", len(q.execCalls))
+	}
+}
+
+func TestUpdateVideosTx_RowMissing_ReturnsErrNotFound(t *testing.T) {
+	missing := false
+	q := &stubQuerier{rowsAffected: 0, existsOverride: &missing}
+	repo := video.NewRepository(q)
+
+	err := repo.UpdateVideosTx(context.Background(), []video.Update{
+		{VideoID: "missing-id", Status: video.StatusReady},
+	})
+	if !errors.Is(err, video.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateVideosTx_StaleVersion_ReturnsErrConflict(t *testing.T) {
+	exists := true
+	q := &stubQuerier{rowsAffected: 0, existsOverride: &exists}
+	repo := video.NewRepository(q)
+
+	err := repo.UpdateVideosTx(context.Background(), []video.Update{
+		{VideoID: "vid-1", Status: video.StatusReady, Version: 2},
+	})
+	if !errors.Is(err, video.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestUpdateVideosTx_RowsAffectedError_ReturnsError(t *testing.T) {
+	q := &stubQuerier{rowsAffectedQueue: []int64{0}, rowsErr: errors.New("rows affected error")}
+	repo := video.NewRepository(q)
+
+	err := repo.UpdateVideosTx(context.Background(), []video.Update{
+		{VideoID: "vid-1", Status: video.StatusReady},
+	})
+	if err == nil {
+		t.Fatal("expected error when RowsAffected fails")
+	}
+}
+
+// TestUpdateVideosTx_RunsWhenBackedByRealDB exercises the UnitOfWork path
+// (NewRepository given a *sql.DB rather than a stub).
+func TestUpdateVideosTx_RunsWhenBackedByRealDB(t *testing.T) {
+	db := statusDB("ready")
+	repo := video.NewRepository(db)
+
+	err := repo.UpdateVideosTx(context.Background(), []video.Update{
+		{VideoID: "vid-1", Status: video.StatusReady},
+		{VideoID: "vid-2", Status: video.StatusReady},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// ── UpsertRenditions ──────────────────────────────────────────────────────────
+
+func TestUpsertRenditions_Success_AppliesEveryRendition(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	err := repo.UpsertRenditions(context.Background(), "vid-1", []video.Rendition{
+		{Resolution: "1280x720", BitrateKbps: 2500, Path: "gs://b/720p.m3u8", Codec: "h264"},
+		{Resolution: "1920x1080", BitrateKbps: 5000, Path: "gs://b/1080p.m3u8", Codec: "h264"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(q.execCalls) != 2 {
+		t.Fatalf("expected 2 ExecContext calls, got %d", len(q.execCalls))
+	}
+}
+
+func TestUpsertRenditions_PassesFields(t *testing.T) {
+	q := &stubQuerier{rowsAffected: 1}
+	repo := video.NewRepository(q)
+
+	_ = repo.UpsertRenditions(context.Background(), "vid-1", []video.Rendition{
+		{Resolution: "1280x720", BitrateKbps: 2500, Path: "gs://b/720p.m3u8", Codec: "h264"},
+	})
+
+	if len(q.lastArgs) < 5 {
+		t.Fatalf("expected 5 args, got %v", q.lastArgs)
+	}
+	if q.lastArgs[0] != "vid-1" || q.lastArgs[1] != "1280x720" || q.lastArgs[2] != 2500 ||
+		q.lastArgs[3] != "gs://b/720p.m3u8" || q.lastArgs[4] != "h264" {
+		t.Errorf("rendition fields not passed correctly, args = %v", q.lastArgs)
+	}
+}
+
+func TestUpsertRenditions_ExecError_ReturnsError(t *testing.T) {
+	q := &stubQuerier{execErr: errors.New("db down")}
+	repo := video.NewRepository(q)
+
+	err := repo.UpsertRenditions(context.Background(), "vid-1", []video.Rendition{
+		{Resolution: "1280x720"},
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// TestUpsertRenditions_RunsWhenBackedByRealDB exercises the UnitOfWork path
+// (NewRepository given a *sql.DB rather than a stub).
+func TestUpsertRenditions_RunsWhenBackedByRealDB(t *testing.T) {
+	db := statusDB("ready")
+	repo := video.NewRepository(db)
+
+	err := repo.UpsertRenditions(context.Background(), "vid-1", []video.Rendition{
+		{Resolution: "1280x720", BitrateKbps: 2500, Path: "gs://b/720p.m3u8", Codec: "h264"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 }
@@ -1,5 +1,10 @@
 // Command transcoder is the Cloud Run Job that transcodes a raw video into
-// multi-bitrate HLS, extracts a thumbnail, and updates the database record.
+// multi-bitrate HLS and a sibling MPEG-DASH manifest sharing the same CMAF
+// segments, extracts a thumbnail, and updates the database record. The
+// rendition ladder itself is content-aware: it is derived per-video from an
+// ffprobe analysis of the source (see ffmpeg.AnalyzeSource/LadderFor),
+// falling back to the fixed ffmpeg.DefaultRenditions() if that analysis
+// fails.
 //
 // Required environment variables:
 //
@@ -10,6 +15,38 @@
 //	CDN_BASE_URL    — base URL for constructing the thumbnail_url written to the DB
 //	                  (e.g. https://cdn.example.com)
 //
+// Object storage backend (see api/internal/blob for the full variable list):
+//
+//	BLOB_BACKEND — "gcs" (default), "s3", or "s3compat" for MinIO/B2/Spaces
+//
+// Encoder backend (optional — see api/cmd/transcoder/internal/ffmpeg):
+//
+//	FFMPEG_ENCODER — "x264" (default/software), "nvenc", "vaapi", or "qsv" to
+//	                 force a backend; unset auto-detects the best one
+//	                 "ffmpeg -encoders" reports available.
+//
+// Output formats (optional — see api/cmd/transcoder/internal/dash):
+//
+//	TRANSCODE_FORMATS — "hls" or "dash" to emit only that manifest format;
+//	                    unset/anything else emits both (default).
+//
+// Pre-flight probe policy (optional — see api/cmd/transcoder/internal/probe):
+//
+//	MAX_DURATION_SECONDS — reject uploads longer than this (0/unset = no limit)
+//	MAX_INPUT_HEIGHT     — reject uploads taller than this (0/unset = no limit)
+//	ALLOWED_VIDEO_CODECS — comma-separated codec allow-list (unset = allow any)
+//
+// Resumable jobs (optional — see api/cmd/transcoder/internal/checkpoint):
+//
+//	FORCE_REPROCESS — "true" clears this video's checkpoints and re-runs
+//	                  every stage, ignoring any prior completed attempt
+//
+// Lifecycle events (optional — see api/internal/events):
+//
+//	EVENTS_TOPIC   — Pub/Sub topic name to publish pipeline-stage events to.
+//	                 Events are silently dropped when unset.
+//	GCP_PROJECT_ID — GCP project containing EVENTS_TOPIC; required if set.
+//
 // Database connection (same as api service, using Cloud SQL Unix socket):
 //
 //	INSTANCE_UNIX_SOCKET — Cloud SQL Unix socket path (when running on Cloud Run)
@@ -18,16 +55,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
-	"cloud.google.com/go/storage"
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/checkpoint"
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/dash"
 	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/ffmpeg"
-	gcsStorage "github.com/ai-teammate/mytube/api/cmd/transcoder/internal/storage"
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/probe"
 	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/video"
+	"github.com/ai-teammate/mytube/api/internal/blob"
 	"github.com/ai-teammate/mytube/api/internal/database"
+	"github.com/ai-teammate/mytube/api/internal/events"
 )
 
 func main() {
@@ -45,12 +88,12 @@ func run() error {
 
 	ctx := context.Background()
 
-	// Initialise GCS client.
-	gcsClient, err := storage.NewClient(ctx)
+	// Initialise the configured object-storage backend (GCS by default; set
+	// BLOB_BACKEND=s3 or s3compat to target AWS S3, MinIO, Backblaze B2, etc.).
+	backend, err := blob.Open(ctx, blob.ConfigFromEnv())
 	if err != nil {
-		return fmt.Errorf("create GCS client: %w", err)
+		return fmt.Errorf("open blob backend: %w", err)
 	}
-	defer gcsClient.Close()
 
 	// Initialise database.
 	db, err := database.Open()
@@ -61,11 +104,61 @@ func run() error {
 
 	repo := video.NewRepository(db)
 
-	downloader := gcsStorage.NewDownloader(gcsStorage.NewGCSObjectReader(gcsClient))
-	uploader := gcsStorage.NewUploader(gcsStorage.NewGCSObjectWriter(gcsClient))
+	publisher, err := events.NewPublisherFromEnv(ctx)
+	if err != nil {
+		return fmt.Errorf("configure events publisher: %w", err)
+	}
+
+	downloader := blob.NewDownloader(backend)
+	uploader := blob.NewUploader(backend)
+	prober := probe.NewProber(probe.PolicyFromEnv())
+	checkpoints := checkpoint.NewBlobStore(backend, cfg.HLSBucket)
 	ffmpegRunner := ffmpeg.NewRunner()
+	ffmpegRunner.Encoder = ffmpeg.DetectEncoder(ctx)
+	log.Printf("using %s encoder", ffmpegRunner.Encoder.Name())
+	ffmpegRunner.OnProgress = throttledProgressPublisher(ctx, publisher, cfg.VideoID, 5*time.Second)
+	ffmpegRunner.OnRenditionDone = func(rend ffmpeg.Rendition) {
+		if err := checkpoints.MarkDone(ctx, cfg.VideoID, checkpoint.RenditionStage(rend.Name)); err != nil {
+			log.Printf("warning: mark rendition %s checkpoint for video %s: %v", rend.Name, cfg.VideoID, err)
+		}
+		if err := publisher.Publish(ctx, events.Event{
+			VideoID:   cfg.VideoID,
+			Stage:     events.StageRenditionComplete,
+			Timestamp: time.Now(),
+			Rendition: rend.Name,
+		}); err != nil {
+			log.Printf("warning: publish rendition_complete event for video %s: %v", cfg.VideoID, err)
+		}
+	}
+
+	return transcode(ctx, cfg, downloader, uploader, prober, ffmpegRunner, repo, publisher, checkpoints)
+}
+
+// TranscodeFormat selects which manifest format(s) a transcode run produces
+// over the shared CMAF segments EncodeRenditions writes, via TRANSCODE_FORMATS.
+type TranscodeFormat string
+
+const (
+	// FormatHLS writes only the HLS master/variant playlists.
+	FormatHLS TranscodeFormat = "hls"
+	// FormatDASH writes only the MPEG-DASH manifest.
+	FormatDASH TranscodeFormat = "dash"
+	// FormatBoth writes both, the default.
+	FormatBoth TranscodeFormat = "both"
+)
 
-	return transcode(ctx, cfg, downloader, uploader, ffmpegRunner, repo)
+// formatFromEnv reads TRANSCODE_FORMATS, defaulting to FormatBoth for an
+// unset or unrecognised value so a typo never silently drops a manifest
+// format operators are relying on.
+func formatFromEnv() TranscodeFormat {
+	switch TranscodeFormat(os.Getenv("TRANSCODE_FORMATS")) {
+	case FormatHLS:
+		return FormatHLS
+	case FormatDASH:
+		return FormatDASH
+	default:
+		return FormatBoth
+	}
 }
 
 // config holds the job configuration derived from environment variables.
@@ -75,6 +168,12 @@ type config struct {
 	RawObjectPath string
 	HLSBucket     string
 	CDNBaseURL    string
+	// ForceReprocess is FORCE_REPROCESS=true: clear this video's checkpoints
+	// and re-run every stage instead of resuming a prior attempt.
+	ForceReprocess bool
+	// Formats selects which manifest format(s) this run produces; see
+	// TranscodeFormat.
+	Formats TranscodeFormat
 }
 
 // configFromEnv reads required environment variables into a config.
@@ -93,19 +192,25 @@ func configFromEnv() (config, error) {
 		}
 	}
 	return config{
-		VideoID:       *vars["VIDEO_ID"],
-		RawBucket:     *vars["RAW_BUCKET"],
-		RawObjectPath: *vars["RAW_OBJECT_PATH"],
-		HLSBucket:     *vars["HLS_BUCKET"],
-		CDNBaseURL:    *vars["CDN_BASE_URL"],
+		VideoID:        *vars["VIDEO_ID"],
+		RawBucket:      *vars["RAW_BUCKET"],
+		RawObjectPath:  *vars["RAW_OBJECT_PATH"],
+		HLSBucket:      *vars["HLS_BUCKET"],
+		CDNBaseURL:     *vars["CDN_BASE_URL"],
+		ForceReprocess: os.Getenv("FORCE_REPROCESS") == "true",
+		Formats:        formatFromEnv(),
 	}, nil
 }
 
 // Transcoder groups the dependencies needed for a single transcoding run.
-// It is defined here to support dependency injection in tests.
+// It is defined here to support dependency injection in tests. It takes
+// EncodeRenditions rather than TranscodeHLS so doTranscode can pass only the
+// renditions a resumed job still needs to encode and write the master
+// playlist itself over the full rendition set (see ffmpeg.WriteMasterPlaylist).
 type Transcoder interface {
-	TranscodeHLS(ctx context.Context, inputPath, outputDir string, renditions []ffmpeg.Rendition) error
+	EncodeRenditions(ctx context.Context, inputPath, outputDir string, renditions []ffmpeg.Rendition) error
 	ExtractThumbnail(ctx context.Context, inputPath, destPath string, offsetSeconds int) error
+	ExtractStoryboard(ctx context.Context, inputPath, outDir string, opts ffmpeg.StoryboardOptions) (*ffmpeg.StoryboardIndex, error)
 }
 
 // FileDownloader downloads a raw GCS object to disk.
@@ -113,6 +218,11 @@ type FileDownloader interface {
 	Download(ctx context.Context, bucket, objectPath, destPath string) error
 }
 
+// Prober inspects a downloaded raw file and rejects it if it fails policy.
+type Prober interface {
+	Probe(ctx context.Context, path string) (probe.MediaInfo, error)
+}
+
 // DirUploader uploads local files to GCS.
 type DirUploader interface {
 	UploadFile(ctx context.Context, bucket, objectPath, srcPath string) error
@@ -121,40 +231,72 @@ type DirUploader interface {
 
 // VideoRepository updates video records in the database.
 type VideoRepository interface {
+	Status(ctx context.Context, videoID string) (video.Status, error)
 	UpdateVideo(ctx context.Context, videoID string, u video.Update) error
+	UpdateProgress(ctx context.Context, videoID string, pct int) error
 	MarkFailed(ctx context.Context, videoID string) error
+	MarkRejected(ctx context.Context, videoID, reason string) error
 }
 
 // transcode executes the full transcoding pipeline for one video.
 // The working directory is a temporary directory that is cleaned up on return.
-// On any failure, transcode makes a best-effort call to repo.MarkFailed before
-// returning the original error.
+// On any failure, transcode makes a best-effort call to repo.MarkFailed (or,
+// for a file that failed pre-flight probe validation, repo.MarkRejected)
+// before returning the original error.
 func transcode(
 	ctx context.Context,
 	cfg config,
 	dl FileDownloader,
 	ul DirUploader,
+	pr Prober,
 	tr Transcoder,
 	repo VideoRepository,
+	pub events.Publisher,
+	cp checkpoint.Store,
 ) error {
-	err := doTranscode(ctx, cfg, dl, ul, tr, repo)
+	err := doTranscode(ctx, cfg, dl, ul, pr, tr, repo, pub, cp)
 	if err != nil {
+		var rejErr *probe.RejectedError
+		if errors.As(err, &rejErr) {
+			if markErr := repo.MarkRejected(ctx, cfg.VideoID, rejErr.Reason); markErr != nil {
+				log.Printf("warning: could not mark video %s as rejected: %v", cfg.VideoID, markErr)
+			}
+			publish(ctx, pub, events.Event{VideoID: cfg.VideoID, Stage: events.StageRejected, Error: err.Error()})
+			return err
+		}
 		if markErr := repo.MarkFailed(ctx, cfg.VideoID); markErr != nil {
 			log.Printf("warning: could not mark video %s as failed: %v", cfg.VideoID, markErr)
 		}
+		publish(ctx, pub, events.Event{VideoID: cfg.VideoID, Stage: events.StageFailed, Error: err.Error()})
 	}
 	return err
 }
 
-// doTranscode contains the core pipeline steps.
+// doTranscode contains the core pipeline steps. Each stage is checkpointed
+// via cp so a retried Cloud Run Job execution can skip the stages a prior
+// attempt already completed rather than redoing the whole pipeline (see
+// api/cmd/transcoder/internal/checkpoint).
 func doTranscode(
 	ctx context.Context,
 	cfg config,
 	dl FileDownloader,
 	ul DirUploader,
+	pr Prober,
 	tr Transcoder,
 	repo VideoRepository,
+	pub events.Publisher,
+	cp checkpoint.Store,
 ) error {
+	if cfg.ForceReprocess {
+		log.Printf("FORCE_REPROCESS set for video %s; clearing checkpoints", cfg.VideoID)
+		if err := cp.Clear(ctx, cfg.VideoID, allCheckpointStages(ffmpeg.DefaultRenditions())); err != nil {
+			return fmt.Errorf("clear checkpoints: %w", err)
+		}
+	} else if status, err := repo.Status(ctx, cfg.VideoID); err == nil && status == video.StatusReady {
+		log.Printf("video %s is already ready; nothing to do", cfg.VideoID)
+		return nil
+	}
+
 	// Create a temporary working directory.
 	workDir, err := os.MkdirTemp("", "transcoder-"+cfg.VideoID+"-*")
 	if err != nil {
@@ -163,10 +305,36 @@ func doTranscode(
 	defer os.RemoveAll(workDir)
 
 	// ── Step 1: Download raw file ─────────────────────────────────────────────
+	// rawPath lives under workDir, a fresh MkdirTemp directory on every call
+	// that is removed on return, so this checkpoint is never actually hit —
+	// the file is always gone, on a Cloud Run Job retry (a fresh container)
+	// and on a second call in the same process alike. It is still recorded
+	// for parity with the other stages.
 	rawPath := filepath.Join(workDir, "raw_input"+filepath.Ext(cfg.RawObjectPath))
-	log.Printf("downloading gs://%s/%s → %s", cfg.RawBucket, cfg.RawObjectPath, rawPath)
-	if err := dl.Download(ctx, cfg.RawBucket, cfg.RawObjectPath, rawPath); err != nil {
-		return fmt.Errorf("download raw file: %w", err)
+	downloadDone, err := cp.Done(ctx, cfg.VideoID, checkpoint.StageDownload)
+	if err != nil {
+		return fmt.Errorf("check download checkpoint: %w", err)
+	}
+	if downloadDone && fileExists(rawPath) {
+		log.Printf("download already checkpointed for video %s; skipping", cfg.VideoID)
+	} else {
+		publish(ctx, pub, events.Event{VideoID: cfg.VideoID, Stage: events.StageDownloadStarted, Progress: 0})
+		log.Printf("downloading gs://%s/%s → %s", cfg.RawBucket, cfg.RawObjectPath, rawPath)
+		if err := dl.Download(ctx, cfg.RawBucket, cfg.RawObjectPath, rawPath); err != nil {
+			return fmt.Errorf("download raw file: %w", err)
+		}
+		if err := cp.MarkDone(ctx, cfg.VideoID, checkpoint.StageDownload); err != nil {
+			log.Printf("warning: mark download checkpoint for video %s: %v", cfg.VideoID, err)
+		}
+		publish(ctx, pub, events.Event{VideoID: cfg.VideoID, Stage: events.StageDownloadComplete, Progress: 0.2})
+		reportProgress(ctx, repo, cfg.VideoID, 20)
+	}
+
+	// ── Step 1b: Probe and validate the downloaded file ───────────────────────
+	log.Printf("probing %s", rawPath)
+	info, err := pr.Probe(ctx, rawPath)
+	if err != nil {
+		return fmt.Errorf("probe raw file: %w", err)
 	}
 
 	// ── Step 2: Transcode to HLS ──────────────────────────────────────────────
@@ -174,45 +342,296 @@ func doTranscode(
 	if err := os.MkdirAll(hlsDir, 0o755); err != nil {
 		return fmt.Errorf("create hls dir: %w", err)
 	}
-	log.Printf("transcoding %s → %s (HLS)", rawPath, hlsDir)
-	if err := tr.TranscodeHLS(ctx, rawPath, hlsDir, ffmpeg.DefaultRenditions()); err != nil {
-		return fmt.Errorf("transcode HLS: %w", err)
+	renditions := renditionLadder(ctx, rawPath, info.Height)
+	pending, err := pendingRenditions(ctx, cp, cfg.VideoID, hlsDir, renditions)
+	if err != nil {
+		return fmt.Errorf("check rendition checkpoints: %w", err)
+	}
+	if len(pending) == 0 {
+		log.Printf("all renditions already checkpointed for video %s; skipping encode", cfg.VideoID)
+	} else {
+		log.Printf("transcoding %s → %s (HLS), %d/%d renditions pending", rawPath, hlsDir, len(pending), len(renditions))
+		if err := tr.EncodeRenditions(ctx, rawPath, hlsDir, pending); err != nil {
+			return fmt.Errorf("transcode HLS: %w", err)
+		}
+	}
+	// The master playlist is written over the full rendition set so it still
+	// references renditions a prior attempt already encoded and uploaded,
+	// even though this run's hlsDir only contains the ones it re-encoded.
+	// Both branches read the same CMAF segments EncodeRenditions just wrote;
+	// cfg.Formats only decides which manifest(s) sit on top of them.
+	if cfg.Formats != FormatDASH {
+		if err := ffmpeg.WriteMasterPlaylist(hlsDir, renditions); err != nil {
+			return fmt.Errorf("write master playlist: %w", err)
+		}
+	}
+
+	if cfg.Formats != FormatHLS {
+		// ── Step 2b: Generate DASH manifest over the same CMAF segments ───────
+		dashManifestFile := filepath.Join(hlsDir, "manifest.mpd")
+		log.Printf("generating DASH manifest → %s", dashManifestFile)
+		if err := dash.WriteMPD(dashManifestFile, dash.ManifestOptions{
+			SegmentDurationSeconds: ffmpeg.HLSSegmentSeconds,
+			Representations:        dashRepresentations(renditions),
+		}); err != nil {
+			return fmt.Errorf("generate DASH manifest: %w", err)
+		}
 	}
 
 	// ── Step 3: Extract thumbnail ─────────────────────────────────────────────
 	thumbPath := filepath.Join(workDir, "thumbnail.jpg")
-	log.Printf("extracting thumbnail from %s → %s", rawPath, thumbPath)
-	if err := tr.ExtractThumbnail(ctx, rawPath, thumbPath, 5); err != nil {
-		return fmt.Errorf("extract thumbnail: %w", err)
+	thumbDone, err := cp.Done(ctx, cfg.VideoID, checkpoint.StageThumbnail)
+	if err != nil {
+		return fmt.Errorf("check thumbnail checkpoint: %w", err)
+	}
+	if thumbDone && fileExists(thumbPath) {
+		log.Printf("thumbnail already checkpointed for video %s; skipping extraction", cfg.VideoID)
+	} else {
+		log.Printf("extracting thumbnail from %s → %s", rawPath, thumbPath)
+		if err := tr.ExtractThumbnail(ctx, rawPath, thumbPath, 5); err != nil {
+			return fmt.Errorf("extract thumbnail: %w", err)
+		}
+		if err := cp.MarkDone(ctx, cfg.VideoID, checkpoint.StageThumbnail); err != nil {
+			log.Printf("warning: mark thumbnail checkpoint for video %s: %v", cfg.VideoID, err)
+		}
+	}
+	publish(ctx, pub, events.Event{VideoID: cfg.VideoID, Stage: events.StageThumbnailExtracted, Progress: 0.7})
+	reportProgress(ctx, repo, cfg.VideoID, 70)
+
+	// ── Step 3b: Extract storyboard sprite sheets ─────────────────────────────
+	storyboardDir := filepath.Join(workDir, "storyboard")
+	storyboardDone, err := cp.Done(ctx, cfg.VideoID, checkpoint.StageStoryboard)
+	if err != nil {
+		return fmt.Errorf("check storyboard checkpoint: %w", err)
+	}
+	if storyboardDone && dirExists(storyboardDir) {
+		log.Printf("storyboard already checkpointed for video %s; skipping extraction", cfg.VideoID)
+	} else {
+		if err := os.MkdirAll(storyboardDir, 0o755); err != nil {
+			return fmt.Errorf("create storyboard dir: %w", err)
+		}
+		log.Printf("extracting storyboard from %s → %s", rawPath, storyboardDir)
+		if _, err := tr.ExtractStoryboard(ctx, rawPath, storyboardDir, ffmpeg.StoryboardOptions{SourceDuration: info.Duration}); err != nil {
+			return fmt.Errorf("extract storyboard: %w", err)
+		}
+		if err := cp.MarkDone(ctx, cfg.VideoID, checkpoint.StageStoryboard); err != nil {
+			log.Printf("warning: mark storyboard checkpoint for video %s: %v", cfg.VideoID, err)
+		}
 	}
 
-	// ── Step 4: Upload HLS output ─────────────────────────────────────────────
-	hlsPrefix := fmt.Sprintf("videos/%s", cfg.VideoID)
-	log.Printf("uploading HLS to gs://%s/%s/", cfg.HLSBucket, hlsPrefix)
-	if err := ul.UploadDir(ctx, cfg.HLSBucket, hlsPrefix, hlsDir); err != nil {
-		return fmt.Errorf("upload HLS: %w", err)
+	// ── Step 4/5: Upload HLS output and thumbnail ─────────────────────────────
+	// A previous attempt may have uploaded everything and then crashed before
+	// the database update below (Step 6); in that case uploadDone is true and
+	// this run skips straight to the (idempotent) DB update without needing
+	// either local artifact to still exist.
+	uploadDone, err := cp.Done(ctx, cfg.VideoID, checkpoint.StageUpload)
+	if err != nil {
+		return fmt.Errorf("check upload checkpoint: %w", err)
 	}
+	if uploadDone {
+		log.Printf("upload already checkpointed for video %s; skipping", cfg.VideoID)
+	} else {
+		hlsPrefix := fmt.Sprintf("videos/%s", cfg.VideoID)
+		log.Printf("uploading HLS to gs://%s/%s/", cfg.HLSBucket, hlsPrefix)
+		if err := ul.UploadDir(ctx, cfg.HLSBucket, hlsPrefix, hlsDir); err != nil {
+			return fmt.Errorf("upload HLS: %w", err)
+		}
+
+		thumbObjectPath := fmt.Sprintf("videos/%s/thumbnail.jpg", cfg.VideoID)
+		log.Printf("uploading thumbnail to gs://%s/%s", cfg.HLSBucket, thumbObjectPath)
+		if err := ul.UploadFile(ctx, cfg.HLSBucket, thumbObjectPath, thumbPath); err != nil {
+			return fmt.Errorf("upload thumbnail: %w", err)
+		}
 
-	// ── Step 5: Upload thumbnail ──────────────────────────────────────────────
-	thumbObjectPath := fmt.Sprintf("videos/%s/thumbnail.jpg", cfg.VideoID)
-	log.Printf("uploading thumbnail to gs://%s/%s", cfg.HLSBucket, thumbObjectPath)
-	if err := ul.UploadFile(ctx, cfg.HLSBucket, thumbObjectPath, thumbPath); err != nil {
-		return fmt.Errorf("upload thumbnail: %w", err)
+		storyboardPrefix := fmt.Sprintf("videos/%s/storyboard", cfg.VideoID)
+		log.Printf("uploading storyboard to gs://%s/%s/", cfg.HLSBucket, storyboardPrefix)
+		if err := ul.UploadDir(ctx, cfg.HLSBucket, storyboardPrefix, storyboardDir); err != nil {
+			return fmt.Errorf("upload storyboard: %w", err)
+		}
+		if err := cp.MarkDone(ctx, cfg.VideoID, checkpoint.StageUpload); err != nil {
+			log.Printf("warning: mark upload checkpoint for video %s: %v", cfg.VideoID, err)
+		}
 	}
+	publish(ctx, pub, events.Event{VideoID: cfg.VideoID, Stage: events.StageUploadComplete, Progress: 0.9})
+	reportProgress(ctx, repo, cfg.VideoID, 90)
 
 	// ── Step 6: Update database ───────────────────────────────────────────────
-	hlsManifestPath := fmt.Sprintf("gs://%s/videos/%s/index.m3u8", cfg.HLSBucket, cfg.VideoID)
+	var hlsManifestPath, dashManifestPath string
+	var manifestType string
+	switch cfg.Formats {
+	case FormatHLS:
+		hlsManifestPath = fmt.Sprintf("gs://%s/videos/%s/index.m3u8", cfg.HLSBucket, cfg.VideoID)
+		manifestType = video.ManifestTypeHLS
+	case FormatDASH:
+		dashManifestPath = fmt.Sprintf("gs://%s/videos/%s/manifest.mpd", cfg.HLSBucket, cfg.VideoID)
+		manifestType = video.ManifestTypeDASH
+	default:
+		hlsManifestPath = fmt.Sprintf("gs://%s/videos/%s/index.m3u8", cfg.HLSBucket, cfg.VideoID)
+		dashManifestPath = fmt.Sprintf("gs://%s/videos/%s/manifest.mpd", cfg.HLSBucket, cfg.VideoID)
+		manifestType = video.ManifestTypeBoth
+	}
 	thumbnailURL := fmt.Sprintf("%s/videos/%s/thumbnail.jpg", cfg.CDNBaseURL, cfg.VideoID)
+	storyboardVTTPath := fmt.Sprintf("gs://%s/videos/%s/storyboard/storyboard.vtt", cfg.HLSBucket, cfg.VideoID)
 
-	log.Printf("updating video %s: hls=%s thumb=%s", cfg.VideoID, hlsManifestPath, thumbnailURL)
+	log.Printf("updating video %s: hls=%s dash=%s thumb=%s storyboard=%s", cfg.VideoID, hlsManifestPath, dashManifestPath, thumbnailURL, storyboardVTTPath)
 	if err := repo.UpdateVideo(ctx, cfg.VideoID, video.Update{
-		HLSManifestPath: hlsManifestPath,
-		ThumbnailURL:    thumbnailURL,
-		Status:          video.StatusReady,
+		HLSManifestPath:   hlsManifestPath,
+		DASHManifestPath:  dashManifestPath,
+		ManifestType:      manifestType,
+		ThumbnailURL:      thumbnailURL,
+		StoryboardVTTPath: storyboardVTTPath,
+		Status:            video.StatusReady,
+		DurationSeconds:   int(info.Duration.Seconds()),
+		Width:             info.Width,
+		Height:            info.Height,
+		VideoCodec:        info.VideoCodec,
+		AudioCodec:        info.AudioCodec,
 	}); err != nil {
 		return fmt.Errorf("update video record: %w", err)
 	}
+	if err := cp.MarkDone(ctx, cfg.VideoID, checkpoint.StageDBUpdate); err != nil {
+		log.Printf("warning: mark db_update checkpoint for video %s: %v", cfg.VideoID, err)
+	}
 
+	publish(ctx, pub, events.Event{VideoID: cfg.VideoID, Stage: events.StageReady, Progress: 1, ManifestType: manifestType})
+	reportProgress(ctx, repo, cfg.VideoID, 100)
 	log.Printf("transcoder completed successfully for video %s", cfg.VideoID)
 	return nil
 }
+
+// fileExists reports whether path exists as a regular, readable file.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// dirExists reports whether path exists as a directory.
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// renditionLadder picks a content-aware rendition ladder for rawPath via
+// ffmpeg.AnalyzeSource, falling back to DefaultRenditions() filtered to
+// probedHeight if ffprobe analysis fails — a broken ladder call should never
+// fail an otherwise-transcodable video.
+func renditionLadder(ctx context.Context, rawPath string, probedHeight int) []ffmpeg.Rendition {
+	info, err := ffmpeg.AnalyzeSource(ctx, rawPath)
+	if err != nil {
+		log.Printf("warning: analyze source %s for content-aware ladder: %v; falling back to default ladder", rawPath, err)
+		return ffmpeg.FilterByMaxHeight(ffmpeg.DefaultRenditions(), probedHeight)
+	}
+	return ffmpeg.LadderFor(info, ffmpeg.DefaultLadderPolicy())
+}
+
+// pendingRenditions returns the subset of renditions not yet checkpointed
+// done for videoID, preserving order. Like the download and thumbnail
+// stages, a rendition is only skipped when its checkpoint marker AND its
+// local playlist file both exist: on a real Cloud Run Job retry the
+// container is fresh and hlsDir is empty, so a marker surviving from a
+// prior attempt (written before that attempt's upload stage) never skips
+// encoding when the segments it covers were never durably uploaded.
+func pendingRenditions(ctx context.Context, cp checkpoint.Store, videoID, hlsDir string, renditions []ffmpeg.Rendition) ([]ffmpeg.Rendition, error) {
+	pending := make([]ffmpeg.Rendition, 0, len(renditions))
+	for _, rend := range renditions {
+		done, err := cp.Done(ctx, videoID, checkpoint.RenditionStage(rend.Name))
+		if err != nil {
+			return nil, fmt.Errorf("check rendition %s checkpoint: %w", rend.Name, err)
+		}
+		if !done || !fileExists(filepath.Join(hlsDir, rend.Name+".m3u8")) {
+			pending = append(pending, rend)
+		}
+	}
+	return pending, nil
+}
+
+// allCheckpointStages lists every Stage a full run can mark, so
+// FORCE_REPROCESS can clear all of them regardless of which renditions this
+// run ends up needing (the probed source height is not known yet at this
+// point, so renditions covers the full default set rather than a filtered
+// one).
+func allCheckpointStages(renditions []ffmpeg.Rendition) []checkpoint.Stage {
+	stages := []checkpoint.Stage{
+		checkpoint.StageDownload,
+		checkpoint.StageThumbnail,
+		checkpoint.StageStoryboard,
+		checkpoint.StageUpload,
+		checkpoint.StageDBUpdate,
+	}
+	for _, rend := range renditions {
+		stages = append(stages, checkpoint.RenditionStage(rend.Name))
+	}
+	return stages
+}
+
+// publish emits evt with the current time and logs (without aborting the
+// pipeline) if the publisher fails, since a lost status update should never
+// fail an otherwise-successful transcode.
+func publish(ctx context.Context, pub events.Publisher, evt events.Event) {
+	evt.Timestamp = time.Now()
+	if err := pub.Publish(ctx, evt); err != nil {
+		log.Printf("warning: publish %s event for video %s: %v", evt.Stage, evt.VideoID, err)
+	}
+}
+
+// reportProgress writes pct to the video's progress_pct column via
+// repo.UpdateProgress, logging (without aborting the pipeline) on failure,
+// since a lost progress update should never fail an otherwise-successful
+// transcode. It is called at each pipeline stage boundary in doTranscode so
+// the API can show real transcoding progress instead of just
+// "processing → ready".
+func reportProgress(ctx context.Context, repo VideoRepository, videoID string, pct int) {
+	if err := repo.UpdateProgress(ctx, videoID, pct); err != nil {
+		log.Printf("warning: update progress for video %s: %v", videoID, err)
+	}
+}
+
+// throttledProgressPublisher returns an ffmpeg.Runner.OnProgress callback
+// that publishes a StageRenditionProgress event for videoID, at most once
+// per interval per rendition, so a fast-polling FFmpeg (-progress reports
+// roughly once a second) doesn't flood the events topic. It is safe for
+// concurrent use across the worker pool's per-rendition goroutines.
+func throttledProgressPublisher(ctx context.Context, pub events.Publisher, videoID string, interval time.Duration) func(ffmpeg.Rendition, ffmpeg.TranscodeProgress) {
+	var mu sync.Mutex
+	last := make(map[string]time.Time)
+
+	return func(rend ffmpeg.Rendition, p ffmpeg.TranscodeProgress) {
+		now := time.Now()
+
+		mu.Lock()
+		due := now.Sub(last[rend.Name]) >= interval
+		if due || p.Done {
+			last[rend.Name] = now
+		}
+		mu.Unlock()
+
+		if !due && !p.Done {
+			return
+		}
+
+		publish(ctx, pub, events.Event{
+			VideoID:        videoID,
+			Stage:          events.StageRenditionProgress,
+			Rendition:      rend.Name,
+			OutTimeSeconds: p.OutTime.Seconds(),
+		})
+	}
+}
+
+// dashRepresentations maps HLS renditions to the DASH Representations that
+// reference their shared CMAF init/media segments.
+func dashRepresentations(renditions []ffmpeg.Rendition) []dash.Representation {
+	reps := make([]dash.Representation, len(renditions))
+	for i, rend := range renditions {
+		width, height := ffmpeg.Resolution(rend)
+		reps[i] = dash.Representation{
+			ID:            rend.Name,
+			Width:         width,
+			Height:        height,
+			Bandwidth:     ffmpeg.Bandwidth(rend),
+			InitPath:      ffmpeg.CMAFInitFilename(rend),
+			MediaTemplate: ffmpeg.CMAFSegmentTemplate(rend),
+		}
+	}
+	return reps
+}
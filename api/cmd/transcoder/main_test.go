@@ -9,9 +9,13 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/checkpoint"
 	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/ffmpeg"
+	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/probe"
 	"github.com/ai-teammate/mytube/api/cmd/transcoder/internal/video"
+	"github.com/ai-teammate/mytube/api/internal/events"
 )
 
 // ── stub implementations ──────────────────────────────────────────────────────
@@ -34,10 +38,11 @@ func (s *stubDownloader) Download(_ context.Context, _, _, destPath string) erro
 
 // stubUploader implements DirUploader.
 type stubUploader struct {
-	fileErr      error
-	dirErr       error
-	uploadedFiles []string
-	uploadedDirs  []string
+	fileErr        error
+	dirErr         error
+	uploadedFiles  []string
+	uploadedDirs   []string
+	uploadedSrcDir string
 }
 
 func (s *stubUploader) UploadFile(_ context.Context, _, objectPath, _ string) error {
@@ -45,23 +50,52 @@ func (s *stubUploader) UploadFile(_ context.Context, _, objectPath, _ string) er
 	return s.fileErr
 }
 
-func (s *stubUploader) UploadDir(_ context.Context, _, prefix, _ string) error {
+func (s *stubUploader) UploadDir(_ context.Context, _, prefix, srcDir string) error {
 	s.uploadedDirs = append(s.uploadedDirs, prefix)
+	s.uploadedSrcDir = srcDir
 	return s.dirErr
 }
 
+// stubProber implements Prober. The zero value probes as an accepted 1080p
+// video, so tests that don't care about probing can pass a bare &stubProber{}.
+type stubProber struct {
+	info probe.MediaInfo
+	err  error
+}
+
+func (s *stubProber) Probe(context.Context, string) (probe.MediaInfo, error) {
+	if s.err != nil {
+		return probe.MediaInfo{}, s.err
+	}
+	if s.info == (probe.MediaInfo{}) {
+		return probe.MediaInfo{HasVideo: true, HasAudio: true, Height: 1080}, nil
+	}
+	return s.info, nil
+}
+
 // stubTranscoder implements Transcoder.
 type stubTranscoder struct {
-	hlsErr   error
-	thumbErr error
-	calls    []string
+	hlsErr        error
+	thumbErr      error
+	storyboardErr error
+	calls         []string
+	renditions    []ffmpeg.Rendition
 }
 
-func (s *stubTranscoder) TranscodeHLS(_ context.Context, _, outputDir string, _ []ffmpeg.Rendition) error {
-	s.calls = append(s.calls, "TranscodeHLS")
+func (s *stubTranscoder) EncodeRenditions(_ context.Context, _, outputDir string, renditions []ffmpeg.Rendition) error {
+	s.calls = append(s.calls, "EncodeRenditions")
+	s.renditions = renditions
 	if s.hlsErr != nil {
 		return s.hlsErr
 	}
+	// Write each rendition's own playlist, matching what Runner.TranscodeHLS
+	// writes per rendition, so a rendition checkpoint's local-file guard
+	// (see pendingRenditions) finds it.
+	for _, rend := range renditions {
+		if err := os.WriteFile(filepath.Join(outputDir, rend.Name+".m3u8"), []byte("#EXTM3U"), 0o644); err != nil {
+			return err
+		}
+	}
 	// Create index.m3u8 so upload can proceed.
 	return os.WriteFile(filepath.Join(outputDir, "index.m3u8"), []byte("#EXTM3U"), 0o644)
 }
@@ -74,14 +108,44 @@ func (s *stubTranscoder) ExtractThumbnail(_ context.Context, _, destPath string,
 	return os.WriteFile(destPath, []byte("jpeg"), 0o644)
 }
 
-// stubVideoRepo implements VideoRepository.
+func (s *stubTranscoder) ExtractStoryboard(_ context.Context, _, outDir string, _ ffmpeg.StoryboardOptions) (*ffmpeg.StoryboardIndex, error) {
+	s.calls = append(s.calls, "ExtractStoryboard")
+	if s.storyboardErr != nil {
+		return nil, s.storyboardErr
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "storyboard_000.jpg"), []byte("jpeg"), 0o644); err != nil {
+		return nil, err
+	}
+	index := &ffmpeg.StoryboardIndex{SpriteFiles: []string{"storyboard_000.jpg"}, VTTFile: "storyboard.vtt"}
+	if err := os.WriteFile(filepath.Join(outDir, index.VTTFile), []byte("WEBVTT\n"), 0o644); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// stubVideoRepo implements VideoRepository. The zero value's Status returns
+// an error (as if the row didn't exist yet), so tests that don't care about
+// the already-ready short-circuit run the full pipeline as before.
 type stubVideoRepo struct {
-	updateErr    error
-	markFailErr  error
-	updateCalled bool
-	markFailed   bool
-	lastUpdate   video.Update
-	lastVideoID  string
+	status        video.Status
+	statusErr     error
+	updateErr     error
+	markFailErr   error
+	markRejectErr error
+	updateCalled  bool
+	markFailed    bool
+	markRejected  bool
+	lastUpdate    video.Update
+	lastVideoID   string
+	lastReason    string
+	progressCalls []int
+}
+
+func (s *stubVideoRepo) Status(_ context.Context, _ string) (video.Status, error) {
+	if s.status == "" && s.statusErr == nil {
+		return "", errors.New("video not found")
+	}
+	return s.status, s.statusErr
 }
 
 func (s *stubVideoRepo) UpdateVideo(_ context.Context, videoID string, u video.Update) error {
@@ -91,11 +155,22 @@ func (s *stubVideoRepo) UpdateVideo(_ context.Context, videoID string, u video.U
 	return s.updateErr
 }
 
+func (s *stubVideoRepo) UpdateProgress(_ context.Context, _ string, pct int) error {
+	s.progressCalls = append(s.progressCalls, pct)
+	return nil
+}
+
 func (s *stubVideoRepo) MarkFailed(_ context.Context, _ string) error {
 	s.markFailed = true
 	return s.markFailErr
 }
 
+func (s *stubVideoRepo) MarkRejected(_ context.Context, _, reason string) error {
+	s.markRejected = true
+	s.lastReason = reason
+	return s.markRejectErr
+}
+
 // ── helpers ───────────────────────────────────────────────────────────────────
 
 func newTestConfig() config {
@@ -114,9 +189,11 @@ func TestTranscode_HappyPath_NoError(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	if err := transcode(context.Background(), newTestConfig(), dl, ul, tr, repo); err != nil {
+	if err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -125,15 +202,17 @@ func TestTranscode_HappyPath_CallsAllSteps(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	_ = transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 
 	if dl.downloadedTo == "" {
 		t.Error("Download was not called")
 	}
-	if !contains(tr.calls, "TranscodeHLS") {
-		t.Error("TranscodeHLS was not called")
+	if !contains(tr.calls, "EncodeRenditions") {
+		t.Error("EncodeRenditions was not called")
 	}
 	if !contains(tr.calls, "ExtractThumbnail") {
 		t.Error("ExtractThumbnail was not called")
@@ -143,14 +222,37 @@ func TestTranscode_HappyPath_CallsAllSteps(t *testing.T) {
 	}
 }
 
+func TestTranscode_HappyPath_ReportsProgressAtEachStage(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	want := []int{20, 70, 90, 100}
+	if len(repo.progressCalls) != len(want) {
+		t.Fatalf("progressCalls = %v, want %v", repo.progressCalls, want)
+	}
+	for i, pct := range want {
+		if repo.progressCalls[i] != pct {
+			t.Errorf("progressCalls[%d] = %d, want %d", i, repo.progressCalls[i], pct)
+		}
+	}
+}
+
 func TestTranscode_HappyPath_UpdatesDBWithCorrectPaths(t *testing.T) {
 	cfg := newTestConfig()
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	_ = transcode(context.Background(), cfg, dl, ul, tr, repo)
+	_ = transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 
 	wantHLS := fmt.Sprintf("gs://%s/videos/%s/index.m3u8", cfg.HLSBucket, cfg.VideoID)
 	if repo.lastUpdate.HLSManifestPath != wantHLS {
@@ -160,15 +262,21 @@ func TestTranscode_HappyPath_UpdatesDBWithCorrectPaths(t *testing.T) {
 	if repo.lastUpdate.ThumbnailURL != wantThumb {
 		t.Errorf("ThumbnailURL = %q, want %q", repo.lastUpdate.ThumbnailURL, wantThumb)
 	}
+	wantDASH := fmt.Sprintf("gs://%s/videos/%s/manifest.mpd", cfg.HLSBucket, cfg.VideoID)
+	if repo.lastUpdate.DASHManifestPath != wantDASH {
+		t.Errorf("DASHManifestPath = %q, want %q", repo.lastUpdate.DASHManifestPath, wantDASH)
+	}
 }
 
 func TestTranscode_HappyPath_UpdatesDBWithStatusReady(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	_ = transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 
 	if repo.lastUpdate.Status != video.StatusReady {
 		t.Errorf("status = %q, want %q", repo.lastUpdate.Status, video.StatusReady)
@@ -180,9 +288,11 @@ func TestTranscode_HappyPath_UploadsHLSAndThumbnail(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	_ = transcode(context.Background(), cfg, dl, ul, tr, repo)
+	_ = transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 
 	wantDir := fmt.Sprintf("videos/%s", cfg.VideoID)
 	if len(ul.uploadedDirs) == 0 || ul.uploadedDirs[0] != wantDir {
@@ -201,15 +311,101 @@ func TestTranscode_HappyPath_UploadsHLSAndThumbnail(t *testing.T) {
 	}
 }
 
+func TestTranscode_HappyPath_WritesDASHManifestBeforeUpload(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	var manifestExistedAtUploadTime bool
+	ul := &recordingDASHUploader{
+		onUploadDir: func(srcDir string) {
+			_, err := os.Stat(filepath.Join(srcDir, "manifest.mpd"))
+			manifestExistedAtUploadTime = err == nil
+		},
+	}
+
+	if err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !manifestExistedAtUploadTime {
+		t.Error("expected manifest.mpd to exist in the HLS dir by the time it is uploaded")
+	}
+}
+
+// recordingDASHUploader is a DirUploader that inspects srcDir synchronously
+// during UploadDir, before the caller's temp dir is cleaned up.
+type recordingDASHUploader struct {
+	onUploadDir func(srcDir string)
+}
+
+func (u *recordingDASHUploader) UploadFile(context.Context, string, string, string) error { return nil }
+
+func (u *recordingDASHUploader) UploadDir(_ context.Context, _, _, srcDir string) error {
+	u.onUploadDir(srcDir)
+	return nil
+}
+
+func TestTranscode_HappyPath_PublishesExactStageSequence(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+	pub := &events.FakePublisher{}
+
+	if err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, pub, cp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []events.Stage{
+		events.StageDownloadStarted,
+		events.StageDownloadComplete,
+		events.StageThumbnailExtracted,
+		events.StageUploadComplete,
+		events.StageReady,
+	}
+	got := pub.Stages()
+	if len(got) != len(want) {
+		t.Fatalf("got %d stages, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stage[%d] = %q, want %q (full sequence: %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestTranscode_DownloadError_PublishesFailedStage(t *testing.T) {
+	dl := &stubDownloader{err: errors.New("network timeout")}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+	pub := &events.FakePublisher{}
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, pub, cp)
+
+	stages := pub.Stages()
+	if len(stages) == 0 || stages[len(stages)-1] != events.StageFailed {
+		t.Errorf("expected last published stage to be %q, got %v", events.StageFailed, stages)
+	}
+}
+
 // ── transcode error cases ─────────────────────────────────────────────────────
 
 func TestTranscode_DownloadError_ReturnsError(t *testing.T) {
 	dl := &stubDownloader{err: errors.New("download failed")}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	err := transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -219,9 +415,11 @@ func TestTranscode_DownloadError_MarksVideoFailed(t *testing.T) {
 	dl := &stubDownloader{err: errors.New("network timeout")}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	_ = transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 
 	if !repo.markFailed {
 		t.Error("expected MarkFailed to be called after download error")
@@ -232,9 +430,11 @@ func TestTranscode_TranscodeHLSError_ReturnsError(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{hlsErr: errors.New("ffmpeg error")}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	err := transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -244,9 +444,11 @@ func TestTranscode_TranscodeHLSError_MarksVideoFailed(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{hlsErr: errors.New("codec error")}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	_ = transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 
 	if !repo.markFailed {
 		t.Error("expected MarkFailed to be called after transcode error")
@@ -257,9 +459,11 @@ func TestTranscode_ThumbnailError_ReturnsError(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{thumbErr: errors.New("thumbnail error")}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	err := transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -269,9 +473,11 @@ func TestTranscode_UploadDirError_ReturnsError(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{dirErr: errors.New("upload dir failed")}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	err := transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -281,9 +487,11 @@ func TestTranscode_UploadFileError_ReturnsError(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{fileErr: errors.New("upload file failed")}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
 
-	err := transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -293,9 +501,11 @@ func TestTranscode_UpdateVideoError_ReturnsError(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{updateErr: errors.New("db error")}
+	cp := checkpoint.NewMemStore()
 
-	err := transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -305,15 +515,420 @@ func TestTranscode_UpdateVideoError_MarksVideoFailed(t *testing.T) {
 	dl := &stubDownloader{content: "video"}
 	ul := &stubUploader{}
 	tr := &stubTranscoder{}
+	pr := &stubProber{}
 	repo := &stubVideoRepo{updateErr: errors.New("db error")}
+	cp := checkpoint.NewMemStore()
 
-	_ = transcode(context.Background(), newTestConfig(), dl, ul, tr, repo)
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
 
 	if !repo.markFailed {
 		t.Error("expected MarkFailed to be called after UpdateVideo error")
 	}
 }
 
+// ── probe rejection ────────────────────────────────────────────────────────────
+
+func TestTranscode_ProbeRejected_ReturnsError(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{err: &probe.RejectedError{Reason: "no video stream"}}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestTranscode_ProbeRejected_MarksVideoRejectedNotFailed(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{err: &probe.RejectedError{Reason: "no video stream"}}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	if !repo.markRejected {
+		t.Error("expected MarkRejected to be called after a probe rejection")
+	}
+	if repo.markFailed {
+		t.Error("expected MarkFailed NOT to be called after a probe rejection")
+	}
+	if repo.lastReason != "no video stream" {
+		t.Errorf("reason = %q, want %q", repo.lastReason, "no video stream")
+	}
+}
+
+func TestTranscode_ProbeRejected_PublishesRejectedStage(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{err: &probe.RejectedError{Reason: "duration exceeds maximum"}}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+	pub := &events.FakePublisher{}
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, pub, cp)
+
+	stages := pub.Stages()
+	if len(stages) == 0 || stages[len(stages)-1] != events.StageRejected {
+		t.Errorf("expected last published stage to be %q, got %v", events.StageRejected, stages)
+	}
+}
+
+func TestTranscode_ProbeGenericError_MarksVideoFailedNotRejected(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{err: errors.New("ffprobe: exit status 1")}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	if !repo.markFailed {
+		t.Error("expected MarkFailed to be called after a non-policy probe error")
+	}
+	if repo.markRejected {
+		t.Error("expected MarkRejected NOT to be called after a non-policy probe error")
+	}
+}
+
+func TestTranscode_ProbedHeight_FiltersRenditionsTallerThanSource(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{info: probe.MediaInfo{HasVideo: true, HasAudio: true, Height: 480}}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	if len(tr.renditions) != 1 || tr.renditions[0].Name != "360p" {
+		t.Errorf("renditions = %v, want only 360p", tr.renditions)
+	}
+}
+
+func TestTranscode_ProbedDuration_WrittenToUpdate(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{info: probe.MediaInfo{HasVideo: true, HasAudio: true, Height: 1080, Duration: 90 * time.Second}}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	if repo.lastUpdate.DurationSeconds != 90 {
+		t.Errorf("DurationSeconds = %d, want 90", repo.lastUpdate.DurationSeconds)
+	}
+}
+
+func TestTranscode_ProbedResolutionAndCodecs_WrittenToUpdate(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{info: probe.MediaInfo{
+		HasVideo: true, HasAudio: true,
+		Width: 1920, Height: 1080,
+		VideoCodec: "h264", AudioCodec: "aac",
+		Duration: 90 * time.Second,
+	}}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	if repo.lastUpdate.Width != 1920 || repo.lastUpdate.Height != 1080 {
+		t.Errorf("Width/Height = %dx%d, want 1920x1080", repo.lastUpdate.Width, repo.lastUpdate.Height)
+	}
+	if repo.lastUpdate.VideoCodec != "h264" || repo.lastUpdate.AudioCodec != "aac" {
+		t.Errorf("VideoCodec/AudioCodec = %q/%q, want h264/aac", repo.lastUpdate.VideoCodec, repo.lastUpdate.AudioCodec)
+	}
+}
+
+// ── output formats (TRANSCODE_FORMATS) ───────────────────────────────────────
+
+func TestTranscode_DefaultFormats_WritesBothManifests(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	_ = transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	if repo.lastUpdate.ManifestType != video.ManifestTypeBoth {
+		t.Errorf("ManifestType = %q, want %q", repo.lastUpdate.ManifestType, video.ManifestTypeBoth)
+	}
+	if repo.lastUpdate.HLSManifestPath == "" || repo.lastUpdate.DASHManifestPath == "" {
+		t.Errorf("expected both manifest paths set, got hls=%q dash=%q", repo.lastUpdate.HLSManifestPath, repo.lastUpdate.DASHManifestPath)
+	}
+}
+
+func TestTranscode_FormatHLSOnly_OmitsDASHManifest(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	cfg := newTestConfig()
+	cfg.Formats = FormatHLS
+
+	_ = transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	if repo.lastUpdate.ManifestType != video.ManifestTypeHLS {
+		t.Errorf("ManifestType = %q, want %q", repo.lastUpdate.ManifestType, video.ManifestTypeHLS)
+	}
+	if repo.lastUpdate.HLSManifestPath == "" {
+		t.Error("expected HLSManifestPath to be set")
+	}
+	if repo.lastUpdate.DASHManifestPath != "" {
+		t.Errorf("DASHManifestPath = %q, want empty", repo.lastUpdate.DASHManifestPath)
+	}
+}
+
+func TestTranscode_FormatDASHOnly_OmitsHLSManifest(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+
+	cfg := newTestConfig()
+	cfg.Formats = FormatDASH
+
+	_ = transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	if repo.lastUpdate.ManifestType != video.ManifestTypeDASH {
+		t.Errorf("ManifestType = %q, want %q", repo.lastUpdate.ManifestType, video.ManifestTypeDASH)
+	}
+	if repo.lastUpdate.DASHManifestPath == "" {
+		t.Error("expected DASHManifestPath to be set")
+	}
+	if repo.lastUpdate.HLSManifestPath != "" {
+		t.Errorf("HLSManifestPath = %q, want empty", repo.lastUpdate.HLSManifestPath)
+	}
+}
+
+func TestFormatFromEnv_Unset_DefaultsToBoth(t *testing.T) {
+	t.Setenv("TRANSCODE_FORMATS", "")
+
+	if got := formatFromEnv(); got != FormatBoth {
+		t.Errorf("formatFromEnv() = %q, want %q", got, FormatBoth)
+	}
+}
+
+func TestFormatFromEnv_HLS(t *testing.T) {
+	t.Setenv("TRANSCODE_FORMATS", "hls")
+
+	if got := formatFromEnv(); got != FormatHLS {
+		t.Errorf("formatFromEnv() = %q, want %q", got, FormatHLS)
+	}
+}
+
+func TestFormatFromEnv_DASH(t *testing.T) {
+	t.Setenv("TRANSCODE_FORMATS", "dash")
+
+	if got := formatFromEnv(); got != FormatDASH {
+		t.Errorf("formatFromEnv() = %q, want %q", got, FormatDASH)
+	}
+}
+
+// ── resumable jobs (checkpointing) ───────────────────────────────────────────
+
+func TestTranscode_AlreadyReady_SkipsPipelineEntirely(t *testing.T) {
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{status: video.StatusReady}
+	cp := checkpoint.NewMemStore()
+
+	if err := transcode(context.Background(), newTestConfig(), dl, ul, pr, tr, repo, events.NoopPublisher{}, cp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dl.downloadedTo != "" {
+		t.Error("expected Download not to be called for an already-ready video")
+	}
+	if repo.updateCalled {
+		t.Error("expected UpdateVideo not to be called for an already-ready video")
+	}
+}
+
+// TestTranscode_RenditionCheckpointedWithoutLocalFile_IsReEncoded simulates a
+// real Cloud Run Job retry: the checkpoint marker for 1080p survived from a
+// prior attempt (written before that attempt's upload stage ran), but the
+// container is fresh so hlsDir has no 1080p.m3u8. The rendition must still
+// be re-encoded — skipping it on the marker alone could ship a "ready" video
+// whose segments were never durably uploaded.
+func TestTranscode_RenditionCheckpointedWithoutLocalFile_IsReEncoded(t *testing.T) {
+	cfg := newTestConfig()
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+	_ = cp.MarkDone(context.Background(), cfg.VideoID, checkpoint.RenditionStage("1080p"))
+
+	_ = transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp)
+
+	found := false
+	for _, rend := range tr.renditions {
+		if rend.Name == "1080p" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected checkpointed-but-locally-missing rendition 1080p to still be re-encoded, got %v", tr.renditions)
+	}
+}
+
+// TestTranscode_AllRenditionsCheckpointedWithoutLocalFiles_ReEncodesAll covers
+// the same fresh-container scenario across every rendition: a prior attempt
+// checkpointed encoding but crashed before (or during) the upload stage, so
+// none of the segments are durable. Every rendition must be re-encoded.
+func TestTranscode_AllRenditionsCheckpointedWithoutLocalFiles_ReEncodesAll(t *testing.T) {
+	cfg := newTestConfig()
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+	for _, rend := range ffmpeg.DefaultRenditions() {
+		_ = cp.MarkDone(context.Background(), cfg.VideoID, checkpoint.RenditionStage(rend.Name))
+	}
+
+	if err := transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !contains(tr.calls, "EncodeRenditions") {
+		t.Error("expected EncodeRenditions to be called when checkpointed renditions have no local segments")
+	}
+	if len(tr.renditions) != len(ffmpeg.DefaultRenditions()) {
+		t.Errorf("expected every rendition to be re-encoded, got %v", tr.renditions)
+	}
+	if !repo.updateCalled {
+		t.Error("expected the pipeline to still finish with a DB update")
+	}
+}
+
+func TestPendingRenditions_CheckpointedAndLocalFileExists_Skipped(t *testing.T) {
+	hlsDir := t.TempDir()
+	rend := ffmpeg.Rendition{Name: "1080p"}
+	cp := checkpoint.NewMemStore()
+	_ = cp.MarkDone(context.Background(), "vid", checkpoint.RenditionStage(rend.Name))
+	if err := os.WriteFile(filepath.Join(hlsDir, rend.Name+".m3u8"), []byte("#EXTM3U"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := pendingRenditions(context.Background(), cp, "vid", hlsDir, []ffmpeg.Rendition{rend})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending renditions, got %v", pending)
+	}
+}
+
+func TestPendingRenditions_CheckpointedButLocalFileMissing_Pending(t *testing.T) {
+	hlsDir := t.TempDir()
+	rend := ffmpeg.Rendition{Name: "1080p"}
+	cp := checkpoint.NewMemStore()
+	_ = cp.MarkDone(context.Background(), "vid", checkpoint.RenditionStage(rend.Name))
+
+	pending, err := pendingRenditions(context.Background(), cp, "vid", hlsDir, []ffmpeg.Rendition{rend})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Name != rend.Name {
+		t.Errorf("expected %s to be pending when its local file is missing, got %v", rend.Name, pending)
+	}
+}
+
+func TestTranscode_UploadAlreadyCheckpointed_SkipsReupload(t *testing.T) {
+	cfg := newTestConfig()
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{}
+	cp := checkpoint.NewMemStore()
+	_ = cp.MarkDone(context.Background(), cfg.VideoID, checkpoint.StageUpload)
+
+	if err := transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ul.uploadedDirs) != 0 || len(ul.uploadedFiles) != 0 {
+		t.Errorf("expected no uploads when the upload stage is already checkpointed, got dirs=%v files=%v", ul.uploadedDirs, ul.uploadedFiles)
+	}
+	if !repo.updateCalled {
+		t.Error("expected the DB update to still run so a crash between upload and db_update can complete on retry")
+	}
+}
+
+func TestTranscode_ForceReprocess_ClearsCheckpointsAndRedoesEverything(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.ForceReprocess = true
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{status: video.StatusReady}
+	cp := checkpoint.NewMemStore()
+	for _, rend := range ffmpeg.DefaultRenditions() {
+		_ = cp.MarkDone(context.Background(), cfg.VideoID, checkpoint.RenditionStage(rend.Name))
+	}
+	_ = cp.MarkDone(context.Background(), cfg.VideoID, checkpoint.StageUpload)
+
+	if err := transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dl.downloadedTo == "" {
+		t.Error("expected FORCE_REPROCESS to re-download despite repo.Status == ready")
+	}
+	if !contains(tr.calls, "EncodeRenditions") {
+		t.Error("expected FORCE_REPROCESS to clear rendition checkpoints and re-encode")
+	}
+	if len(ul.uploadedDirs) == 0 {
+		t.Error("expected FORCE_REPROCESS to clear the upload checkpoint and re-upload")
+	}
+}
+
+func TestTranscode_ForceReprocess_ClearsStoryboardCheckpoint(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.ForceReprocess = true
+	dl := &stubDownloader{content: "video"}
+	ul := &stubUploader{}
+	tr := &stubTranscoder{}
+	pr := &stubProber{}
+	repo := &stubVideoRepo{status: video.StatusReady}
+	cp := checkpoint.NewMemStore()
+	_ = cp.MarkDone(context.Background(), cfg.VideoID, checkpoint.StageStoryboard)
+
+	if err := transcode(context.Background(), cfg, dl, ul, pr, tr, repo, events.NoopPublisher{}, cp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !contains(tr.calls, "ExtractStoryboard") {
+		t.Error("expected FORCE_REPROCESS to clear the storyboard checkpoint and re-extract it")
+	}
+}
+
 // ── configFromEnv ─────────────────────────────────────────────────────────────
 
 func setEnvVars(t *testing.T, pairs map[string]string) {
@@ -402,6 +1017,32 @@ func TestConfigFromEnv_MissingHLSBucket(t *testing.T) {
 	}
 }
 
+func TestConfigFromEnv_ForceReprocess_DefaultsFalse(t *testing.T) {
+	setEnvVars(t, fullEnv())
+
+	cfg, err := configFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ForceReprocess {
+		t.Error("expected ForceReprocess to default to false")
+	}
+}
+
+func TestConfigFromEnv_ForceReprocess_ReadsTrue(t *testing.T) {
+	env := fullEnv()
+	setEnvVars(t, env)
+	t.Setenv("FORCE_REPROCESS", "true")
+
+	cfg, err := configFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ForceReprocess {
+		t.Error("expected ForceReprocess to be true when FORCE_REPROCESS=true")
+	}
+}
+
 func TestConfigFromEnv_MissingCDNBaseURL(t *testing.T) {
 	env := fullEnv()
 	setEnvVars(t, env)
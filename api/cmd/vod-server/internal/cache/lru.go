@@ -0,0 +1,87 @@
+// Package cache provides an in-memory LRU cache for encoded segments, keyed
+// by (videoID, rendition, segment index), so repeat requests for the same
+// popular segment (e.g. a seek target many viewers land on) don't each pay
+// for a fresh FFmpeg invocation.
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// SegmentCache caches encoded segment bytes by key.
+type SegmentCache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, value []byte)
+}
+
+// Key builds the cache key for a given video/rendition/segment index.
+func Key(videoID, rendition string, segIdx int) string {
+	return fmt.Sprintf("%s|%s|%d", videoID, rendition, segIdx)
+}
+
+// LRU is a fixed-capacity, in-memory least-recently-used cache. It is safe
+// for concurrent use.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // front = most recently used
+}
+
+type entry struct {
+	key   string
+	value []byte
+}
+
+// NewLRU constructs an LRU holding at most capacity entries. A non-positive
+// capacity disables caching: Get always misses and Put is a no-op.
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached value for key and marks it most-recently-used.
+func (c *LRU) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*entry).value, true
+}
+
+// Put inserts or updates key's cached value, evicting the least-recently-used
+// entry if the cache is at capacity.
+func (c *LRU) Put(key string, value []byte) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*entry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&entry{key: key, value: value})
+	c.items[key] = elem
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*entry).key)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/cache"
+)
+
+func TestLRU_PutThenGet_ReturnsValue(t *testing.T) {
+	c := cache.NewLRU(2)
+	c.Put("a", []byte("1"))
+
+	got, ok := c.Get("a")
+	if !ok {
+		t.Fatal("expected hit")
+	}
+	if string(got) != "1" {
+		t.Errorf("got = %q, want %q", got, "1")
+	}
+}
+
+func TestLRU_Get_MissReturnsFalse(t *testing.T) {
+	c := cache.NewLRU(2)
+	if _, ok := c.Get("missing"); ok {
+		t.Error("expected miss")
+	}
+}
+
+func TestLRU_OverCapacity_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewLRU(2)
+	c.Put("a", []byte("1"))
+	c.Put("b", []byte("2"))
+	c.Put("c", []byte("3")) // evicts "a"
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected a to be evicted")
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Error("expected b to remain")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("expected c to remain")
+	}
+}
+
+func TestLRU_GetRefreshesRecency(t *testing.T) {
+	c := cache.NewLRU(2)
+	c.Put("a", []byte("1"))
+	c.Put("b", []byte("2"))
+	c.Get("a")              // a is now most-recently-used
+	c.Put("c", []byte("3")) // should evict "b", not "a"
+
+	if _, ok := c.Get("a"); !ok {
+		t.Error("expected a to remain")
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+}
+
+func TestLRU_ZeroCapacity_NeverCaches(t *testing.T) {
+	c := cache.NewLRU(0)
+	c.Put("a", []byte("1"))
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected zero-capacity cache to never hit")
+	}
+}
+
+func TestKey_IncludesAllComponents(t *testing.T) {
+	k1 := cache.Key("video-1", "360p", 2)
+	k2 := cache.Key("video-1", "360p", 3)
+	if k1 == k2 {
+		t.Error("expected different segment indices to produce different keys")
+	}
+}
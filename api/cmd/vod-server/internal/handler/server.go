@@ -0,0 +1,192 @@
+// Package handler wires the VOD server's HTTP routes: master/variant HLS
+// playlists generated on the fly, and individual segments encoded on demand
+// and cached.
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/cache"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/playlist"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/segment"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/video"
+)
+
+// VideoRepository resolves a video ID to its raw source location.
+type VideoRepository interface {
+	Get(ctx context.Context, videoID string) (*video.Video, error)
+}
+
+// SourceFetcher resolves a video's raw object to a local path FFmpeg can
+// seek within. Satisfied by *source.Fetcher.
+type SourceFetcher interface {
+	LocalPath(ctx context.Context, videoID, bucket, objectPath string) (string, error)
+}
+
+// SegmentEncoder runs the FFmpeg invocation producing one segment's bytes.
+// Satisfied by *segment.Encoder.
+type SegmentEncoder interface {
+	EncodeSegment(ctx context.Context, sourcePath string, rend segment.Rendition, start, duration float64) ([]byte, error)
+}
+
+// Server holds the VOD server's dependencies and builds its HTTP routes.
+type Server struct {
+	Repo       VideoRepository
+	Fetcher    SourceFetcher
+	Encoder    SegmentEncoder
+	Cache      cache.SegmentCache
+	RawBucket  string
+	Renditions []segment.Rendition
+}
+
+// NewRouter builds the *http.ServeMux exposing:
+//
+//	GET /videos/{id}/index.m3u8                 — master playlist
+//	GET /videos/{id}/{rendition}/index.m3u8     — variant playlist
+//	GET /videos/{id}/{rendition}/{segIdx}.ts    — one encoded segment
+func (s *Server) NewRouter() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/videos/", s.handleVideoRequest)
+	return mux
+}
+
+// handleVideoRequest dispatches a /videos/... request to the master
+// playlist, variant playlist, or segment handler based on how many path
+// segments follow the video ID.
+func (s *Server) handleVideoRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/videos/"), "/")
+	switch len(parts) {
+	case 2:
+		if parts[1] != "index.m3u8" {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveMaster(w, r, parts[0])
+	case 3:
+		if parts[1] == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if parts[2] == "index.m3u8" {
+			s.serveVariant(w, r, parts[0], parts[1])
+			return
+		}
+		segIdx, ok := segmentIndexFromFilename(parts[2])
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveSegment(w, r, parts[0], parts[1], segIdx)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// segmentIndexFromFilename parses "{idx}.ts" into idx.
+func segmentIndexFromFilename(name string) (int, bool) {
+	idxStr := strings.TrimSuffix(name, ".ts")
+	if idxStr == name {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(idxStr)
+	if err != nil || idx < 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
+func (s *Server) serveMaster(w http.ResponseWriter, r *http.Request, videoID string) {
+	if _, err := s.Repo.Get(r.Context(), videoID); err != nil {
+		s.writeVideoLookupError(w, videoID, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	_, _ = w.Write([]byte(playlist.BuildMaster(videoID, s.Renditions)))
+}
+
+func (s *Server) serveVariant(w http.ResponseWriter, r *http.Request, videoID, renditionName string) {
+	v, err := s.Repo.Get(r.Context(), videoID)
+	if err != nil {
+		s.writeVideoLookupError(w, videoID, err)
+		return
+	}
+	rend, ok := segment.RenditionByName(s.Renditions, renditionName)
+	if !ok {
+		http.Error(w, "unknown rendition", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	_, _ = w.Write([]byte(playlist.BuildVariant(float64(v.DurationSeconds), rend)))
+}
+
+func (s *Server) serveSegment(w http.ResponseWriter, r *http.Request, videoID, renditionName string, segIdx int) {
+	v, err := s.Repo.Get(r.Context(), videoID)
+	if err != nil {
+		s.writeVideoLookupError(w, videoID, err)
+		return
+	}
+	rend, ok := segment.RenditionByName(s.Renditions, renditionName)
+	if !ok {
+		http.Error(w, "unknown rendition", http.StatusNotFound)
+		return
+	}
+
+	cacheKey := cache.Key(videoID, renditionName, segIdx)
+	if cached, ok := s.Cache.Get(cacheKey); ok {
+		writeSegment(w, cached)
+		return
+	}
+
+	sourcePath, err := s.Fetcher.LocalPath(r.Context(), videoID, s.RawBucket, v.RawObjectPath)
+	if err != nil {
+		log.Printf("GET /videos/%s/%s/%d.ts: fetch source: %v", videoID, renditionName, segIdx, err)
+		http.Error(w, "source unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	keyframes, err := segment.KeyframeTimes(r.Context(), sourcePath)
+	if err != nil {
+		log.Printf("GET /videos/%s/%s/%d.ts: keyframe scan: %v; falling back to unaligned segment start", videoID, renditionName, segIdx, err)
+		keyframes = nil
+	}
+	start, duration := segment.Window(keyframes, segIdx, float64(v.DurationSeconds))
+	if duration <= 0 {
+		http.Error(w, "segment index out of range", http.StatusNotFound)
+		return
+	}
+
+	encoded, err := s.Encoder.EncodeSegment(r.Context(), sourcePath, rend, start, duration)
+	if err != nil {
+		log.Printf("GET /videos/%s/%s/%d.ts: encode: %v", videoID, renditionName, segIdx, err)
+		http.Error(w, "encode failed", http.StatusInternalServerError)
+		return
+	}
+
+	s.Cache.Put(cacheKey, encoded)
+	writeSegment(w, encoded)
+}
+
+func writeSegment(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "video/mp2t")
+	_, _ = w.Write(data)
+}
+
+func (s *Server) writeVideoLookupError(w http.ResponseWriter, videoID string, err error) {
+	if err == video.ErrNotFound {
+		http.Error(w, "video not found", http.StatusNotFound)
+		return
+	}
+	log.Printf("GET /videos/%s: lookup video: %v", videoID, err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
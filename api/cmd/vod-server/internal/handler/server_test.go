@@ -0,0 +1,161 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/cache"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/handler"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/segment"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/video"
+)
+
+type stubRepo struct {
+	video *video.Video
+	err   error
+}
+
+func (s *stubRepo) Get(_ context.Context, _ string) (*video.Video, error) {
+	return s.video, s.err
+}
+
+type stubFetcher struct {
+	path string
+	err  error
+}
+
+func (s *stubFetcher) LocalPath(_ context.Context, _, _, _ string) (string, error) {
+	return s.path, s.err
+}
+
+type stubEncoder struct {
+	calls int
+	out   []byte
+	err   error
+}
+
+func (s *stubEncoder) EncodeSegment(_ context.Context, _ string, _ segment.Rendition, _, _ float64) ([]byte, error) {
+	s.calls++
+	return s.out, s.err
+}
+
+func newTestServer(repo handler.VideoRepository, fetcher handler.SourceFetcher, enc handler.SegmentEncoder, c cache.SegmentCache) *handler.Server {
+	return &handler.Server{
+		Repo:       repo,
+		Fetcher:    fetcher,
+		Encoder:    enc,
+		Cache:      c,
+		RawBucket:  "raw-bucket",
+		Renditions: segment.DefaultRenditions(),
+	}
+}
+
+func TestServeMaster_UnknownVideo_Returns404(t *testing.T) {
+	s := newTestServer(&stubRepo{err: video.ErrNotFound}, &stubFetcher{}, &stubEncoder{}, cache.NewLRU(1))
+
+	req := httptest.NewRequest(http.MethodGet, "/videos/missing/index.m3u8", nil)
+	rec := httptest.NewRecorder()
+	s.NewRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestServeMaster_KnownVideo_ReturnsPlaylist(t *testing.T) {
+	s := newTestServer(&stubRepo{video: &video.Video{RawObjectPath: "raw/v.mp4", DurationSeconds: 30}}, &stubFetcher{}, &stubEncoder{}, cache.NewLRU(1))
+
+	req := httptest.NewRequest(http.MethodGet, "/videos/v1/index.m3u8", nil)
+	rec := httptest.NewRecorder()
+	s.NewRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != "application/vnd.apple.mpegurl" {
+		t.Errorf("Content-Type = %q", rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestServeVariant_UnknownRendition_Returns404(t *testing.T) {
+	s := newTestServer(&stubRepo{video: &video.Video{RawObjectPath: "raw/v.mp4", DurationSeconds: 30}}, &stubFetcher{}, &stubEncoder{}, cache.NewLRU(1))
+
+	req := httptest.NewRequest(http.MethodGet, "/videos/v1/4k/index.m3u8", nil)
+	rec := httptest.NewRecorder()
+	s.NewRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestServeSegment_CacheHit_SkipsEncoder(t *testing.T) {
+	c := cache.NewLRU(4)
+	c.Put(cache.Key("v1", "360p", 0), []byte("cached-bytes"))
+	enc := &stubEncoder{out: []byte("fresh-bytes")}
+	s := newTestServer(&stubRepo{video: &video.Video{RawObjectPath: "raw/v.mp4", DurationSeconds: 30}}, &stubFetcher{path: "/tmp/v.mp4"}, enc, c)
+
+	req := httptest.NewRequest(http.MethodGet, "/videos/v1/360p/0.ts", nil)
+	rec := httptest.NewRecorder()
+	s.NewRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if rec.Body.String() != "cached-bytes" {
+		t.Errorf("body = %q, want cached-bytes", rec.Body.String())
+	}
+	if enc.calls != 0 {
+		t.Errorf("encoder was called %d times, want 0 (cache hit)", enc.calls)
+	}
+}
+
+func TestServeSegment_CacheMiss_EncodesAndCaches(t *testing.T) {
+	c := cache.NewLRU(4)
+	enc := &stubEncoder{out: []byte("fresh-bytes")}
+	s := newTestServer(&stubRepo{video: &video.Video{RawObjectPath: "raw/v.mp4", DurationSeconds: 30}}, &stubFetcher{path: "/tmp/v.mp4"}, enc, c)
+
+	req := httptest.NewRequest(http.MethodGet, "/videos/v1/360p/0.ts", nil)
+	rec := httptest.NewRecorder()
+	s.NewRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if rec.Body.String() != "fresh-bytes" {
+		t.Errorf("body = %q, want fresh-bytes", rec.Body.String())
+	}
+	if enc.calls != 1 {
+		t.Errorf("encoder was called %d times, want 1", enc.calls)
+	}
+	if cached, ok := c.Get(cache.Key("v1", "360p", 0)); !ok || string(cached) != "fresh-bytes" {
+		t.Error("expected segment to be cached after a miss")
+	}
+}
+
+func TestServeSegment_IndexPastDuration_Returns404(t *testing.T) {
+	c := cache.NewLRU(4)
+	s := newTestServer(&stubRepo{video: &video.Video{RawObjectPath: "raw/v.mp4", DurationSeconds: 12}}, &stubFetcher{path: "/tmp/v.mp4"}, &stubEncoder{}, c)
+
+	req := httptest.NewRequest(http.MethodGet, "/videos/v1/360p/99.ts", nil)
+	rec := httptest.NewRecorder()
+	s.NewRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestServeSegment_InvalidFilename_Returns404(t *testing.T) {
+	s := newTestServer(&stubRepo{video: &video.Video{RawObjectPath: "raw/v.mp4", DurationSeconds: 30}}, &stubFetcher{}, &stubEncoder{}, cache.NewLRU(1))
+
+	req := httptest.NewRequest(http.MethodGet, "/videos/v1/360p/not-a-segment", nil)
+	rec := httptest.NewRecorder()
+	s.NewRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
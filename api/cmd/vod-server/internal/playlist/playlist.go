@@ -0,0 +1,90 @@
+// Package playlist generates HLS master and variant playlists in memory for
+// the VOD server, since there's no pre-transcoded output on disk to point to
+// — every segment is encoded on request.
+package playlist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/segment"
+)
+
+// BuildMaster returns the master playlist referencing each rendition's own
+// variant playlist at videos/{id}/{rendition}/index.m3u8.
+func BuildMaster(videoID string, renditions []segment.Rendition) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, rend := range renditions {
+		width, height := resolution(rend)
+		fmt.Fprintf(&b,
+			"#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=%q\n%s/index.m3u8\n",
+			bandwidth(rend), width, height, rend.Name, rend.Name,
+		)
+	}
+	return b.String()
+}
+
+// BuildVariant returns rend's media playlist for a source of durationSeconds,
+// with one #EXTINF entry per segment.SegmentCount segment. Segments are
+// listed as plain "{idx}.ts" paths relative to the variant playlist, and the
+// playlist is marked VOD since durationSeconds is known up front.
+func BuildVariant(durationSeconds float64, rend segment.Rendition) string {
+	count := segment.SegmentCount(durationSeconds)
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", segment.SegmentSeconds)
+	b.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	b.WriteString("#EXT-X-MEDIA-SEQUENCE:0\n")
+
+	remaining := durationSeconds
+	for i := 0; i < count; i++ {
+		segDuration := float64(segment.SegmentSeconds)
+		if remaining < segDuration {
+			segDuration = remaining
+		}
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n%d.ts\n", segDuration, i)
+		remaining -= segDuration
+	}
+	b.WriteString("#EXT-X-ENDLIST\n")
+	return b.String()
+}
+
+// bandwidth estimates rend's combined video+audio bitrate in bits/sec (e.g.
+// "1500k" -> 1500000), used for the HLS BANDWIDTH attribute.
+func bandwidth(rend segment.Rendition) int {
+	return parseBitrate(rend.VideoBitrate) + parseBitrate(rend.AudioBitrate)
+}
+
+// resolution returns a width/height pair derived from rend's target height
+// and a 16:9 aspect ratio, since the source aspect ratio isn't known here.
+func resolution(rend segment.Rendition) (width, height int) {
+	width = rend.Height * 16 / 9
+	width -= width % 2 // most encoders require even dimensions
+	return width, rend.Height
+}
+
+// parseBitrate converts an FFmpeg bitrate string ("500k", "2m", "128000") to
+// an integer count of bits per second, returning 0 for values it can't parse
+// since BANDWIDTH is advisory metadata.
+func parseBitrate(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	multiplier := 1
+	switch suffix := s[len(s)-1]; suffix {
+	case 'k', 'K':
+		multiplier = 1000
+		s = s[:len(s)-1]
+	case 'm', 'M':
+		multiplier = 1000000
+		s = s[:len(s)-1]
+	}
+	var n int
+	if _, err := fmt.Sscanf(s, "%d", &n); err != nil {
+		return 0
+	}
+	return n * multiplier
+}
@@ -0,0 +1,56 @@
+package playlist_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/playlist"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/segment"
+)
+
+func TestBuildMaster_ReferencesEachRenditionVariantPlaylist(t *testing.T) {
+	out := playlist.BuildMaster("video-1", segment.DefaultRenditions())
+
+	for _, rend := range segment.DefaultRenditions() {
+		if !strings.Contains(out, rend.Name+"/index.m3u8") {
+			t.Errorf("expected master playlist to reference %s/index.m3u8, got:\n%s", rend.Name, out)
+		}
+	}
+}
+
+func TestBuildMaster_IncludesBandwidthAttribute(t *testing.T) {
+	out := playlist.BuildMaster("video-1", []segment.Rendition{{Name: "360p", Height: 360, VideoBitrate: "500k", AudioBitrate: "64k"}})
+
+	if !strings.Contains(out, "BANDWIDTH=564000") {
+		t.Errorf("expected BANDWIDTH=564000, got:\n%s", out)
+	}
+}
+
+func TestBuildVariant_ListsOneEntryPerSegment(t *testing.T) {
+	out := playlist.BuildVariant(18, segment.DefaultRenditions()[0])
+
+	for _, want := range []string{"0.ts", "1.ts", "2.ts"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected variant playlist to list %s, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "3.ts") {
+		t.Errorf("expected exactly 3 segments, got:\n%s", out)
+	}
+}
+
+func TestBuildVariant_LastSegmentDurationIsRemainder(t *testing.T) {
+	out := playlist.BuildVariant(20, segment.DefaultRenditions()[0])
+
+	if !strings.Contains(out, "#EXTINF:2.000,\n3.ts") {
+		t.Errorf("expected final segment EXTINF:2.000 for 3.ts, got:\n%s", out)
+	}
+}
+
+func TestBuildVariant_EndsWithEndlist(t *testing.T) {
+	out := playlist.BuildVariant(12, segment.DefaultRenditions()[0])
+
+	if !strings.HasSuffix(strings.TrimSpace(out), "#EXT-X-ENDLIST") {
+		t.Errorf("expected playlist to end with #EXT-X-ENDLIST, got:\n%s", out)
+	}
+}
@@ -0,0 +1,77 @@
+package segment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// CommandRunner abstracts running FFmpeg and capturing its stdout, so tests
+// can inject a stub instead of shelling out. It is distinct from
+// ffmpeg.CommandRunner (api/cmd/transcoder/internal/ffmpeg): that interface
+// only reports success/failure, while on-demand segment encoding needs the
+// encoded bytes FFmpeg writes to stdout.
+type CommandRunner interface {
+	// Output runs name with args and returns what it wrote to stdout.
+	Output(ctx context.Context, name string, args []string) ([]byte, error)
+}
+
+// ExecCommandRunner is the real CommandRunner that shells out to the system.
+type ExecCommandRunner struct{}
+
+// Output runs name with args using os/exec, returning stdout. On failure the
+// error wraps stderr so callers can log what FFmpeg reported.
+func (ExecCommandRunner) Output(ctx context.Context, name string, args []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg exited with error: %w\nstderr:\n%s", err, stderr.String())
+	}
+	return out, nil
+}
+
+// Encoder runs the short FFmpeg invocation that produces a single MPEG-TS
+// segment on demand.
+type Encoder struct {
+	// Cmd is the command executor; defaults to ExecCommandRunner{}.
+	Cmd CommandRunner
+}
+
+// NewEncoder constructs an Encoder with the real ExecCommandRunner.
+func NewEncoder() *Encoder {
+	return &Encoder{Cmd: ExecCommandRunner{}}
+}
+
+func (e *Encoder) cmd() CommandRunner {
+	if e.Cmd != nil {
+		return e.Cmd
+	}
+	return ExecCommandRunner{}
+}
+
+// EncodeSegment transcodes [start, start+duration) of sourcePath into rend's
+// target resolution/bitrate, returning a single MPEG-TS segment's bytes.
+func (e *Encoder) EncodeSegment(ctx context.Context, sourcePath string, rend Rendition, start, duration float64) ([]byte, error) {
+	args := []string{
+		"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+		"-i", sourcePath,
+		"-t", strconv.FormatFloat(duration, 'f', 3, 64),
+		"-vf", fmt.Sprintf("scale=-2:%d", rend.Height),
+		"-c:v", "libx264",
+		"-b:v", rend.VideoBitrate,
+		"-c:a", "aac",
+		"-b:a", rend.AudioBitrate,
+		"-f", "mpegts",
+		"-muxdelay", "0",
+		"pipe:1",
+	}
+	out, err := e.cmd().Output(ctx, "ffmpeg", args)
+	if err != nil {
+		return nil, fmt.Errorf("encode segment: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,63 @@
+package segment_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/segment"
+)
+
+type stubCommandRunner struct {
+	lastName string
+	lastArgs []string
+	output   []byte
+	err      error
+}
+
+func (s *stubCommandRunner) Output(_ context.Context, name string, args []string) ([]byte, error) {
+	s.lastName = name
+	s.lastArgs = args
+	return s.output, s.err
+}
+
+func TestEncodeSegment_ReturnsCommandOutput(t *testing.T) {
+	cmd := &stubCommandRunner{output: []byte("mpegts-bytes")}
+	enc := &segment.Encoder{Cmd: cmd}
+
+	out, err := enc.EncodeSegment(context.Background(), "/tmp/source.mp4", segment.Rendition{Name: "360p", Height: 360, VideoBitrate: "500k", AudioBitrate: "64k"}, 12, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != "mpegts-bytes" {
+		t.Errorf("out = %q, want %q", out, "mpegts-bytes")
+	}
+	if cmd.lastName != "ffmpeg" {
+		t.Errorf("lastName = %q, want ffmpeg", cmd.lastName)
+	}
+}
+
+func TestEncodeSegment_PassesWindowAndRenditionArgs(t *testing.T) {
+	cmd := &stubCommandRunner{}
+	enc := &segment.Encoder{Cmd: cmd}
+
+	_, _ = enc.EncodeSegment(context.Background(), "/tmp/source.mp4", segment.Rendition{Name: "720p", Height: 720, VideoBitrate: "1500k", AudioBitrate: "128k"}, 12, 6)
+
+	wantArgs := []string{"-ss", "12.000", "-i", "/tmp/source.mp4", "-t", "6.000", "-vf", "scale=-2:720", "-c:v", "libx264", "-b:v", "1500k", "-c:a", "aac", "-b:a", "128k", "-f", "mpegts", "-muxdelay", "0", "pipe:1"}
+	if len(cmd.lastArgs) != len(wantArgs) {
+		t.Fatalf("lastArgs = %v, want %v", cmd.lastArgs, wantArgs)
+	}
+	for i := range wantArgs {
+		if cmd.lastArgs[i] != wantArgs[i] {
+			t.Errorf("lastArgs[%d] = %q, want %q", i, cmd.lastArgs[i], wantArgs[i])
+		}
+	}
+}
+
+func TestEncodeSegment_CommandError_ReturnsError(t *testing.T) {
+	cmd := &stubCommandRunner{err: context.DeadlineExceeded}
+	enc := &segment.Encoder{Cmd: cmd}
+
+	if _, err := enc.EncodeSegment(context.Background(), "/tmp/source.mp4", segment.DefaultRenditions()[0], 0, 6); err == nil {
+		t.Error("expected error")
+	}
+}
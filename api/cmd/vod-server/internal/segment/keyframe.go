@@ -0,0 +1,70 @@
+package segment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+)
+
+// KeyframeTimes runs ffprobe against path and returns the presentation
+// timestamps, in seconds and sorted ascending, of every keyframe in the
+// first video stream. Window uses these to align an on-demand segment's
+// start to a GOP boundary instead of an arbitrary timecode. Callers should
+// treat a failure here as non-fatal and fall back to Window's unsnapped
+// behavior (pass a nil slice), since a missing/broken ffprobe binary
+// shouldn't stop playback, only make the segment less cleanly cut.
+func KeyframeTimes(ctx context.Context, path string) ([]float64, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "frame=pkt_pts_time,key_frame",
+		"-of", "json",
+		path,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("ffprobe exited with error: %w\nstderr:\n%s", err, exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+	return parseKeyframeTimes(out)
+}
+
+// ffprobeFrameOutput mirrors the fields of ffprobe's `-show_entries
+// frame=pkt_pts_time,key_frame -of json` output that parseKeyframeTimes needs.
+type ffprobeFrameOutput struct {
+	Frames []ffprobeFrame `json:"frames"`
+}
+
+type ffprobeFrame struct {
+	PktPtsTime string `json:"pkt_pts_time"`
+	KeyFrame   int    `json:"key_frame"`
+}
+
+// parseKeyframeTimes decodes raw ffprobe JSON into a sorted slice of
+// keyframe timestamps, discarding frames that aren't keyframes or whose
+// timestamp ffprobe couldn't report.
+func parseKeyframeTimes(raw []byte) ([]float64, error) {
+	var out ffprobeFrameOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	times := make([]float64, 0, len(out.Frames))
+	for _, f := range out.Frames {
+		if f.KeyFrame != 1 {
+			continue
+		}
+		t, err := strconv.ParseFloat(f.PktPtsTime, 64)
+		if err != nil {
+			continue
+		}
+		times = append(times, t)
+	}
+	sort.Float64s(times)
+	return times, nil
+}
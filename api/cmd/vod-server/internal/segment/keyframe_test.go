@@ -0,0 +1,45 @@
+package segment
+
+import "testing"
+
+func TestParseKeyframeTimes_FiltersNonKeyframesAndSorts(t *testing.T) {
+	raw := []byte(`{
+		"frames": [
+			{"pkt_pts_time": "5.000000", "key_frame": 0},
+			{"pkt_pts_time": "0.000000", "key_frame": 1},
+			{"pkt_pts_time": "10.000000", "key_frame": 1}
+		]
+	}`)
+
+	times, err := parseKeyframeTimes(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []float64{0, 10}
+	if len(times) != len(want) {
+		t.Fatalf("times = %v, want %v", times, want)
+	}
+	for i := range want {
+		if times[i] != want[i] {
+			t.Errorf("times[%d] = %v, want %v", i, times[i], want[i])
+		}
+	}
+}
+
+func TestParseKeyframeTimes_UnparsableTimestamp_Skipped(t *testing.T) {
+	raw := []byte(`{"frames": [{"pkt_pts_time": "N/A", "key_frame": 1}]}`)
+
+	times, err := parseKeyframeTimes(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(times) != 0 {
+		t.Errorf("times = %v, want empty", times)
+	}
+}
+
+func TestParseKeyframeTimes_InvalidJSON_ReturnsError(t *testing.T) {
+	if _, err := parseKeyframeTimes([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
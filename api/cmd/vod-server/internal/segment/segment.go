@@ -0,0 +1,99 @@
+// Package segment computes keyframe-aligned encode windows for on-demand HLS
+// segment transcoding and runs the FFmpeg invocation that produces one.
+package segment
+
+// SegmentSeconds is the target duration of each on-demand segment, matching
+// the pre-transcoder's HLS -hls_time 6 so a player can mix VOD-server and
+// pre-transcoded renditions without the player noticing a cadence change.
+const SegmentSeconds = 6
+
+// Rendition describes a single on-demand output stream. It intentionally
+// mirrors api/cmd/transcoder/internal/ffmpeg.Rendition rather than importing
+// it: that package sits under the transcoder's own internal/ tree and can't
+// be imported from this sibling service.
+type Rendition struct {
+	// Name is used as the variant playlist/path segment (e.g. "360p").
+	Name string
+	// Height is the video height in pixels (e.g. 360).
+	Height int
+	// VideoBitrate is the target video bitrate string (e.g. "500k").
+	VideoBitrate string
+	// AudioBitrate is the target audio bitrate string (e.g. "64k").
+	AudioBitrate string
+}
+
+// DefaultRenditions returns the renditions the VOD server can produce
+// on-demand, mirroring api/cmd/transcoder/internal/ffmpeg.DefaultRenditions.
+func DefaultRenditions() []Rendition {
+	return []Rendition{
+		{Name: "360p", Height: 360, VideoBitrate: "500k", AudioBitrate: "64k"},
+		{Name: "720p", Height: 720, VideoBitrate: "1500k", AudioBitrate: "128k"},
+		{Name: "1080p", Height: 1080, VideoBitrate: "3000k", AudioBitrate: "192k"},
+	}
+}
+
+// RenditionByName returns the rendition in renditions whose Name matches
+// name, or false if none does.
+func RenditionByName(renditions []Rendition, name string) (Rendition, bool) {
+	for _, rend := range renditions {
+		if rend.Name == name {
+			return rend, true
+		}
+	}
+	return Rendition{}, false
+}
+
+// SegmentCount returns how many SegmentSeconds-long segments a variant
+// playlist for a source of the given duration should list, rounding up since
+// the final segment is typically shorter.
+func SegmentCount(durationSeconds float64) int {
+	if durationSeconds <= 0 {
+		return 0
+	}
+	count := int(durationSeconds / SegmentSeconds)
+	if float64(count)*SegmentSeconds < durationSeconds {
+		count++
+	}
+	return count
+}
+
+// Window computes the -ss/-t arguments for encoding segment index segIdx on
+// demand. The ideal start is segIdx*SegmentSeconds, but starting an encode
+// mid-GOP produces a segment FFmpeg can't decode independently, so the start
+// is snapped backward to the nearest keyframe at or before it. keyframes must
+// be sorted ascending; a nil or empty slice (e.g. ffprobe's keyframe scan
+// failed) disables snapping and falls back to the ideal, unsnapped start.
+// Duration is capped so the window never runs past durationSeconds.
+func Window(keyframes []float64, segIdx int, durationSeconds float64) (start, duration float64) {
+	ideal := float64(segIdx) * SegmentSeconds
+	start = nearestKeyframeAtOrBefore(keyframes, ideal)
+
+	end := start + SegmentSeconds
+	if durationSeconds > 0 && end > durationSeconds {
+		end = durationSeconds
+	}
+	duration = end - start
+	if duration < 0 {
+		duration = 0
+	}
+	return start, duration
+}
+
+// nearestKeyframeAtOrBefore returns the largest value in keyframes that does
+// not exceed target, or target itself if keyframes is empty or every
+// keyframe is after target.
+func nearestKeyframeAtOrBefore(keyframes []float64, target float64) float64 {
+	best := target
+	found := false
+	for _, kf := range keyframes {
+		if kf > target {
+			break
+		}
+		best = kf
+		found = true
+	}
+	if !found {
+		return target
+	}
+	return best
+}
@@ -0,0 +1,71 @@
+package segment_test
+
+import (
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/segment"
+)
+
+func TestSegmentCount_WholeNumberOfSegments(t *testing.T) {
+	if got := segment.SegmentCount(18); got != 3 {
+		t.Errorf("SegmentCount(18) = %d, want 3", got)
+	}
+}
+
+func TestSegmentCount_RoundsUpForRemainder(t *testing.T) {
+	if got := segment.SegmentCount(20); got != 4 {
+		t.Errorf("SegmentCount(20) = %d, want 4", got)
+	}
+}
+
+func TestSegmentCount_ZeroDuration_ReturnsZero(t *testing.T) {
+	if got := segment.SegmentCount(0); got != 0 {
+		t.Errorf("SegmentCount(0) = %d, want 0", got)
+	}
+}
+
+func TestWindow_NoKeyframes_UsesIdealStart(t *testing.T) {
+	start, duration := segment.Window(nil, 2, 30)
+	if start != 12 {
+		t.Errorf("start = %v, want 12", start)
+	}
+	if duration != 6 {
+		t.Errorf("duration = %v, want 6", duration)
+	}
+}
+
+func TestWindow_SnapsToNearestKeyframeAtOrBefore(t *testing.T) {
+	keyframes := []float64{0, 5, 11.5, 18, 24}
+	start, _ := segment.Window(keyframes, 2, 30)
+	if start != 11.5 {
+		t.Errorf("start = %v, want 11.5", start)
+	}
+}
+
+func TestWindow_LastSegment_CapsDurationAtSourceEnd(t *testing.T) {
+	start, duration := segment.Window(nil, 4, 26)
+	if start != 24 {
+		t.Errorf("start = %v, want 24", start)
+	}
+	if duration != 2 {
+		t.Errorf("duration = %v, want 2", duration)
+	}
+}
+
+func TestRenditionByName_Found(t *testing.T) {
+	renditions := segment.DefaultRenditions()
+	rend, ok := segment.RenditionByName(renditions, "720p")
+	if !ok {
+		t.Fatal("expected 720p to be found")
+	}
+	if rend.Height != 720 {
+		t.Errorf("Height = %d, want 720", rend.Height)
+	}
+}
+
+func TestRenditionByName_NotFound(t *testing.T) {
+	_, ok := segment.RenditionByName(segment.DefaultRenditions(), "4k")
+	if ok {
+		t.Error("expected 4k to not be found")
+	}
+}
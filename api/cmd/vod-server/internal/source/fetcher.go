@@ -0,0 +1,72 @@
+// Package source resolves a video's raw object to a local file path that
+// FFmpeg can seek within, downloading and caching it on first use so repeat
+// segment/keyframe requests for the same video don't each re-fetch the whole
+// source from object storage.
+package source
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Downloader copies a bucket/objectPath to a local file. Satisfied by
+// *blob.Downloader (api/internal/blob).
+type Downloader interface {
+	Download(ctx context.Context, bucket, objectPath, destPath string) error
+}
+
+// Fetcher resolves video IDs to a local copy of their raw source, caching
+// downloads under CacheDir so concurrent and repeat requests for the same
+// video reuse the same file instead of re-downloading it.
+type Fetcher struct {
+	Downloader Downloader
+	// CacheDir is the local directory downloads are cached under.
+	CacheDir string
+
+	mu       sync.Mutex
+	inFlight map[string]*sync.Once
+}
+
+// NewFetcher constructs a Fetcher backed by dl, caching downloads under
+// cacheDir.
+func NewFetcher(dl Downloader, cacheDir string) *Fetcher {
+	return &Fetcher{Downloader: dl, CacheDir: cacheDir, inFlight: make(map[string]*sync.Once)}
+}
+
+// LocalPath returns the local filesystem path for videoID's raw object at
+// bucket/objectPath, downloading it on first request. Concurrent callers for
+// the same video ID share a single download.
+func (f *Fetcher) LocalPath(ctx context.Context, videoID, bucket, objectPath string) (string, error) {
+	destPath := filepath.Join(f.CacheDir, videoID+filepath.Ext(objectPath))
+
+	if _, err := os.Stat(destPath); err == nil {
+		return destPath, nil
+	}
+
+	once := f.onceFor(videoID)
+	var downloadErr error
+	once.Do(func() {
+		downloadErr = f.Downloader.Download(ctx, bucket, objectPath, destPath)
+	})
+	if downloadErr != nil {
+		return "", fmt.Errorf("fetch source for video %s: %w", videoID, downloadErr)
+	}
+	if _, err := os.Stat(destPath); err != nil {
+		return "", fmt.Errorf("fetch source for video %s: %w", videoID, err)
+	}
+	return destPath, nil
+}
+
+func (f *Fetcher) onceFor(videoID string) *sync.Once {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	once, ok := f.inFlight[videoID]
+	if !ok {
+		once = &sync.Once{}
+		f.inFlight[videoID] = once
+	}
+	return once
+}
@@ -0,0 +1,59 @@
+// Package video provides the minimal, read-only repository the VOD server
+// needs to resolve a video ID to its raw source object. It deliberately
+// doesn't import api/cmd/transcoder/internal/video: that package sits under
+// the transcoder's own internal/ tree and can't be imported from this
+// sibling service.
+package video
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned by Get when videoID doesn't match any video.
+var ErrNotFound = errors.New("video: not found")
+
+// Video is the subset of a video row the VOD server needs to serve it.
+type Video struct {
+	// RawObjectPath is the object path within the raw bucket (e.g.
+	// "raw/<uuid>.mp4") that the source was uploaded to.
+	RawObjectPath string
+	// DurationSeconds is the probed source duration, used to size the
+	// variant playlists without re-probing on every request.
+	DurationSeconds int
+}
+
+// Querier abstracts *sql.DB so that tests can inject a stub.
+type Querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Repository resolves video IDs to their raw source location.
+type Repository struct {
+	db Querier
+}
+
+// NewRepository constructs a Repository backed by db.
+func NewRepository(db Querier) *Repository {
+	return &Repository{db: db}
+}
+
+// Get returns the raw object path and duration for videoID, or ErrNotFound
+// if no such video exists. duration_seconds is populated by the transcoder
+// (see api/cmd/transcoder/internal/video.Repository.UpdateVideo) via the
+// shared api/migrations column of the same name.
+func (r *Repository) Get(ctx context.Context, videoID string) (*Video, error) {
+	const query = `SELECT raw_object_path, duration_seconds FROM videos WHERE id = $1`
+
+	var v Video
+	err := r.db.QueryRowContext(ctx, query, videoID).Scan(&v.RawObjectPath, &v.DurationSeconds)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query video: %w", err)
+	}
+	return &v, nil
+}
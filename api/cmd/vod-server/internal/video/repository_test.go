@@ -0,0 +1,121 @@
+package video_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/video"
+)
+
+// ── minimal fake SQL driver, for stubbing QueryRowContext ─────────────────────
+// Registers a "vod-video-fakedb" driver that allows tests to return a
+// pre-configured (raw_object_path, duration_seconds) row without a real
+// database.
+
+func init() {
+	sql.Register("vod-video-fakedb", &fakeDriver{})
+}
+
+var dsnCounter int64
+
+func nextDSN() string {
+	n := atomic.AddInt64(&dsnCounter, 1)
+	return fmt.Sprintf("vod-video-dsn-%d", n)
+}
+
+type fakeRow struct {
+	rawObjectPath   string
+	durationSeconds int
+	found           bool
+}
+
+var rowRegistry = map[string]fakeRow{}
+
+type fakeDriver struct{}
+
+func (*fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{row: rowRegistry[name]}, nil
+}
+
+type fakeConn struct{ row fakeRow }
+
+func (c *fakeConn) Prepare(_ string) (driver.Stmt, error) { return &fakeStmt{row: c.row}, nil }
+func (c *fakeConn) Close() error                          { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)             { return &fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (*fakeTx) Commit() error   { return nil }
+func (*fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct{ row fakeRow }
+
+func (*fakeStmt) Close() error  { return nil }
+func (*fakeStmt) NumInput() int { return -1 }
+func (*fakeStmt) Exec(_ []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("unused")
+}
+func (s *fakeStmt) Query(_ []driver.Value) (driver.Rows, error) {
+	return &fakeRows{row: s.row}, nil
+}
+
+// fakeRows yields a single (raw_object_path, duration_seconds) row, or no
+// rows at all when row.found is false, so tests can exercise both the found
+// and sql.ErrNoRows paths.
+type fakeRows struct {
+	row  fakeRow
+	read bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"raw_object_path", "duration_seconds"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if !r.row.found || r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = r.row.rawObjectPath
+	dest[1] = int64(r.row.durationSeconds)
+	return nil
+}
+
+// rowDB returns a *sql.DB whose QueryRowContext yields row.
+func rowDB(row fakeRow) *sql.DB {
+	dsn := nextDSN()
+	rowRegistry[dsn] = row
+	db, _ := sql.Open("vod-video-fakedb", dsn)
+	return db
+}
+
+// ── Get ────────────────────────────────────────────────────────────────────
+
+func TestGet_Found_ReturnsVideo(t *testing.T) {
+	db := rowDB(fakeRow{rawObjectPath: "raw/video-1.mp4", durationSeconds: 90, found: true})
+	repo := video.NewRepository(db)
+
+	v, err := repo.Get(context.Background(), "video-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.RawObjectPath != "raw/video-1.mp4" {
+		t.Errorf("RawObjectPath = %q, want raw/video-1.mp4", v.RawObjectPath)
+	}
+	if v.DurationSeconds != 90 {
+		t.Errorf("DurationSeconds = %d, want 90", v.DurationSeconds)
+	}
+}
+
+func TestGet_NotFound_ReturnsErrNotFound(t *testing.T) {
+	db := rowDB(fakeRow{found: false})
+	repo := video.NewRepository(db)
+
+	_, err := repo.Get(context.Background(), "missing")
+	if err != video.ErrNotFound {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Command vod-server is an alternative to the batch transcoder Cloud Run
+// Job: instead of pre-transcoding every HLS rendition up front, it serves
+// variant playlists and .ts segments on demand from a single high-quality
+// source stored in RAW_BUCKET, encoding only the segments a viewer actually
+// requests. This lets operators skip the batch job entirely for cold or
+// long-tail content and only spend CPU on segments that get watched.
+//
+// Required environment variables:
+//
+//	RAW_BUCKET — GCS bucket containing raw uploads (e.g. mytube-raw-uploads)
+//
+// Object storage backend (see api/internal/blob for the full variable list):
+//
+//	BLOB_BACKEND — "gcs" (default), "s3", or "s3compat" for MinIO/B2/Spaces
+//
+// Segment cache (optional):
+//
+//	VOD_CACHE_SEGMENTS   — max encoded segments to keep in the in-memory LRU
+//	                       cache (default 256)
+//	VOD_SOURCE_CACHE_DIR — local directory downloaded sources are cached
+//	                       under (default os.TempDir()/vod-server-sources)
+//
+// Database connection (same as api service, using Cloud SQL Unix socket):
+//
+//	INSTANCE_UNIX_SOCKET — Cloud SQL Unix socket path (when running on Cloud Run)
+//	DB_USER, DB_PASSWORD, DB_NAME — credentials (same as API)
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/cache"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/handler"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/segment"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/source"
+	"github.com/ai-teammate/mytube/api/cmd/vod-server/internal/video"
+	"github.com/ai-teammate/mytube/api/internal/blob"
+	"github.com/ai-teammate/mytube/api/internal/database"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Printf("vod-server error: %v", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	rawBucket := os.Getenv("RAW_BUCKET")
+	if rawBucket == "" {
+		return fmt.Errorf("required env var RAW_BUCKET is not set")
+	}
+
+	ctx := context.Background()
+
+	// Initialise the configured object-storage backend (GCS by default; set
+	// BLOB_BACKEND=s3 or s3compat to target AWS S3, MinIO, Backblaze B2, etc.).
+	backend, err := blob.Open(ctx, blob.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("open blob backend: %w", err)
+	}
+
+	db, err := database.Open()
+	if err != nil {
+		return fmt.Errorf("open db: %w", err)
+	}
+	defer db.Close()
+
+	fetcher := source.NewFetcher(blob.NewDownloader(backend), sourceCacheDir())
+
+	srv := &handler.Server{
+		Repo:       video.NewRepository(db),
+		Fetcher:    fetcher,
+		Encoder:    segment.NewEncoder(),
+		Cache:      cache.NewLRU(cacheSegmentsFromEnv()),
+		RawBucket:  rawBucket,
+		Renditions: segment.DefaultRenditions(),
+	}
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	log.Printf("listening on :%s", port)
+	return http.ListenAndServe(":"+port, srv.NewRouter())
+}
+
+// cacheSegmentsFromEnv reads VOD_CACHE_SEGMENTS, defaulting to 256 for an
+// unset or unparseable value.
+func cacheSegmentsFromEnv() int {
+	const defaultCapacity = 256
+	v := os.Getenv("VOD_CACHE_SEGMENTS")
+	if v == "" {
+		return defaultCapacity
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return defaultCapacity
+	}
+	return n
+}
+
+// sourceCacheDir reads VOD_SOURCE_CACHE_DIR, defaulting to a subdirectory of
+// the system temp directory.
+func sourceCacheDir() string {
+	if dir := os.Getenv("VOD_SOURCE_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "vod-server-sources")
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FromEnv builds a MultiVerifier from the AUTH_PROVIDERS environment
+// variable:
+//
+//	AUTH_PROVIDERS — comma-separated list of providers to enable, e.g.
+//	                 "firebase,google". Each entry is one of:
+//
+//	  firebase — FirebaseVerifier, configured via FIREBASE_PROJECT_ID
+//	  static   — StaticVerifier, for local dev and tests; operators should
+//	             never enable this in production
+//	  anything else — a generic OIDCVerifier, configured via
+//	             <NAME>_OIDC_ISSUER and <NAME>_OIDC_AUDIENCE (NAME is the
+//	             entry upper-cased, e.g. GOOGLE_OIDC_ISSUER for "google")
+//
+// At least one provider must be enabled.
+func FromEnv(ctx context.Context) (*MultiVerifier, error) {
+	raw := os.Getenv("AUTH_PROVIDERS")
+	if raw == "" {
+		raw = "firebase"
+	}
+
+	byIssuer := make(map[string]TokenVerifier)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		switch name {
+		case "firebase":
+			v, err := NewFirebaseVerifier(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("auth: configure firebase provider: %w", err)
+			}
+			byIssuer[v.Issuer()] = v
+		case "static":
+			byIssuer[StaticIssuer] = NewStaticVerifier()
+		default:
+			v, err := oidcVerifierFromEnv(name)
+			if err != nil {
+				return nil, fmt.Errorf("auth: configure %s provider: %w", name, err)
+			}
+			byIssuer[v.IssuerURL] = v
+		}
+	}
+
+	if len(byIssuer) == 0 {
+		return nil, fmt.Errorf("auth: AUTH_PROVIDERS must name at least one provider")
+	}
+
+	return NewMultiVerifier(byIssuer), nil
+}
+
+// oidcVerifierFromEnv builds an OIDCVerifier for a provider named name (e.g.
+// "google"), reading its issuer and audience from <NAME>_OIDC_ISSUER and
+// <NAME>_OIDC_AUDIENCE.
+func oidcVerifierFromEnv(name string) (*OIDCVerifier, error) {
+	prefix := strings.ToUpper(name)
+
+	issuer := os.Getenv(prefix + "_OIDC_ISSUER")
+	if issuer == "" {
+		return nil, fmt.Errorf("%s_OIDC_ISSUER env var is not set", prefix)
+	}
+	audience := os.Getenv(prefix + "_OIDC_AUDIENCE")
+	if audience == "" {
+		return nil, fmt.Errorf("%s_OIDC_AUDIENCE env var is not set", prefix)
+	}
+
+	return NewOIDCVerifier(issuer, audience, name), nil
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jwtHeader is the subset of a JWT header this package needs: the signing
+// algorithm and the key ID used to look it up in a JWKS.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtPayload is the subset of standard OIDC ID token claims this package
+// checks. Unrecognised claims are ignored.
+type jwtPayload struct {
+	Issuer   string `json:"iss"`
+	Subject  string `json:"sub"`
+	Audience string `json:"aud"`
+	Email    string `json:"email"`
+	Expiry   int64  `json:"exp"`
+}
+
+// validate checks the payload's exp/iss/aud claims against wantIssuer and
+// wantAudience.
+func (p jwtPayload) validate(wantIssuer, wantAudience string) error {
+	if p.Expiry != 0 && time.Now().Unix() >= p.Expiry {
+		return fmt.Errorf("token expired at %d", p.Expiry)
+	}
+	if wantIssuer != "" && p.Issuer != wantIssuer {
+		return fmt.Errorf("unexpected issuer %q, want %q", p.Issuer, wantIssuer)
+	}
+	if wantAudience != "" && p.Audience != wantAudience {
+		return fmt.Errorf("unexpected audience %q, want %q", p.Audience, wantAudience)
+	}
+	return nil
+}
+
+// splitJWT decodes a compact JWT's header and payload segments (without
+// verifying the signature) and returns the raw signing input (header.payload)
+// and decoded signature bytes, for the caller to verify separately.
+func splitJWT(token string) (header jwtHeader, payload jwtPayload, signingInput string, signature []byte, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return jwtHeader{}, jwtPayload{}, "", nil, fmt.Errorf("malformed JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return jwtHeader{}, jwtPayload{}, "", nil, fmt.Errorf("decode JWT header: %w", err)
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return jwtHeader{}, jwtPayload{}, "", nil, fmt.Errorf("parse JWT header: %w", err)
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return jwtHeader{}, jwtPayload{}, "", nil, fmt.Errorf("decode JWT payload: %w", err)
+	}
+	if err := json.Unmarshal(payloadJSON, &payload); err != nil {
+		return jwtHeader{}, jwtPayload{}, "", nil, fmt.Errorf("parse JWT payload: %w", err)
+	}
+
+	signature, err = base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return jwtHeader{}, jwtPayload{}, "", nil, fmt.Errorf("decode JWT signature: %w", err)
+	}
+
+	return header, payload, parts[0] + "." + parts[1], signature, nil
+}
+
+// peekIssuer extracts the "iss" claim from a JWT without verifying its
+// signature, so MultiVerifier can route the token to the right provider
+// before any cryptographic work happens.
+func peekIssuer(token string) (string, error) {
+	_, payload, _, _, err := splitJWT(token)
+	if err != nil {
+		return "", err
+	}
+	return payload.Issuer, nil
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MultiVerifier dispatches VerifyIDToken to one of several TokenVerifiers
+// based on the token's "iss" claim, peeked without checking the signature.
+// StaticVerifier tokens ("static:<claims>") are not JWTs and are routed to
+// StaticIssuer by their prefix instead. This lets operators enable several identity providers at once (Firebase
+// plus one or more OIDC providers) behind a single TokenVerifier.
+type MultiVerifier struct {
+	byIssuer map[string]TokenVerifier
+}
+
+// NewMultiVerifier builds a MultiVerifier that routes tokens to verifiers by
+// issuer.
+func NewMultiVerifier(byIssuer map[string]TokenVerifier) *MultiVerifier {
+	return &MultiVerifier{byIssuer: byIssuer}
+}
+
+// VerifyIDToken peeks idToken's "iss" claim, looks up the matching verifier,
+// and delegates to it. The signature is only checked by the chosen verifier,
+// not by this lookup.
+func (m *MultiVerifier) VerifyIDToken(ctx context.Context, idToken string) (*TokenClaims, error) {
+	issuer := StaticIssuer
+	if !strings.HasPrefix(idToken, "static:") {
+		var err error
+		issuer, err = peekIssuer(idToken)
+		if err != nil {
+			return nil, fmt.Errorf("multiverifier: %w", err)
+		}
+	}
+
+	verifier, ok := m.byIssuer[issuer]
+	if !ok {
+		return nil, fmt.Errorf("multiverifier: no provider registered for issuer %q", issuer)
+	}
+
+	return verifier.VerifyIDToken(ctx, idToken)
+}
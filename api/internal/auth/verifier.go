@@ -1,4 +1,7 @@
-// Package auth provides Firebase ID-token verification.
+// Package auth provides ID-token verification across multiple identity
+// providers (Firebase, generic OIDC providers, and a dev/static verifier for
+// tests), unified behind the TokenVerifier interface and routed by
+// MultiVerifier.
 package auth
 
 import (
@@ -10,12 +13,39 @@ import (
 	firebaseAuth "firebase.google.com/go/v4/auth"
 )
 
-// TokenClaims holds the verified claims extracted from a Firebase ID token.
+// TokenClaims holds the verified claims extracted from an ID token, with the
+// issuing provider normalized so callers can key storage on (Provider, UID)
+// rather than assuming every UID came from Firebase.
 type TokenClaims struct {
-	// UID is the Firebase user UID.
+	// UID is the subject ("sub") claim: the user's unique ID within Provider.
 	UID string
-	// Email is the user's email address as recorded in Firebase Auth.
+	// Email is the user's email address as recorded by the provider.
 	Email string
+	// Issuer is the token's "iss" claim, used by MultiVerifier to route to
+	// the right TokenVerifier.
+	Issuer string
+	// Provider is the short name of the verifier that issued these claims,
+	// e.g. "firebase", "google", "apple", "static".
+	Provider string
+	// EmailVerified is the provider's "email_verified" claim. Checked by
+	// middleware.RequireEmailVerified.
+	EmailVerified bool
+	// Roles holds the token's "role"/"roles" custom claim(s), if present.
+	// Checked by middleware.RequireRole / RequireAnyRole.
+	Roles []string
+	// Custom holds the full set of decoded token claims, for callers that
+	// need a claim the fields above don't surface (see middleware.RequireClaims).
+	Custom map[string]any
+}
+
+// HasRole reports whether role is present in c.Roles.
+func (c *TokenClaims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }
 
 // TokenVerifier is the interface that wraps Firebase token verification.
@@ -28,7 +58,8 @@ type TokenVerifier interface {
 // FirebaseVerifier is the production TokenVerifier backed by the Firebase
 // Admin SDK.  It reads FIREBASE_PROJECT_ID from the environment.
 type FirebaseVerifier struct {
-	client *firebaseAuth.Client
+	client    *firebaseAuth.Client
+	projectID string
 }
 
 // NewFirebaseVerifier creates a FirebaseVerifier.  When running on Cloud Run
@@ -50,7 +81,13 @@ func NewFirebaseVerifier(ctx context.Context) (*FirebaseVerifier, error) {
 		return nil, fmt.Errorf("firebase auth client: %w", err)
 	}
 
-	return &FirebaseVerifier{client: client}, nil
+	return &FirebaseVerifier{client: client, projectID: projectID}, nil
+}
+
+// Issuer returns the "iss" claim Firebase stamps on its ID tokens, so
+// MultiVerifier can route tokens to this verifier without a signature check.
+func (v *FirebaseVerifier) Issuer() string {
+	return "https://securetoken.google.com/" + v.projectID
 }
 
 // VerifyIDToken validates the token against Firebase and returns the verified
@@ -63,9 +100,38 @@ func (v *FirebaseVerifier) VerifyIDToken(ctx context.Context, idToken string) (*
 	}
 
 	email, _ := t.Claims["email"].(string)
+	issuer, _ := t.Claims["iss"].(string)
+	if issuer == "" {
+		issuer = v.Issuer()
+	}
+	emailVerified, _ := t.Claims["email_verified"].(bool)
 
 	return &TokenClaims{
-		UID:   t.UID,
-		Email: email,
+		UID:           t.UID,
+		Email:         email,
+		Issuer:        issuer,
+		Provider:      "firebase",
+		EmailVerified: emailVerified,
+		Roles:         extractRoles(t.Claims),
+		Custom:        t.Claims,
 	}, nil
 }
+
+// extractRoles reads a "role" (string) or "roles" ([]interface{} of strings)
+// custom claim — set via the Firebase Admin SDK's custom claims API — into
+// TokenClaims.Roles.
+func extractRoles(claims map[string]interface{}) []string {
+	if roles, ok := claims["roles"].([]interface{}); ok {
+		out := make([]string, 0, len(roles))
+		for _, r := range roles {
+			if s, ok := r.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	if role, ok := claims["role"].(string); ok && role != "" {
+		return []string{role}
+	}
+	return nil
+}
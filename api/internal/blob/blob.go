@@ -0,0 +1,206 @@
+// Package blob provides a pluggable object-storage abstraction shared by
+// services that need to read and write video assets: GCS, AWS S3, and
+// generic S3-compatible endpoints (MinIO, Backblaze B2, DigitalOcean
+// Spaces). The active backend is selected at startup via the BLOB_BACKEND
+// environment variable so callers never depend on a specific cloud provider.
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ObjectReader abstracts backend object reads so tests can inject a stub.
+type ObjectReader interface {
+	// NewReader opens a reader for the given bucket/object.
+	NewReader(ctx context.Context, bucket, object string) (io.ReadCloser, error)
+}
+
+// ObjectWriter abstracts backend object writes so tests can inject a stub.
+type ObjectWriter interface {
+	// NewWriter opens a writer for the given bucket/object. The caller must
+	// close the writer to finalise the upload.
+	NewWriter(ctx context.Context, bucket, object string) io.WriteCloser
+}
+
+// ObjectExister checks for the existence of an object without reading it,
+// used to detect work a previous (possibly interrupted) run already
+// finished, e.g. checkpoint package's resumable-job markers.
+type ObjectExister interface {
+	// Exists reports whether bucket/object exists.
+	Exists(ctx context.Context, bucket, object string) (bool, error)
+}
+
+// ObjectDeleter removes an object.
+type ObjectDeleter interface {
+	// Delete removes bucket/object. It is a no-op, not an error, if the
+	// object does not exist.
+	Delete(ctx context.Context, bucket, object string) error
+}
+
+// Backend is a named object-storage implementation providing read, write,
+// existence-check, and delete access, selected by BLOB_BACKEND.
+type Backend interface {
+	ObjectReader
+	ObjectWriter
+	ObjectExister
+	ObjectDeleter
+}
+
+// BackendFactory constructs a Backend from a Config. Factories are
+// registered from an init() in the backend's own file so adding a new
+// provider never requires touching this file.
+type BackendFactory func(ctx context.Context, cfg Config) (Backend, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]BackendFactory{}
+)
+
+// Register adds a BackendFactory under name (e.g. "gcs", "s3"). It panics on
+// a duplicate registration, mirroring database/sql.Register.
+func Register(name string, factory BackendFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("blob: Register called twice for backend " + name)
+	}
+	registry[name] = factory
+}
+
+// Open constructs the Backend registered under cfg.Backend.
+func Open(ctx context.Context, cfg Config) (Backend, error) {
+	registryMu.RLock()
+	factory, ok := registry[cfg.Backend]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("blob: no backend registered for BLOB_BACKEND=%q", cfg.Backend)
+	}
+	return factory(ctx, cfg)
+}
+
+// Downloader downloads an object to the local filesystem.
+type Downloader struct {
+	Reader ObjectReader
+}
+
+// NewDownloader constructs a Downloader backed by the provided ObjectReader.
+func NewDownloader(r ObjectReader) *Downloader {
+	return &Downloader{Reader: r}
+}
+
+// Download copies the object at bucket/objectPath to destPath, creating any
+// parent directories required.
+func (d *Downloader) Download(ctx context.Context, bucket, objectPath, destPath string) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(destPath), err)
+	}
+
+	rc, err := d.Reader.NewReader(ctx, bucket, objectPath)
+	if err != nil {
+		return fmt.Errorf("open reader for %s/%s: %w", bucket, objectPath, err)
+	}
+	defer rc.Close()
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("create local file %s: %w", destPath, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, rc); err != nil {
+		return fmt.Errorf("copy %s/%s to %s: %w", bucket, objectPath, destPath, err)
+	}
+	return nil
+}
+
+// Uploader uploads local files to a Backend.
+type Uploader struct {
+	Writer ObjectWriter
+	// MaxConcurrency bounds how many files UploadDir uploads at once.
+	// Defaults to 8 when unset.
+	MaxConcurrency int
+}
+
+// NewUploader constructs an Uploader backed by the provided ObjectWriter.
+func NewUploader(w ObjectWriter) *Uploader {
+	return &Uploader{Writer: w}
+}
+
+func (u *Uploader) maxConcurrency() int {
+	if u.MaxConcurrency > 0 {
+		return u.MaxConcurrency
+	}
+	return 8
+}
+
+// UploadFile copies the local file at srcPath to bucket/objectPath.
+func (u *Uploader) UploadFile(ctx context.Context, bucket, objectPath, srcPath string) error {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("open local file %s: %w", srcPath, err)
+	}
+	defer f.Close()
+
+	wc := u.Writer.NewWriter(ctx, bucket, objectPath)
+	if _, err := io.Copy(wc, f); err != nil {
+		_ = wc.Close()
+		return fmt.Errorf("copy %s to %s/%s: %w", srcPath, bucket, objectPath, err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("finalise upload %s/%s: %w", bucket, objectPath, err)
+	}
+	return nil
+}
+
+// UploadDir walks srcDir and uploads every file to bucket/<prefix>/<relPath>.
+// Uploads run concurrently, bounded by MaxConcurrency; the first failure is
+// returned once all in-flight uploads have finished.
+func (u *Uploader) UploadDir(ctx context.Context, bucket, prefix, srcDir string) error {
+	var paths []string
+	if err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	sem := make(chan struct{}, u.maxConcurrency())
+	errCh := make(chan error, len(paths))
+	var wg sync.WaitGroup
+
+	for _, path := range paths {
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return fmt.Errorf("rel path for %s: %w", path, err)
+		}
+		objectPath := prefix + "/" + rel
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(path, objectPath string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := u.UploadFile(ctx, bucket, objectPath, path); err != nil {
+				errCh <- err
+			}
+		}(path, objectPath)
+	}
+
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		return err
+	}
+	return nil
+}
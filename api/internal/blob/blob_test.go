@@ -0,0 +1,181 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/internal/blob"
+)
+
+// memBackend is an in-memory Backend fake for exercising Downloader/Uploader
+// without a real cloud provider.
+type memBackend struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemBackend() *memBackend { return &memBackend{objects: map[string][]byte{}} }
+
+func (m *memBackend) key(bucket, object string) string { return bucket + "/" + object }
+
+func (m *memBackend) NewReader(_ context.Context, bucket, object string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[m.key(bucket, object)]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memBackend) NewWriter(_ context.Context, bucket, object string) io.WriteCloser {
+	return &memWriter{backend: m, key: m.key(bucket, object)}
+}
+
+type memWriter struct {
+	backend *memBackend
+	key     string
+	buf     bytes.Buffer
+}
+
+func (w *memWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *memWriter) Close() error {
+	w.backend.mu.Lock()
+	defer w.backend.mu.Unlock()
+	w.backend.objects[w.key] = append([]byte(nil), w.buf.Bytes()...)
+	return nil
+}
+
+func (m *memBackend) Exists(_ context.Context, bucket, object string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.objects[m.key(bucket, object)]
+	return ok, nil
+}
+
+func (m *memBackend) Delete(_ context.Context, bucket, object string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, m.key(bucket, object))
+	return nil
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Register to panic on duplicate name")
+		}
+	}()
+	blob.Register("dup-backend-test", func(context.Context, blob.Config) (blob.Backend, error) { return nil, nil })
+	blob.Register("dup-backend-test", func(context.Context, blob.Config) (blob.Backend, error) { return nil, nil })
+}
+
+func TestOpen_UnknownBackend_ReturnsError(t *testing.T) {
+	_, err := blob.Open(context.Background(), blob.Config{Backend: "does-not-exist"})
+	if err == nil {
+		t.Fatal("expected error for unregistered backend")
+	}
+}
+
+func TestDownloader_Download_WritesLocalFile(t *testing.T) {
+	backend := newMemBackend()
+	backend.objects[backend.key("bucket", "videos/a.txt")] = []byte("hello")
+
+	dl := blob.NewDownloader(backend)
+	dest := filepath.Join(t.TempDir(), "nested", "a.txt")
+
+	if err := dl.Download(context.Background(), "bucket", "videos/a.txt", dest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read downloaded file: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("downloaded content = %q, want %q", got, "hello")
+	}
+}
+
+func TestUploader_UploadFile_StoresObject(t *testing.T) {
+	backend := newMemBackend()
+	src := filepath.Join(t.TempDir(), "seg.ts")
+	if err := os.WriteFile(src, []byte("segment"), 0o644); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+
+	ul := blob.NewUploader(backend)
+	if err := ul.UploadFile(context.Background(), "bucket", "videos/id/seg.ts", src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := backend.objects[backend.key("bucket", "videos/id/seg.ts")]; string(got) != "segment" {
+		t.Errorf("stored object = %q, want %q", got, "segment")
+	}
+}
+
+func TestMemBackend_Exists_TrueAfterWrite(t *testing.T) {
+	backend := newMemBackend()
+	backend.objects[backend.key("bucket", "videos/id/.checkpoints/download")] = []byte{}
+
+	ok, err := backend.Exists(context.Background(), "bucket", "videos/id/.checkpoints/download")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("expected Exists to report true for a stored object")
+	}
+}
+
+func TestMemBackend_Exists_FalseWhenAbsent(t *testing.T) {
+	backend := newMemBackend()
+
+	ok, err := backend.Exists(context.Background(), "bucket", "videos/id/.checkpoints/download")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("expected Exists to report false for a never-written object")
+	}
+}
+
+func TestMemBackend_Delete_RemovesObject(t *testing.T) {
+	backend := newMemBackend()
+	key := backend.key("bucket", "videos/id/.checkpoints/download")
+	backend.objects[key] = []byte{}
+
+	if err := backend.Delete(context.Background(), "bucket", "videos/id/.checkpoints/download"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := backend.objects[key]; ok {
+		t.Error("expected object to be removed after Delete")
+	}
+}
+
+func TestUploader_UploadDir_UploadsAllFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.m3u8"), []byte("#EXTM3U"), 0o644); err != nil {
+		t.Fatalf("write index.m3u8: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "0.ts"), []byte("seg0"), 0o644); err != nil {
+		t.Fatalf("write 0.ts: %v", err)
+	}
+
+	backend := newMemBackend()
+	ul := blob.NewUploader(backend)
+
+	if err := ul.UploadDir(context.Background(), "bucket", "videos/id", dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"videos/id/index.m3u8", "videos/id/0.ts"} {
+		if _, ok := backend.objects[backend.key("bucket", want)]; !ok {
+			t.Errorf("expected object %q to have been uploaded", want)
+		}
+	}
+}
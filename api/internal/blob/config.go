@@ -0,0 +1,51 @@
+package blob
+
+import "os"
+
+// Config selects and configures the active Backend.
+type Config struct {
+	// Backend is BLOB_BACKEND: "gcs" (default), "s3", or "s3compat" for a
+	// generic S3-compatible endpoint (MinIO, Backblaze B2, DO Spaces).
+	Backend string
+
+	// S3Endpoint overrides the default AWS endpoint, e.g.
+	// "https://s3.us-west-002.backblazeb2.com" or a MinIO server URL. Unused
+	// when Backend is "gcs".
+	S3Endpoint string
+	// S3Region is the signing region (AWS_REGION for real S3; most
+	// S3-compatible servers accept an arbitrary non-empty value).
+	S3Region string
+	// S3AccessKeyID and S3SecretAccessKey are static credentials. When both
+	// are empty, the AWS SDK's default credential chain is used instead.
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// S3ForcePathStyle addresses buckets as "<endpoint>/<bucket>" rather than
+	// "<bucket>.<endpoint>", required by most S3-compatible servers.
+	S3ForcePathStyle bool
+}
+
+// ConfigFromEnv reads Config from the environment:
+//
+//	BLOB_BACKEND            — "gcs" (default), "s3", or "s3compat"
+//	S3_ENDPOINT             — custom S3 endpoint (s3/s3compat only)
+//	S3_REGION               — signing region
+//	S3_ACCESS_KEY_ID        — static access key
+//	S3_SECRET_ACCESS_KEY    — static secret key
+//	S3_FORCE_PATH_STYLE     — "true" to force path-style bucket addressing
+func ConfigFromEnv() Config {
+	return Config{
+		Backend:           envOr("BLOB_BACKEND", "gcs"),
+		S3Endpoint:        os.Getenv("S3_ENDPOINT"),
+		S3Region:          envOr("S3_REGION", "us-east-1"),
+		S3AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		S3ForcePathStyle:  os.Getenv("S3_FORCE_PATH_STYLE") == "true",
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,68 @@
+package blob_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/internal/blob"
+)
+
+func clearBlobEnv(t *testing.T) {
+	t.Helper()
+	for _, k := range []string{
+		"BLOB_BACKEND", "S3_ENDPOINT", "S3_REGION",
+		"S3_ACCESS_KEY_ID", "S3_SECRET_ACCESS_KEY", "S3_FORCE_PATH_STYLE",
+	} {
+		old, had := os.LookupEnv(k)
+		os.Unsetenv(k)
+		t.Cleanup(func() {
+			if had {
+				os.Setenv(k, old)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv_DefaultsToGCS(t *testing.T) {
+	clearBlobEnv(t)
+
+	cfg := blob.ConfigFromEnv()
+	if cfg.Backend != "gcs" {
+		t.Errorf("Backend = %q, want %q", cfg.Backend, "gcs")
+	}
+}
+
+func TestConfigFromEnv_ReadsS3Settings(t *testing.T) {
+	clearBlobEnv(t)
+	os.Setenv("BLOB_BACKEND", "s3compat")
+	os.Setenv("S3_ENDPOINT", "http://localhost:9000")
+	os.Setenv("S3_REGION", "us-west-002")
+	os.Setenv("S3_ACCESS_KEY_ID", "key")
+	os.Setenv("S3_SECRET_ACCESS_KEY", "secret")
+	os.Setenv("S3_FORCE_PATH_STYLE", "true")
+
+	cfg := blob.ConfigFromEnv()
+	if cfg.Backend != "s3compat" {
+		t.Errorf("Backend = %q, want %q", cfg.Backend, "s3compat")
+	}
+	if cfg.S3Endpoint != "http://localhost:9000" {
+		t.Errorf("S3Endpoint = %q", cfg.S3Endpoint)
+	}
+	if cfg.S3Region != "us-west-002" {
+		t.Errorf("S3Region = %q", cfg.S3Region)
+	}
+	if cfg.S3AccessKeyID != "key" || cfg.S3SecretAccessKey != "secret" {
+		t.Errorf("credentials not read correctly: %+v", cfg)
+	}
+	if !cfg.S3ForcePathStyle {
+		t.Error("expected S3ForcePathStyle = true")
+	}
+}
+
+func TestConfigFromEnv_DefaultsRegion(t *testing.T) {
+	clearBlobEnv(t)
+	cfg := blob.ConfigFromEnv()
+	if cfg.S3Region != "us-east-1" {
+		t.Errorf("S3Region = %q, want default %q", cfg.S3Region, "us-east-1")
+	}
+}
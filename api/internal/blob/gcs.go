@@ -0,0 +1,61 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+)
+
+// gcsBackend implements Backend on top of cloud.google.com/go/storage.
+type gcsBackend struct {
+	client *storage.Client
+}
+
+func init() {
+	Register("gcs", func(ctx context.Context, _ Config) (Backend, error) {
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("create GCS client: %w", err)
+		}
+		return &gcsBackend{client: client}, nil
+	})
+}
+
+// NewReader opens a GCS object reader for bucket/object.
+func (b *gcsBackend) NewReader(ctx context.Context, bucket, object string) (io.ReadCloser, error) {
+	r, err := b.client.Bucket(bucket).Object(object).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gcs new reader gs://%s/%s: %w", bucket, object, err)
+	}
+	return r, nil
+}
+
+// NewWriter opens a GCS object writer for bucket/object. The caller must
+// Close it to finalise the upload.
+func (b *gcsBackend) NewWriter(ctx context.Context, bucket, object string) io.WriteCloser {
+	return b.client.Bucket(bucket).Object(object).NewWriter(ctx)
+}
+
+// Exists reports whether bucket/object exists, via a GCS Attrs (HEAD) call.
+func (b *gcsBackend) Exists(ctx context.Context, bucket, object string) (bool, error) {
+	_, err := b.client.Bucket(bucket).Object(object).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("gcs attrs gs://%s/%s: %w", bucket, object, err)
+	}
+	return true, nil
+}
+
+// Delete removes bucket/object, treating an already-absent object as success.
+func (b *gcsBackend) Delete(ctx context.Context, bucket, object string) error {
+	err := b.client.Bucket(bucket).Object(object).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("gcs delete gs://%s/%s: %w", bucket, object, err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3Backend implements Backend on top of AWS S3 or any S3-compatible
+// endpoint (MinIO, Backblaze B2, DigitalOcean Spaces), selected via
+// Config.S3Endpoint/S3ForcePathStyle.
+type s3Backend struct {
+	client *s3.Client
+}
+
+func init() {
+	factory := func(ctx context.Context, cfg Config) (Backend, error) {
+		client, err := newS3Client(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &s3Backend{client: client}, nil
+	}
+	Register("s3", factory)
+	// "s3compat" is the same client construction; it exists as a distinct
+	// BLOB_BACKEND value so MinIO/B2/Spaces deployments can be selected
+	// explicitly instead of implying real AWS S3.
+	Register("s3compat", factory)
+}
+
+func newS3Client(ctx context.Context, cfg Config) (*s3.Client, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	opts = append(opts, awsconfig.WithRegion(cfg.S3Region))
+	if cfg.S3AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.S3AccessKeyID, cfg.S3SecretAccessKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+
+	return s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.S3Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.S3Endpoint)
+		}
+		o.UsePathStyle = cfg.S3ForcePathStyle
+	}), nil
+}
+
+// NewReader opens an S3 object reader for bucket/object.
+func (b *s3Backend) NewReader(ctx context.Context, bucket, object string) (io.ReadCloser, error) {
+	out, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 get object s3://%s/%s: %w", bucket, object, err)
+	}
+	return out.Body, nil
+}
+
+// Exists reports whether bucket/object exists, via an S3 HeadObject call.
+func (b *s3Backend) Exists(ctx context.Context, bucket, object string) (bool, error) {
+	_, err := b.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("s3 head object s3://%s/%s: %w", bucket, object, err)
+	}
+	return true, nil
+}
+
+// Delete removes bucket/object. S3's DeleteObject already treats a missing
+// key as success, so no NotFound handling is needed here.
+func (b *s3Backend) Delete(ctx context.Context, bucket, object string) error {
+	_, err := b.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 delete object s3://%s/%s: %w", bucket, object, err)
+	}
+	return nil
+}
+
+// NewWriter opens an S3 object writer for bucket/object. Because the S3
+// PutObject API has no streaming-write variant, the write is buffered to a
+// temp file and the PutObject call happens on Close.
+func (b *s3Backend) NewWriter(ctx context.Context, bucket, object string) io.WriteCloser {
+	return &s3Writer{ctx: ctx, client: b.client, bucket: bucket, object: object}
+}
+
+type s3Writer struct {
+	ctx     context.Context
+	client  *s3.Client
+	bucket  string
+	object  string
+	tmp     *os.File
+	openErr error
+}
+
+func (w *s3Writer) ensureTmp() error {
+	if w.tmp != nil || w.openErr != nil {
+		return w.openErr
+	}
+	f, err := os.CreateTemp("", "blob-s3-upload-*")
+	if err != nil {
+		w.openErr = fmt.Errorf("create temp upload buffer: %w", err)
+		return w.openErr
+	}
+	w.tmp = f
+	return nil
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	if err := w.ensureTmp(); err != nil {
+		return 0, err
+	}
+	return w.tmp.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	if w.openErr != nil {
+		return w.openErr
+	}
+	if w.tmp == nil {
+		_, err := w.client.PutObject(w.ctx, &s3.PutObjectInput{
+			Bucket: aws.String(w.bucket),
+			Key:    aws.String(w.object),
+		})
+		return err
+	}
+	defer os.Remove(w.tmp.Name())
+	defer w.tmp.Close()
+
+	if _, err := w.tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind upload buffer: %w", err)
+	}
+	_, err := w.client.PutObject(w.ctx, &s3.PutObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(w.object),
+		Body:   w.tmp,
+	})
+	if err != nil {
+		return fmt.Errorf("s3 put object s3://%s/%s: %w", w.bucket, w.object, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Package events defines the transcode lifecycle event emitted by the
+// transcoder pipeline and consumed by the API service's SSE endpoint.
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// Stage identifies a pipeline milestone within a single transcode run.
+type Stage string
+
+const (
+	StageDownloadStarted    Stage = "download_started"
+	StageDownloadComplete   Stage = "download_complete"
+	StageRenditionProgress  Stage = "rendition_progress"
+	StageRenditionComplete  Stage = "rendition_complete"
+	StageThumbnailExtracted Stage = "thumbnail_extracted"
+	StageUploadComplete     Stage = "upload_complete"
+	StageReady              Stage = "ready"
+	StageFailed             Stage = "failed"
+	// StageRejected is published when the raw upload fails pre-flight probe
+	// validation (see internal/probe) and is never handed to ffmpeg.
+	StageRejected Stage = "rejected"
+)
+
+// Event describes one pipeline milestone for a single video.
+type Event struct {
+	VideoID string `json:"video_id"`
+	Stage   Stage  `json:"stage"`
+	// Timestamp is set by the caller; callers running inside transcode()
+	// should use time.Now() at the point of emission.
+	Timestamp time.Time `json:"timestamp"`
+	// Progress is a coarse 0.0-1.0 completion estimate for the overall job.
+	// It is left at zero for events that have no meaningful notion of
+	// progress (e.g. StageFailed).
+	Progress float64 `json:"progress"`
+	// Rendition names the HLS variant this event concerns. Populated for
+	// StageRenditionProgress and StageRenditionComplete.
+	Rendition string `json:"rendition,omitempty"`
+	// Error holds a human-readable failure reason. Only populated for
+	// StageFailed and StageRejected.
+	Error string `json:"error,omitempty"`
+	// OutTimeSeconds is how far into Rendition's encode FFmpeg has
+	// progressed, in seconds. Only populated for StageRenditionProgress.
+	OutTimeSeconds float64 `json:"out_time_seconds,omitempty"`
+	// ManifestType tells the player which manifest format(s) are available,
+	// e.g. "hls+dash", so iOS/Safari clients can keep using native HLS while
+	// others prefer DASH. Only populated for StageReady.
+	ManifestType string `json:"manifest_type,omitempty"`
+}
+
+// Publisher publishes lifecycle events. Implementations must be safe for
+// concurrent use, since per-rendition events may be published from multiple
+// worker-pool goroutines at once.
+type Publisher interface {
+	Publish(ctx context.Context, evt Event) error
+}
+
+// NoopPublisher discards every event. It is used when no events topic is
+// configured, so callers can unconditionally publish without a nil check.
+type NoopPublisher struct{}
+
+// Publish implements Publisher by doing nothing.
+func (NoopPublisher) Publish(context.Context, Event) error { return nil }
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// FakePublisher is an in-memory Publisher for tests. It records every
+// published event in order and is safe for concurrent use, since
+// transcode() may publish per-rendition events from multiple worker-pool
+// goroutines at once.
+type FakePublisher struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// Publish appends evt to the recorded list.
+func (f *FakePublisher) Publish(_ context.Context, evt Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, evt)
+	return nil
+}
+
+// Events returns a copy of every event published so far, in publish order.
+func (f *FakePublisher) Events() []Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]Event, len(f.events))
+	copy(out, f.events)
+	return out
+}
+
+// Stages returns the Stage of every recorded event, in publish order, which
+// is the shape most tests assert against.
+func (f *FakePublisher) Stages() []Stage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]Stage, len(f.events))
+	for i, evt := range f.events {
+		out[i] = evt.Stage
+	}
+	return out
+}
+
+// FakeSubscriber is an in-memory Subscriber for tests. It delivers a fixed
+// list of events to onEvent, in order, then returns nil.
+type FakeSubscriber struct {
+	Events []Event
+}
+
+// Subscribe delivers each of s.Events to onEvent and returns nil, or returns
+// ctx.Err() immediately if ctx is already done.
+func (s *FakeSubscriber) Subscribe(ctx context.Context, onEvent func(Event)) error {
+	for _, evt := range s.Events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		onEvent(evt)
+	}
+	return nil
+}
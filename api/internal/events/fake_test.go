@@ -0,0 +1,35 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/internal/events"
+)
+
+func TestFakePublisher_RecordsEventsInOrder(t *testing.T) {
+	pub := &events.FakePublisher{}
+	ctx := context.Background()
+
+	_ = pub.Publish(ctx, events.Event{VideoID: "v1", Stage: events.StageDownloadStarted})
+	_ = pub.Publish(ctx, events.Event{VideoID: "v1", Stage: events.StageDownloadComplete})
+	_ = pub.Publish(ctx, events.Event{VideoID: "v1", Stage: events.StageReady})
+
+	got := pub.Stages()
+	want := []events.Stage{events.StageDownloadStarted, events.StageDownloadComplete, events.StageReady}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stage[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNoopPublisher_DiscardsEvents(t *testing.T) {
+	var pub events.NoopPublisher
+	if err := pub.Publish(context.Background(), events.Event{VideoID: "v1", Stage: events.StageReady}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// PubSubPublisher publishes events to a Google Cloud Pub/Sub topic, JSON-encoded
+// and keyed for downstream filtering by the VideoID attribute.
+type PubSubPublisher struct {
+	topic *pubsub.Topic
+}
+
+// NewPubSubPublisher constructs a PubSubPublisher for the given project and
+// topic ID. The topic must already exist.
+func NewPubSubPublisher(ctx context.Context, projectID, topicID string) (*PubSubPublisher, error) {
+	client, err := pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("create pubsub client: %w", err)
+	}
+	return &PubSubPublisher{topic: client.Topic(topicID)}, nil
+}
+
+// Publish JSON-encodes evt and publishes it to the topic, blocking until the
+// publish completes or ctx is done.
+func (p *PubSubPublisher) Publish(ctx context.Context, evt Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	result := p.topic.Publish(ctx, &pubsub.Message{
+		Data: data,
+		Attributes: map[string]string{
+			"video_id": evt.VideoID,
+			"stage":    string(evt.Stage),
+		},
+	})
+	if _, err := result.Get(ctx); err != nil {
+		return fmt.Errorf("publish event for video %s: %w", evt.VideoID, err)
+	}
+	return nil
+}
+
+// NewPublisherFromEnv builds a Publisher from EVENTS_TOPIC and GCP_PROJECT_ID.
+// When EVENTS_TOPIC is unset, it returns a NoopPublisher so callers can
+// publish unconditionally without checking whether events are configured.
+func NewPublisherFromEnv(ctx context.Context) (Publisher, error) {
+	topicID := os.Getenv("EVENTS_TOPIC")
+	if topicID == "" {
+		return NoopPublisher{}, nil
+	}
+	projectID := os.Getenv("GCP_PROJECT_ID")
+	if projectID == "" {
+		return nil, fmt.Errorf("EVENTS_TOPIC is set but GCP_PROJECT_ID is not")
+	}
+	return NewPubSubPublisher(ctx, projectID, topicID)
+}
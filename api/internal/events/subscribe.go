@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// Subscriber delivers every event received on a subscription to onEvent,
+// blocking until ctx is cancelled or delivery fails unrecoverably.
+// Implementations do not filter by VideoID — callers filter in onEvent.
+type Subscriber interface {
+	Subscribe(ctx context.Context, onEvent func(Event)) error
+}
+
+// PubSubSubscriber delivers events from a Google Cloud Pub/Sub subscription.
+type PubSubSubscriber struct {
+	sub *pubsub.Subscription
+}
+
+// NewPubSubSubscriber constructs a PubSubSubscriber for the given project and
+// subscription ID. The subscription must already exist and be attached to
+// the topic events are published to.
+func NewPubSubSubscriber(ctx context.Context, projectID, subscriptionID string) (*PubSubSubscriber, error) {
+	client, err := pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("create pubsub client: %w", err)
+	}
+	return &PubSubSubscriber{sub: client.Subscription(subscriptionID)}, nil
+}
+
+// Subscribe calls onEvent for every message received until ctx is cancelled.
+// Messages that fail to unmarshal are acked and skipped rather than
+// redelivered forever.
+func (s *PubSubSubscriber) Subscribe(ctx context.Context, onEvent func(Event)) error {
+	return s.sub.Receive(ctx, func(_ context.Context, m *pubsub.Message) {
+		var evt Event
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			m.Ack()
+			return
+		}
+		onEvent(evt)
+		m.Ack()
+	})
+}
+
+// NewSubscriberFromEnv builds a Subscriber from EVENTS_SUBSCRIPTION and
+// GCP_PROJECT_ID. Returns an error if EVENTS_SUBSCRIPTION is unset, since
+// callers of this constructor (the SSE endpoint) have no meaningful
+// no-op behaviour the way a Publisher does.
+func NewSubscriberFromEnv(ctx context.Context) (Subscriber, error) {
+	subID := os.Getenv("EVENTS_SUBSCRIPTION")
+	if subID == "" {
+		return nil, fmt.Errorf("EVENTS_SUBSCRIPTION env var is not set")
+	}
+	projectID := os.Getenv("GCP_PROJECT_ID")
+	if projectID == "" {
+		return nil, fmt.Errorf("EVENTS_SUBSCRIPTION is set but GCP_PROJECT_ID is not")
+	}
+	return NewPubSubSubscriber(ctx, projectID, subID)
+}
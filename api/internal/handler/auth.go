@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ai-teammate/mytube/api/internal/auth"
+	"github.com/ai-teammate/mytube/api/internal/middleware"
+	"github.com/ai-teammate/mytube/api/internal/session"
+)
+
+// SessionManager is the data-access interface used by the /api/auth/*
+// endpoints. Satisfied by *session.Manager and allows tests to inject a stub.
+type SessionManager interface {
+	Exchange(ctx context.Context, userID, provider, email string, r *http.Request) (*session.IssuedTokens, error)
+	Refresh(ctx context.Context, refreshToken string, r *http.Request) (*session.IssuedTokens, error)
+	Logout(ctx context.Context, sessionID string) error
+	ListSessions(ctx context.Context, userID string) ([]*session.Session, error)
+}
+
+// TokensResponse is the JSON body returned by /api/auth/exchange and
+// /api/auth/refresh.
+type TokensResponse struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// SessionResponse is one entry in the GET /api/auth/sessions response.
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	Provider  string    `json:"provider"`
+	IssuedAt  time.Time `json:"issued_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+}
+
+// NewExchangeHandler returns an http.HandlerFunc for POST /api/auth/exchange.
+// It verifies the Firebase/OIDC ID token supplied as a bearer token,
+// provisions the user row on first call (same as the historical /api/me
+// path), and issues a session access/refresh token pair for it.
+func NewExchangeHandler(verifier auth.TokenVerifier, users UserProvider, sessions SessionManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		idToken, ok := bearerToken(r)
+		if !ok {
+			http.Error(w, `{"error":"missing or malformed Authorization header"}`, http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := verifier.VerifyIDToken(r.Context(), idToken)
+		if err != nil {
+			http.Error(w, `{"error":"invalid or expired token"}`, http.StatusUnauthorized)
+			return
+		}
+
+		user, err := provisionAndFetch(r.Context(), users, claims)
+		if err != nil || user == nil {
+			log.Printf("POST /api/auth/exchange: provision user %s: %v", claims.UID, err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		tokens, err := sessions.Exchange(r.Context(), user.ID, claims.Provider, claims.Email, r)
+		if err != nil {
+			log.Printf("POST /api/auth/exchange: issue session for user %s: %v", user.ID, err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		writeTokens(w, tokens)
+	}
+}
+
+// refreshRequest is the JSON body expected by POST /api/auth/refresh.
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// NewRefreshHandler returns an http.HandlerFunc for POST /api/auth/refresh.
+// It rotates the refresh token named in the request body and returns a
+// fresh access/refresh token pair. Unlike the other /api/auth/* endpoints
+// this one is unauthenticated: the refresh token itself is the credential.
+func NewRefreshHandler(sessions SessionManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req refreshRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+			http.Error(w, `{"error":"missing refresh_token"}`, http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := sessions.Refresh(r.Context(), req.RefreshToken, r)
+		if err != nil {
+			http.Error(w, `{"error":"invalid or expired refresh token"}`, http.StatusUnauthorized)
+			return
+		}
+
+		writeTokens(w, tokens)
+	}
+}
+
+// NewLogoutHandler returns an http.HandlerFunc for POST /api/auth/logout.
+// It must run behind middleware.RequireAuthOrSession: logout deletes the
+// caller's own session, identified from the context the middleware set, and
+// there is nothing to delete for a request authenticated via a raw
+// upstream ID token.
+func NewLogoutHandler(sessions SessionManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		sess := middleware.SessionFromContext(r.Context())
+		if sess == nil {
+			http.Error(w, `{"error":"not authenticated via a session token"}`, http.StatusBadRequest)
+			return
+		}
+
+		if err := sessions.Logout(r.Context(), sess.ID); err != nil {
+			log.Printf("POST /api/auth/logout: logout session %s: %v", sess.ID, err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// NewSessionsHandler returns an http.HandlerFunc for GET /api/auth/sessions,
+// listing the caller's active sessions across devices so they can be
+// reviewed. No handler here revokes a listed session by ID; NewLogoutHandler
+// ends only the caller's own. Like NewLogoutHandler it requires a
+// session-authenticated request.
+func NewSessionsHandler(sessions SessionManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		sess := middleware.SessionFromContext(r.Context())
+		if sess == nil {
+			http.Error(w, `{"error":"not authenticated via a session token"}`, http.StatusBadRequest)
+			return
+		}
+
+		list, err := sessions.ListSessions(r.Context(), sess.UserID)
+		if err != nil {
+			log.Printf("GET /api/auth/sessions: list sessions for user %s: %v", sess.UserID, err)
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+
+		out := make([]SessionResponse, len(list))
+		for i, s := range list {
+			out[i] = SessionResponse{
+				ID:        s.ID,
+				Provider:  s.Provider,
+				IssuedAt:  s.IssuedAt,
+				LastSeen:  s.LastSeen,
+				UserAgent: s.UserAgent,
+				IP:        s.IP,
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out)
+	}
+}
+
+// writeTokens writes t as a TokensResponse JSON body.
+func writeTokens(w http.ResponseWriter, t *session.IssuedTokens) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(TokensResponse{
+		AccessToken:      t.AccessToken,
+		AccessExpiresAt:  t.AccessExpiresAt,
+		RefreshToken:     t.RefreshToken,
+		RefreshExpiresAt: t.RefreshExpiresAt,
+	})
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>"
+// header, duplicated from middleware.bearerToken (unexported there) since
+// NewExchangeHandler verifies the ID token itself rather than delegating to
+// RequireAuth.
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if h == "" {
+		return "", false
+	}
+	parts := strings.SplitN(h, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", false
+	}
+	token := strings.TrimSpace(parts[1])
+	if token == "" {
+		return "", false
+	}
+	return token, true
+}
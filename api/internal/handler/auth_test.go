@@ -0,0 +1,253 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/api/internal/auth"
+	"github.com/ai-teammate/mytube/api/internal/handler"
+	"github.com/ai-teammate/mytube/api/internal/repository"
+	"github.com/ai-teammate/mytube/api/internal/session"
+)
+
+// ─── stub SessionManager ──────────────────────────────────────────────────────
+
+type stubSessionManager struct {
+	tokens        *session.IssuedTokens
+	exchangeErr   error
+	refreshErr    error
+	logoutErr     error
+	sessions      []*session.Session
+	listErr       error
+	gotUserID     string
+	gotProvider   string
+	gotRefresh    string
+	gotLogoutID   string
+	gotListUserID string
+}
+
+func (s *stubSessionManager) Exchange(_ context.Context, userID, provider, _ string, _ *http.Request) (*session.IssuedTokens, error) {
+	s.gotUserID, s.gotProvider = userID, provider
+	return s.tokens, s.exchangeErr
+}
+
+func (s *stubSessionManager) Refresh(_ context.Context, refreshToken string, _ *http.Request) (*session.IssuedTokens, error) {
+	s.gotRefresh = refreshToken
+	return s.tokens, s.refreshErr
+}
+
+func (s *stubSessionManager) Logout(_ context.Context, sessionID string) error {
+	s.gotLogoutID = sessionID
+	return s.logoutErr
+}
+
+func (s *stubSessionManager) ListSessions(_ context.Context, userID string) ([]*session.Session, error) {
+	s.gotListUserID = userID
+	return s.sessions, s.listErr
+}
+
+// ─── NewExchangeHandler tests ─────────────────────────────────────────────────
+
+func TestNewExchangeHandler_MissingAuthHeader(t *testing.T) {
+	h := handler.NewExchangeHandler(&stubTokenVerifier{}, &stubUserProvider{}, &stubSessionManager{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/exchange", nil)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestNewExchangeHandler_WrongMethod(t *testing.T) {
+	h := handler.NewExchangeHandler(&stubTokenVerifier{}, &stubUserProvider{}, &stubSessionManager{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/exchange", nil)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
+
+func TestNewExchangeHandler_InvalidIDToken(t *testing.T) {
+	h := handler.NewExchangeHandler(&stubTokenVerifier{err: errors.New("bad token")}, &stubUserProvider{}, &stubSessionManager{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/exchange", nil)
+	req.Header.Set("Authorization", "Bearer bad.token")
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestNewExchangeHandler_Success_ReturnsTokens(t *testing.T) {
+	claims := &auth.TokenClaims{UID: "uid1", Email: "a@b.com", Provider: "firebase"}
+	user := &repository.User{ID: "user-1", Username: "alice"}
+	expected := &session.IssuedTokens{
+		AccessToken:      "mts1.access",
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshToken:     "refresh-token",
+		RefreshExpiresAt: time.Now().Add(30 * 24 * time.Hour),
+	}
+	sessions := &stubSessionManager{tokens: expected}
+
+	h := handler.NewExchangeHandler(&stubTokenVerifier{claims: claims}, &stubUserProvider{user: user}, sessions)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/exchange", nil)
+	req.Header.Set("Authorization", "Bearer valid.id.token")
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if sessions.gotUserID != user.ID {
+		t.Errorf("Exchange called with userID %q, want %q", sessions.gotUserID, user.ID)
+	}
+	if sessions.gotProvider != claims.Provider {
+		t.Errorf("Exchange called with provider %q, want %q", sessions.gotProvider, claims.Provider)
+	}
+
+	var body handler.TokensResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.AccessToken != expected.AccessToken {
+		t.Errorf("AccessToken: got %q, want %q", body.AccessToken, expected.AccessToken)
+	}
+	if body.RefreshToken != expected.RefreshToken {
+		t.Errorf("RefreshToken: got %q, want %q", body.RefreshToken, expected.RefreshToken)
+	}
+}
+
+// ─── NewRefreshHandler tests ──────────────────────────────────────────────────
+
+func TestNewRefreshHandler_MissingBody(t *testing.T) {
+	h := handler.NewRefreshHandler(&stubSessionManager{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", nil)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestNewRefreshHandler_InvalidRefreshToken(t *testing.T) {
+	sessions := &stubSessionManager{refreshErr: errors.New("not found")}
+	h := handler.NewRefreshHandler(sessions)
+
+	body, _ := json.Marshal(map[string]string{"refresh_token": "bogus"})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestNewRefreshHandler_Success(t *testing.T) {
+	expected := &session.IssuedTokens{AccessToken: "mts1.new", RefreshToken: "new-refresh"}
+	sessions := &stubSessionManager{tokens: expected}
+	h := handler.NewRefreshHandler(sessions)
+
+	body, _ := json.Marshal(map[string]string{"refresh_token": "old-refresh"})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if sessions.gotRefresh != "old-refresh" {
+		t.Errorf("Refresh called with %q, want %q", sessions.gotRefresh, "old-refresh")
+	}
+}
+
+// ─── NewLogoutHandler tests ───────────────────────────────────────────────────
+
+func TestNewLogoutHandler_NoSessionInContext(t *testing.T) {
+	h := handler.NewLogoutHandler(&stubSessionManager{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 when request wasn't session-authenticated, got %d", rec.Code)
+	}
+}
+
+func TestNewLogoutHandler_Success(t *testing.T) {
+	sessions := &stubSessionManager{}
+	h := handler.NewLogoutHandler(sessions)
+
+	sess := &session.Session{ID: "sess-1", UserID: "user-1"}
+	req := withSession(httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil), sess)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if sessions.gotLogoutID != sess.ID {
+		t.Errorf("Logout called with %q, want %q", sessions.gotLogoutID, sess.ID)
+	}
+}
+
+// ─── NewSessionsHandler tests ─────────────────────────────────────────────────
+
+func TestNewSessionsHandler_NoSessionInContext(t *testing.T) {
+	h := handler.NewSessionsHandler(&stubSessionManager{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/sessions", nil)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 when request wasn't session-authenticated, got %d", rec.Code)
+	}
+}
+
+func TestNewSessionsHandler_Success_ListsForCallersUserID(t *testing.T) {
+	sessions := &stubSessionManager{
+		sessions: []*session.Session{
+			{ID: "sess-1", Provider: "firebase", UserAgent: "curl"},
+			{ID: "sess-2", Provider: "google", UserAgent: "chrome"},
+		},
+	}
+	h := handler.NewSessionsHandler(sessions)
+
+	sess := &session.Session{ID: "sess-1", UserID: "user-1"}
+	req := withSession(httptest.NewRequest(http.MethodGet, "/api/auth/sessions", nil), sess)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if sessions.gotListUserID != "user-1" {
+		t.Errorf("ListSessions called with %q, want %q", sessions.gotListUserID, "user-1")
+	}
+
+	var body []handler.SessionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if len(body) != 2 {
+		t.Fatalf("expected 2 sessions in response, got %d", len(body))
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ai-teammate/mytube/api/internal/events"
+)
+
+// EventSubscriber delivers transcode lifecycle events. Satisfied by
+// *events.PubSubSubscriber in production and *events.FakeSubscriber in tests.
+type EventSubscriber interface {
+	Subscribe(ctx context.Context, onEvent func(events.Event)) error
+}
+
+// NewEventsHandler returns an http.HandlerFunc for
+// GET /api/videos/{id}/events, which streams Server-Sent Events for the
+// video ID in the path until the client disconnects. Events for other video
+// IDs are filtered out.
+func NewEventsHandler(sub EventSubscriber) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		videoID := videoIDFromEventsPath(r.URL.Path)
+		if videoID == "" {
+			http.Error(w, `{"error":"invalid video id"}`, http.StatusBadRequest)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, `{"error":"streaming unsupported"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		err := sub.Subscribe(r.Context(), func(evt events.Event) {
+			if evt.VideoID != videoID {
+				return
+			}
+			data, err := json.Marshal(evt)
+			if err != nil {
+				log.Printf("GET /api/videos/%s/events: marshal event: %v", videoID, err)
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		})
+		if err != nil && r.Context().Err() == nil {
+			log.Printf("GET /api/videos/%s/events: subscribe: %v", videoID, err)
+		}
+	}
+}
+
+// videoIDFromEventsPath extracts {id} from "/api/videos/{id}/events".
+// Returns "" if the path does not match that shape.
+func videoIDFromEventsPath(path string) string {
+	const prefix = "/api/videos/"
+	const suffix = "/events"
+	if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, suffix) {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(path, prefix), suffix)
+}
@@ -0,0 +1,53 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/internal/events"
+	"github.com/ai-teammate/mytube/api/internal/handler"
+)
+
+func TestNewEventsHandler_StreamsMatchingEventsOnly(t *testing.T) {
+	sub := &events.FakeSubscriber{Events: []events.Event{
+		{VideoID: "v1", Stage: events.StageDownloadStarted},
+		{VideoID: "other-video", Stage: events.StageReady},
+		{VideoID: "v1", Stage: events.StageReady},
+	}}
+	h := handler.NewEventsHandler(sub)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/videos/v1/events", nil)
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	body := rec.Body.String()
+	if strings.Count(body, "data: ") != 2 {
+		t.Errorf("expected 2 SSE frames for v1, got body: %q", body)
+	}
+	if strings.Contains(body, "other-video") {
+		t.Errorf("events for other videos must be filtered out, got body: %q", body)
+	}
+}
+
+func TestNewEventsHandler_InvalidPath_ReturnsBadRequest(t *testing.T) {
+	sub := &events.FakeSubscriber{}
+	h := handler.NewEventsHandler(sub)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/videos//events", nil)
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for missing video id, got %d", rec.Code)
+	}
+}
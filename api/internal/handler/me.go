@@ -15,8 +15,9 @@ import (
 // UserProvider is the data-access interface used by the /api/me handler.
 // Satisfied by *repository.UserRepository and allows tests to inject a stub.
 type UserProvider interface {
-	Upsert(ctx context.Context, firebaseUID, email string) (*repository.User, error)
-	GetByFirebaseUID(ctx context.Context, firebaseUID string) (*repository.User, error)
+	Upsert(ctx context.Context, provider, subject, email string) (*repository.User, error)
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*repository.User, error)
+	GetByID(ctx context.Context, id string) (*repository.User, error)
 }
 
 // MeResponse is the JSON body returned by GET /api/me.
@@ -27,8 +28,9 @@ type MeResponse struct {
 }
 
 // NewMeHandler returns an http.HandlerFunc for GET /api/me.
-// It reads verified claims from the request context (injected by RequireAuth),
-// upserts the user row on first call, and returns the user profile.
+// It reads verified claims from the request context (injected by RequireAuth
+// or RequireAuthOrSession), upserts the user row on first call, and returns
+// the user profile.
 func NewMeHandler(users UserProvider) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims := middleware.ClaimsFromContext(r.Context())
@@ -39,7 +41,16 @@ func NewMeHandler(users UserProvider) http.HandlerFunc {
 			return
 		}
 
-		user, err := provisionAndFetch(r.Context(), users, claims)
+		var user *repository.User
+		var err error
+		if sess := middleware.SessionFromContext(r.Context()); sess != nil {
+			// Session fast path: claims.UID is the internal user ID, not a
+			// provider subject, since the user row was already provisioned
+			// at /api/auth/exchange time.
+			user, err = users.GetByID(r.Context(), sess.UserID)
+		} else {
+			user, err = provisionAndFetch(r.Context(), users, claims)
+		}
 		if err != nil {
 			log.Printf("GET /api/me: provision user %s: %v", claims.UID, err)
 			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
@@ -67,5 +78,5 @@ func NewMeHandler(users UserProvider) http.HandlerFunc {
 // If the upsert is a no-op (conflict) it falls back to a plain SELECT so that
 // subsequent GET /api/me calls after provisioning always succeed.
 func provisionAndFetch(ctx context.Context, users UserProvider, claims *auth.TokenClaims) (*repository.User, error) {
-	return users.Upsert(ctx, claims.UID, claims.Email)
+	return users.Upsert(ctx, claims.Provider, claims.UID, claims.Email)
 }
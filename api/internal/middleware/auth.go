@@ -4,10 +4,12 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/ai-teammate/mytube/api/internal/auth"
+	"github.com/ai-teammate/mytube/api/internal/session"
 )
 
 // contextKey is an unexported type used for context values set by this package
@@ -17,18 +19,35 @@ type contextKey int
 const (
 	// claimsKey is the context key under which *auth.TokenClaims is stored.
 	claimsKey contextKey = iota
+	// sessionKey is the context key under which *session.Session is stored,
+	// set only on the RequireAuthOrSession fast path.
+	sessionKey
+	// loggerKey is the context key under which the request-scoped
+	// *slog.Logger set by RequestLogger is stored.
+	loggerKey
 )
 
 // ClaimsFromContext retrieves the verified *auth.TokenClaims injected by
-// RequireAuth.  Returns nil if the middleware was not applied or the token was
-// not verified.
+// RequireAuth or RequireAuthOrSession.  Returns nil if neither middleware was
+// applied or the token was not verified.
 func ClaimsFromContext(ctx context.Context) *auth.TokenClaims {
 	v, _ := ctx.Value(claimsKey).(*auth.TokenClaims)
 	return v
 }
 
-// RequireAuth returns a middleware that validates the Firebase ID token supplied
-// in the "Authorization: Bearer <token>" header.
+// SessionFromContext retrieves the *session.Session injected by
+// RequireAuthOrSession when the request authenticated via the session
+// fast path (as opposed to an upstream ID token). Returns nil otherwise —
+// handlers that need to distinguish the two (e.g. logout, which has nothing
+// to revoke for an ID-token-authenticated request) should check this first.
+func SessionFromContext(ctx context.Context) *session.Session {
+	v, _ := ctx.Value(sessionKey).(*session.Session)
+	return v
+}
+
+// RequireAuth returns a middleware that validates the ID token supplied in the
+// "Authorization: Bearer <token>" header using verifier (typically an
+// *auth.MultiVerifier routing across the enabled identity providers).
 //
 // On success the verified *auth.TokenClaims are stored in the request context
 // (retrieve with ClaimsFromContext) and the next handler is called.
@@ -51,7 +70,81 @@ func RequireAuth(verifier auth.TokenVerifier) func(http.Handler) http.Handler {
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), claimsKey, claims)
+			ctx := withClaimsLogger(r.Context(), claims)
+			ctx = context.WithValue(ctx, claimsKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// withClaimsLogger returns ctx with LoggerFromContext(ctx) enriched with
+// claims' uid and email, so request logs emitted after authentication
+// correlate with the authenticated user. A no-op if RequestLogger wasn't
+// applied upstream, since LoggerFromContext falls back to slog.Default().
+func withClaimsLogger(ctx context.Context, claims *auth.TokenClaims) context.Context {
+	logger := LoggerFromContext(ctx).With(
+		slog.String("uid", claims.UID),
+		slog.String("email", claims.Email),
+	)
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// SessionAuthenticator validates a session access token (see the session
+// package) and returns the Session it names. Satisfied by *session.Manager;
+// the interface exists so tests can inject a stub.
+type SessionAuthenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (*session.Session, error)
+}
+
+// RequireAuthOrSession is like RequireAuth but tries the session access-token
+// fast path first: if the bearer token looks like one (see
+// session.IsAccessToken), it's verified via sessions (HMAC + optional cache,
+// no Postgres round trip) instead of the slower upstream ID-token path.
+//
+// On success both ClaimsFromContext and SessionFromContext are populated —
+// claims are synthesized from the session so handlers written against
+// ClaimsFromContext (e.g. the historical /api/me path) keep working
+// unmodified. Handlers that need the live Session (e.g. logout) should use
+// SessionFromContext instead.
+//
+// Tokens that don't look like a session access token fall back to today's
+// behavior: verifier.VerifyIDToken against the upstream Firebase/OIDC token.
+func RequireAuthOrSession(verifier auth.TokenVerifier, sessions SessionAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				writeUnauthorized(w, "missing or malformed Authorization header")
+				return
+			}
+
+			if session.IsAccessToken(token) {
+				sess, err := sessions.Authenticate(r.Context(), token)
+				if err != nil {
+					writeUnauthorized(w, "invalid or expired session")
+					return
+				}
+
+				sessClaims := &auth.TokenClaims{
+					UID:      sess.UserID,
+					Email:    sess.Email,
+					Provider: sess.Provider,
+				}
+				ctx := withClaimsLogger(r.Context(), sessClaims)
+				ctx = context.WithValue(ctx, sessionKey, sess)
+				ctx = context.WithValue(ctx, claimsKey, sessClaims)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			claims, err := verifier.VerifyIDToken(r.Context(), token)
+			if err != nil {
+				writeUnauthorized(w, "invalid or expired token")
+				return
+			}
+
+			ctx := withClaimsLogger(r.Context(), claims)
+			ctx = context.WithValue(ctx, claimsKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
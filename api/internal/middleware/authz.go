@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ai-teammate/mytube/api/internal/auth"
+)
+
+// RequireClaims returns a middleware that must run after RequireAuth or
+// RequireAuthOrSession — it reads the *auth.TokenClaims those middlewares
+// place in context via ClaimsFromContext, rather than verifying a token
+// itself. Requests whose claims don't satisfy pred (or that have no claims
+// in context at all, e.g. RequireClaims applied without an auth middleware
+// upstream) are rejected with 403 Forbidden and reason as the JSON error
+// message.
+func RequireClaims(pred func(*auth.TokenClaims) bool, reason string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := ClaimsFromContext(r.Context())
+			if claims == nil || !pred(claims) {
+				writeForbidden(w, reason)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireEmailVerified rejects requests whose authenticated claims don't have
+// EmailVerified set.
+func RequireEmailVerified() func(http.Handler) http.Handler {
+	return RequireClaims(func(c *auth.TokenClaims) bool {
+		return c.EmailVerified
+	}, "email not verified")
+}
+
+// RequireRole rejects requests whose authenticated claims don't include role
+// among TokenClaims.Roles.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return RequireClaims(func(c *auth.TokenClaims) bool {
+		return c.HasRole(role)
+	}, "missing required role: "+role)
+}
+
+// RequireAnyRole rejects requests whose authenticated claims don't include at
+// least one of roles among TokenClaims.Roles.
+func RequireAnyRole(roles ...string) func(http.Handler) http.Handler {
+	return RequireClaims(func(c *auth.TokenClaims) bool {
+		for _, role := range roles {
+			if c.HasRole(role) {
+				return true
+			}
+		}
+		return false
+	}, "missing required role")
+}
+
+// writeForbidden writes a 403 JSON error response, the same shape as
+// writeUnauthorized's 401.
+func writeForbidden(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,169 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/internal/auth"
+	"github.com/ai-teammate/mytube/api/internal/middleware"
+)
+
+// withClaims builds a request carrying claims as RequireAuth would have
+// placed them in context.
+func withClaims(req *http.Request, claims *auth.TokenClaims) *http.Request {
+	v := &stubVerifier{claims: claims}
+	var out *http.Request
+	h := middleware.RequireAuth(v)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		out = r
+	}))
+	req.Header.Set("Authorization", "Bearer token")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return out
+}
+
+func TestRequireClaims_PredicateTrue_CallsNext(t *testing.T) {
+	req := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &auth.TokenClaims{UID: "u1"})
+
+	called := false
+	h := middleware.RequireClaims(func(*auth.TokenClaims) bool { return true }, "nope")(nextHandlerCalled(&called))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+	if !called {
+		t.Error("expected next handler to be called")
+	}
+}
+
+func TestRequireClaims_PredicateFalse_Returns403(t *testing.T) {
+	req := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &auth.TokenClaims{UID: "u1"})
+
+	called := false
+	h := middleware.RequireClaims(func(*auth.TokenClaims) bool { return false }, "not allowed")(nextHandlerCalled(&called))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+	if called {
+		t.Error("next handler must not be called on predicate failure")
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("could not decode 403 body as JSON: %v", err)
+	}
+	if body["error"] != "not allowed" {
+		t.Errorf("error message: got %q, want %q", body["error"], "not allowed")
+	}
+}
+
+func TestRequireClaims_NoClaimsInContext_Returns403(t *testing.T) {
+	called := false
+	h := middleware.RequireClaims(func(*auth.TokenClaims) bool { return true }, "no auth")(nextHandlerCalled(&called))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 when claims are absent, got %d", rec.Code)
+	}
+	if called {
+		t.Error("next handler must not be called without claims in context")
+	}
+}
+
+func TestRequireEmailVerified_Verified_CallsNext(t *testing.T) {
+	req := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &auth.TokenClaims{UID: "u1", EmailVerified: true})
+
+	called := false
+	h := middleware.RequireEmailVerified()(nextHandlerCalled(&called))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if !called {
+		t.Error("expected next handler to be called when email is verified")
+	}
+}
+
+func TestRequireEmailVerified_Unverified_Returns403(t *testing.T) {
+	req := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &auth.TokenClaims{UID: "u1", EmailVerified: false})
+
+	called := false
+	h := middleware.RequireEmailVerified()(nextHandlerCalled(&called))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+	if called {
+		t.Error("next handler must not be called when email is unverified")
+	}
+}
+
+func TestRequireRole_HasRole_CallsNext(t *testing.T) {
+	req := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &auth.TokenClaims{UID: "u1", Roles: []string{"admin"}})
+
+	called := false
+	h := middleware.RequireRole("admin")(nextHandlerCalled(&called))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if !called {
+		t.Error("expected next handler to be called for a matching role")
+	}
+}
+
+func TestRequireRole_MissingRole_Returns403(t *testing.T) {
+	req := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &auth.TokenClaims{UID: "u1", Roles: []string{"viewer"}})
+
+	called := false
+	h := middleware.RequireRole("admin")(nextHandlerCalled(&called))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+	if called {
+		t.Error("next handler must not be called without the required role")
+	}
+}
+
+func TestRequireAnyRole_MatchesOneOfSeveral_CallsNext(t *testing.T) {
+	req := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &auth.TokenClaims{UID: "u1", Roles: []string{"editor"}})
+
+	called := false
+	h := middleware.RequireAnyRole("admin", "editor")(nextHandlerCalled(&called))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if !called {
+		t.Error("expected next handler to be called when any listed role matches")
+	}
+}
+
+func TestRequireAnyRole_MatchesNone_Returns403(t *testing.T) {
+	req := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &auth.TokenClaims{UID: "u1", Roles: []string{"viewer"}})
+
+	called := false
+	h := middleware.RequireAnyRole("admin", "editor")(nextHandlerCalled(&called))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+	if called {
+		t.Error("next handler must not be called when no listed role matches")
+	}
+}
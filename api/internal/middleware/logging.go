@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LoggerFromContext retrieves the request-scoped *slog.Logger injected by
+// RequestLogger, pre-bound with that request's method/path/trace fields (and,
+// once RequireAuth or RequireAuthOrSession has run, uid/email). Falls back to
+// slog.Default() if RequestLogger wasn't applied, so callers can use it
+// unconditionally.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// RequestLogger returns a middleware that emits one structured JSON log line
+// per request to logger (method, path, status, bytes written, latency, user
+// agent, remote IP, and — when Cloud Run/Eventarc set it — the
+// X-Cloud-Trace-Context trace and span IDs), and injects a logger pre-bound
+// with those same fields, except status, bytes and latency, which are only
+// known afterwards, into the request context so downstream handlers and
+// repositories can call LoggerFromContext(ctx).Info(...) and have their
+// entries auto-correlate with the request in Cloud Logging.
+//
+// A nil logger falls back to slog.Default().
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			reqLogger := logger.With(
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("user_agent", r.UserAgent()),
+				slog.String("remote_ip", remoteIP(r)),
+			)
+			if traceID, spanID, ok := parseCloudTraceContext(r.Header.Get("X-Cloud-Trace-Context")); ok {
+				reqLogger = reqLogger.With(slog.String("trace_id", traceID), slog.String("span_id", spanID))
+			}
+
+			ctx := context.WithValue(r.Context(), loggerKey, reqLogger)
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			reqLogger.Info("request",
+				slog.Int("status", sw.statusOrDefault()),
+				slog.Int("bytes", sw.bytes),
+				slog.Duration("latency", time.Since(start)),
+			)
+		})
+	}
+}
+
+// remoteIP strips the port from r.RemoteAddr, falling back to the raw value
+// if it isn't a host:port pair (e.g. in some test harnesses).
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// parseCloudTraceContext parses the "X-Cloud-Trace-Context: TRACE_ID/SPAN_ID;o=TRACE_TRUE"
+// header Google Cloud load balancers and Cloud Run inject, returning ok=false
+// if the header is absent or malformed. The ";o=..." options suffix is
+// discarded; callers only need the IDs for log correlation.
+func parseCloudTraceContext(header string) (traceID, spanID string, ok bool) {
+	if header == "" {
+		return "", "", false
+	}
+	traceID, rest, found := strings.Cut(header, "/")
+	if traceID == "" {
+		return "", "", false
+	}
+	if !found {
+		return traceID, "", true
+	}
+	spanID, _, _ = strings.Cut(rest, ";")
+	return traceID, spanID, true
+}
+
+// statusWriter wraps http.ResponseWriter to capture the response status code
+// and bytes written for RequestLogger's summary line, while still exposing
+// http.Flusher and http.Hijacker to handlers that need them (e.g. the SSE
+// events handler, which flushes, and any future WebSocket upgrade).
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// statusOrDefault reports the response status, defaulting to 200 if the
+// handler never called WriteHeader explicitly (matching net/http's own
+// default when Write is called first).
+func (w *statusWriter) statusOrDefault() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support http.Hijacker")
+	}
+	return h.Hijack()
+}
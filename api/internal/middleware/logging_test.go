@@ -0,0 +1,167 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/internal/auth"
+	"github.com/ai-teammate/mytube/api/internal/middleware"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, nil))
+}
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	dec := json.NewDecoder(buf)
+	var lines []map[string]any
+	for {
+		var line map[string]any
+		if err := dec.Decode(&line); err != nil {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRequestLogger_EmitsOneLinePerRequest(t *testing.T) {
+	var buf bytes.Buffer
+	h := middleware.RequestLogger(newTestLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/videos", nil)
+	req.RemoteAddr = "203.0.113.5:54321"
+	req.Header.Set("User-Agent", "test-agent/1.0")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("expected exactly 1 log line, got %d", len(lines))
+	}
+
+	line := lines[0]
+	if line["method"] != http.MethodPost {
+		t.Errorf("method: got %v, want POST", line["method"])
+	}
+	if line["path"] != "/api/videos" {
+		t.Errorf("path: got %v, want /api/videos", line["path"])
+	}
+	if line["status"] != float64(http.StatusCreated) {
+		t.Errorf("status: got %v, want 201", line["status"])
+	}
+	if line["user_agent"] != "test-agent/1.0" {
+		t.Errorf("user_agent: got %v", line["user_agent"])
+	}
+	if line["remote_ip"] != "203.0.113.5" {
+		t.Errorf("remote_ip: got %v, want port stripped", line["remote_ip"])
+	}
+	if _, ok := line["latency"]; !ok {
+		t.Error("expected a latency field")
+	}
+}
+
+func TestRequestLogger_DefaultsStatusToOKWhenWriteHeaderNotCalled(t *testing.T) {
+	var buf bytes.Buffer
+	h := middleware.RequestLogger(newTestLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	lines := decodeLogLines(t, &buf)
+	if lines[0]["status"] != float64(http.StatusOK) {
+		t.Errorf("status: got %v, want 200", lines[0]["status"])
+	}
+	if lines[0]["bytes"] != float64(2) {
+		t.Errorf("bytes: got %v, want 2", lines[0]["bytes"])
+	}
+}
+
+func TestRequestLogger_ParsesCloudTraceContext(t *testing.T) {
+	var buf bytes.Buffer
+	h := middleware.RequestLogger(newTestLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Cloud-Trace-Context", "105445aa7843bc8bf206b12000100000/1;o=1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := decodeLogLines(t, &buf)
+	if lines[0]["trace_id"] != "105445aa7843bc8bf206b12000100000" {
+		t.Errorf("trace_id: got %v", lines[0]["trace_id"])
+	}
+	if lines[0]["span_id"] != "1" {
+		t.Errorf("span_id: got %v", lines[0]["span_id"])
+	}
+}
+
+func TestRequestLogger_MissingCloudTraceContext_OmitsTraceFields(t *testing.T) {
+	var buf bytes.Buffer
+	h := middleware.RequestLogger(newTestLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	lines := decodeLogLines(t, &buf)
+	if _, ok := lines[0]["trace_id"]; ok {
+		t.Error("expected no trace_id field when header is absent")
+	}
+}
+
+func TestRequestLogger_InjectsLoggerIntoContext(t *testing.T) {
+	var buf bytes.Buffer
+	var gotLogger *slog.Logger
+	h := middleware.RequestLogger(newTestLogger(&buf))(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		gotLogger = middleware.LoggerFromContext(r.Context())
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if gotLogger == nil {
+		t.Fatal("expected a non-nil logger in the request context")
+	}
+}
+
+func TestLoggerFromContext_FallsBackToDefault(t *testing.T) {
+	logger := middleware.LoggerFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	if logger == nil {
+		t.Fatal("expected a non-nil default logger")
+	}
+}
+
+func TestRequireAuth_EnrichesLoggerWithUIDAndEmail(t *testing.T) {
+	var buf bytes.Buffer
+
+	claims := &auth.TokenClaims{UID: "firebase-uid-7", Email: "logged@example.com"}
+	captureHandler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		middleware.LoggerFromContext(r.Context()).Info("handler reached")
+	})
+
+	chain := middleware.RequestLogger(newTestLogger(&buf))(
+		middleware.RequireAuth(&stubVerifier{claims: claims})(captureHandler),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.Header.Set("Authorization", "Bearer valid.token")
+	chain.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) == 0 {
+		t.Fatal("expected at least one log line")
+	}
+	if lines[0]["uid"] != claims.UID {
+		t.Errorf("uid: got %v, want %v", lines[0]["uid"], claims.UID)
+	}
+	if lines[0]["email"] != claims.Email {
+		t.Errorf("email: got %v, want %v", lines[0]["email"], claims.Email)
+	}
+}
@@ -18,19 +18,22 @@ type Migrator interface {
 	Up() error
 }
 
-// migrateMaker is a function type that creates a Migrator from a *sql.DB and
-// the migrations filesystem.  Replaced in tests to inject a mock.
-type migrateMaker func(db *sql.DB, migrationsFS fs.ReadDirFS) (Migrator, error)
+// migrateMaker is a function type that creates a Migrator from a *sql.DB, the
+// migrations filesystem, and the target schema.  Replaced in tests to inject
+// a mock.
+type migrateMaker func(db *sql.DB, migrationsFS fs.ReadDirFS, schema string) (Migrator, error)
 
 // defaultMakeMigrator is the production implementation that builds a real
-// *migrate.Migrate instance backed by iofs + postgres driver.
-func defaultMakeMigrator(db *sql.DB, migrationsFS fs.ReadDirFS) (Migrator, error) {
+// *migrate.Migrate instance backed by iofs + postgres driver. An empty
+// schema leaves postgres.Config's SchemaName unset, which golang-migrate
+// defaults to "public" — the same as omitting it entirely.
+func defaultMakeMigrator(db *sql.DB, migrationsFS fs.ReadDirFS, schema string) (Migrator, error) {
 	src, err := iofs.New(migrationsFS, ".")
 	if err != nil {
 		return nil, fmt.Errorf("iofs source: %w", err)
 	}
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	driver, err := postgres.WithInstance(db, &postgres.Config{SchemaName: schema})
 	if err != nil {
 		return nil, fmt.Errorf("postgres driver: %w", err)
 	}
@@ -42,16 +45,24 @@ func defaultMakeMigrator(db *sql.DB, migrationsFS fs.ReadDirFS) (Migrator, error
 	return m, nil
 }
 
-// RunMigrations applies all pending migrations.
+// RunMigrations applies all pending migrations against the "public" schema.
 // migrationsFS must be a directory FS whose root contains the numbered *.sql
 // files (e.g. 0001_initial_schema.up.sql).
 func RunMigrations(db *sql.DB, migrationsFS fs.ReadDirFS) error {
-	return runMigrations(db, migrationsFS, defaultMakeMigrator)
+	return runMigrations(db, migrationsFS, "", defaultMakeMigrator)
+}
+
+// RunMigrationsInSchema is RunMigrations against a non-default schema, so
+// callers can isolate migrations to one schema within a shared database —
+// used by repositorytest.NewTestDB to give each test its own schema within
+// one shared Postgres container.
+func RunMigrationsInSchema(db *sql.DB, migrationsFS fs.ReadDirFS, schema string) error {
+	return runMigrations(db, migrationsFS, schema, defaultMakeMigrator)
 }
 
 // runMigrations is the testable inner implementation.
-func runMigrations(db *sql.DB, migrationsFS fs.ReadDirFS, maker migrateMaker) error {
-	m, err := maker(db, migrationsFS)
+func runMigrations(db *sql.DB, migrationsFS fs.ReadDirFS, schema string, maker migrateMaker) error {
+	m, err := maker(db, migrationsFS, schema)
 	if err != nil {
 		return err
 	}
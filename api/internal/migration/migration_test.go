@@ -20,14 +20,14 @@ func (s *stubMigrator) Up() error { return s.upErr }
 
 // noopMaker returns a stubMigrator regardless of inputs.
 func noopMaker(upErr error) migrateMaker {
-	return func(_ *sql.DB, _ fs.ReadDirFS) (Migrator, error) {
+	return func(_ *sql.DB, _ fs.ReadDirFS, _ string) (Migrator, error) {
 		return &stubMigrator{upErr: upErr}, nil
 	}
 }
 
 // errorMaker simulates a failure during Migrator construction.
 func errorMaker(makeErr error) migrateMaker {
-	return func(_ *sql.DB, _ fs.ReadDirFS) (Migrator, error) {
+	return func(_ *sql.DB, _ fs.ReadDirFS, _ string) (Migrator, error) {
 		return nil, makeErr
 	}
 }
@@ -55,21 +55,21 @@ func TestRunMigrationsPublic_PropagatesError(t *testing.T) {
 }
 
 func TestRunMigrations_Success(t *testing.T) {
-	if err := runMigrations(nil, emptyFS, noopMaker(nil)); err != nil {
+	if err := runMigrations(nil, emptyFS, "", noopMaker(nil)); err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
 
 func TestRunMigrations_NoChange(t *testing.T) {
 	// ErrNoChange is not a real error — migrations are already up to date.
-	if err := runMigrations(nil, emptyFS, noopMaker(migrate.ErrNoChange)); err != nil {
+	if err := runMigrations(nil, emptyFS, "", noopMaker(migrate.ErrNoChange)); err != nil {
 		t.Fatalf("expected no error on ErrNoChange, got %v", err)
 	}
 }
 
 func TestRunMigrations_UpError(t *testing.T) {
 	upErr := errors.New("dirty database")
-	err := runMigrations(nil, emptyFS, noopMaker(upErr))
+	err := runMigrations(nil, emptyFS, "", noopMaker(upErr))
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -80,7 +80,7 @@ func TestRunMigrations_UpError(t *testing.T) {
 
 func TestRunMigrations_MakerError(t *testing.T) {
 	makeErr := errors.New("driver init failed")
-	err := runMigrations(nil, emptyFS, errorMaker(makeErr))
+	err := runMigrations(nil, emptyFS, "", errorMaker(makeErr))
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -88,3 +88,9 @@ func TestRunMigrations_MakerError(t *testing.T) {
 		t.Errorf("expected wrapped makeErr, got %v", err)
 	}
 }
+
+func TestRunMigrationsInSchema_Success(t *testing.T) {
+	if err := runMigrations(nil, emptyFS, "tenant_1", noopMaker(nil)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
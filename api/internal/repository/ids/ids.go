@@ -0,0 +1,101 @@
+// Package ids generates client-side primary keys for repository rows.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// RepositoryID is a UUIDv7 value: 128 bits laid out as a 48-bit Unix-ms
+// timestamp, a 4-bit version, 12 bits of sub-millisecond randomness, a
+// 2-bit variant, and 62 bits of randomness (RFC 9562). Because the high
+// bits encode a monotonically increasing timestamp, comparing two
+// RepositoryIDs byte-for-byte (or lexically, once hex-encoded) orders them
+// by creation time — see Time.
+type RepositoryID [16]byte
+
+// NewID generates a new UUIDv7 and returns its canonical 8-4-4-4-12 hex
+// string, ready to pass as a query argument for a caller-supplied primary
+// key (see repository.UserRepository.Upsert).
+func NewID() string {
+	return newRepositoryID().String()
+}
+
+func newRepositoryID() RepositoryID {
+	var id RepositoryID
+
+	ms := time.Now().UnixMilli()
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+
+	if _, err := rand.Read(id[6:]); err != nil {
+		// crypto/rand failing means the system's entropy source is broken;
+		// there's no sane fallback, so this is as unrecoverable as
+		// crypto/rand.Read's own documented behaviour treats it.
+		panic(fmt.Errorf("ids: generate uuidv7 randomness: %w", err))
+	}
+
+	id[6] = (id[6] & 0x0f) | 0x70 // version 7
+	id[8] = (id[8] & 0x3f) | 0x80 // variant 10 (RFC 9562)
+
+	return id
+}
+
+// String returns the canonical 8-4-4-4-12 hex representation.
+func (id RepositoryID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:36], id[10:16])
+	return string(buf[:])
+}
+
+// Time returns the creation timestamp embedded in id, truncated to
+// millisecond precision. Because UUIDv7 is monotonic, this is what lets a
+// RepositoryID double as a pagination cursor for endpoints like a future
+// GET /api/users?after=<id>: ordering by id already orders by Time.
+func (id RepositoryID) Time() time.Time {
+	ms := int64(id[0])<<40 | int64(id[1])<<32 | int64(id[2])<<24 |
+		int64(id[3])<<16 | int64(id[4])<<8 | int64(id[5])
+	return time.UnixMilli(ms)
+}
+
+// Parse parses s, a canonical 8-4-4-4-12 hex UUID string, into a
+// RepositoryID.
+func Parse(s string) (RepositoryID, error) {
+	var id RepositoryID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return id, fmt.Errorf("ids: parse %q: not a canonical UUID string", s)
+	}
+
+	hexStr := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	b, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return id, fmt.Errorf("ids: parse %q: %w", s, err)
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// MustParse is Parse, panicking on error. It exists for call sites that
+// parse a value they already know to be well-formed (tests, constants),
+// not for parsing untrusted input.
+func MustParse(s string) RepositoryID {
+	id, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,80 @@
+package ids
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewID_IsCanonicalUUIDString(t *testing.T) {
+	s := NewID()
+
+	if len(s) != 36 {
+		t.Fatalf("expected 36-char UUID string, got %d: %q", len(s), s)
+	}
+	for _, i := range []int{8, 13, 18, 23} {
+		if s[i] != '-' {
+			t.Fatalf("expected '-' at index %d, got %q in %q", i, s[i], s)
+		}
+	}
+}
+
+func TestNewID_SetsVersionAndVariant(t *testing.T) {
+	id := newRepositoryID()
+
+	if v := id[6] >> 4; v != 0x7 {
+		t.Errorf("expected version nibble 0x7, got %x", v)
+	}
+	if variant := id[8] >> 6; variant != 0b10 {
+		t.Errorf("expected variant bits 0b10, got %b", variant)
+	}
+}
+
+func TestNewID_IsMonotonicallyOrderedOverTime(t *testing.T) {
+	first := NewID()
+	time.Sleep(2 * time.Millisecond)
+	second := NewID()
+
+	if strings.Compare(first, second) >= 0 {
+		t.Errorf("expected ids to sort by creation time, got %q then %q", first, second)
+	}
+}
+
+func TestParse_RoundTripsWithString(t *testing.T) {
+	id := newRepositoryID()
+
+	got, err := Parse(id.String())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got != id {
+		t.Errorf("got %v, want %v", got, id)
+	}
+}
+
+func TestParse_RejectsMalformedInput(t *testing.T) {
+	if _, err := Parse("not-a-uuid"); err == nil {
+		t.Error("expected an error for malformed input")
+	}
+}
+
+func TestMustParse_PanicsOnInvalidInput(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustParse to panic on invalid input")
+		}
+	}()
+	MustParse("not-a-uuid")
+}
+
+func TestRepositoryID_TimeIsCloseToCreationTime(t *testing.T) {
+	want := time.Now().Truncate(time.Millisecond)
+	id := newRepositoryID()
+
+	got := id.Time()
+	// newRepositoryID stamps the current time itself, so assert against
+	// "now" within a generous tolerance rather than injecting a clock.
+	if diff := got.Sub(want); diff < -time.Second || diff > time.Second {
+		t.Errorf("Time() = %v, want close to %v", got, want)
+	}
+}
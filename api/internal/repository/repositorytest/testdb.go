@@ -0,0 +1,116 @@
+//go:build integration
+
+// Package repositorytest provides a real-Postgres integration harness for
+// internal/repository. The fake SQL driver in user_test.go is fast but only
+// exercises argument capture — it can't catch a bad ON CONFLICT clause, a
+// wrong column order, or a type mismatch that only shows up against a real
+// server. NewTestDB spins up one testcontainers-go Postgres container per
+// test binary run and hands each test its own schema within it, so tests
+// stay isolated without paying a container-startup cost per test.
+//
+// Build and run with: go test -tags=integration ./internal/repository/...
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/ai-teammate/mytube/api/internal/migration"
+	"github.com/ai-teammate/mytube/api/migrations"
+)
+
+var (
+	containerOnce sync.Once
+	containerDSN  string
+	containerErr  error
+
+	schemaCounter int64
+)
+
+// NewTestDB returns a *sql.DB connected to a fresh schema (migrations/*.sql
+// already applied) within a Postgres container shared across the whole test
+// binary run. The schema is dropped in t.Cleanup.
+func NewTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := sharedContainerDSN(t)
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("repositorytest: open admin connection: %v", err)
+	}
+	defer admin.Close()
+
+	schema := nextSchemaName()
+	if _, err := admin.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema)); err != nil {
+		t.Fatalf("repositorytest: create schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema)); err != nil {
+			t.Errorf("repositorytest: drop schema %s: %v", schema, err)
+		}
+	})
+
+	// search_path is set via the connection's "options" parameter rather
+	// than a per-query "SET search_path" so every pooled connection in db
+	// (not just the one that happened to run the first query) resolves
+	// unqualified table names to this test's schema.
+	db, err := sql.Open("postgres", fmt.Sprintf("%s options='-c search_path=%s'", dsn, schema))
+	if err != nil {
+		t.Fatalf("repositorytest: open schema-scoped connection: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := migration.RunMigrationsInSchema(db, migrations.FS, schema); err != nil {
+		t.Fatalf("repositorytest: run migrations in schema %s: %v", schema, err)
+	}
+
+	return db
+}
+
+// nextSchemaName returns a process-unique schema name for one test.
+func nextSchemaName() string {
+	n := atomic.AddInt64(&schemaCounter, 1)
+	return fmt.Sprintf("test_%d", n)
+}
+
+// sharedContainerDSN starts the shared Postgres container on first use and
+// returns its admin DSN for every subsequent call in this test binary run.
+func sharedContainerDSN(t *testing.T) string {
+	t.Helper()
+
+	containerOnce.Do(func() {
+		ctx := context.Background()
+		container, err := postgres.Run(ctx, "postgres:16-alpine",
+			postgres.WithDatabase("mytube_test"),
+			postgres.WithUsername("mytube_test"),
+			postgres.WithPassword("mytube_test"),
+			postgres.BasicWaitStrategies(),
+			wait.ForListeningPort("5432/tcp"),
+		)
+		if err != nil {
+			containerErr = fmt.Errorf("start postgres container: %w", err)
+			return
+		}
+
+		dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+		if err != nil {
+			containerErr = fmt.Errorf("container connection string: %w", err)
+			return
+		}
+		containerDSN = dsn
+	})
+
+	if containerErr != nil {
+		t.Fatalf("repositorytest: %v", containerErr)
+	}
+	return containerDSN
+}
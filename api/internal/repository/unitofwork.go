@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// UnitOfWork runs a group of statements as a single all-or-nothing
+// transaction against a *sql.DB. It exists so repository methods that need
+// to read-then-write (or write-then-read) without a concurrent change
+// slipping in between don't have to hand-roll BeginTx/Commit/Rollback
+// bookkeeping themselves — see UserRepository.Upsert — and so future repos
+// can share the same mechanism instead of each growing their own.
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork constructs a UnitOfWork backed by db.
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// WithTx begins a transaction scoped to ctx and passes it to fn. *sql.Tx
+// satisfies any repo's Querier interface built from QueryRowContext /
+// QueryContext / ExecContext (see UserQuerier), so fn can use it as a
+// drop-in replacement for the repo's usual db handle. If fn returns an
+// error, the transaction is rolled back and that error is returned
+// unwrapped (or wrapped with %w alongside the rollback error, if the rollback
+// also fails) so callers can still errors.Is/As against it; otherwise the
+// transaction is committed. Because the transaction is started with
+// BeginTx(ctx, nil) rather than Begin(), a canceled ctx aborts it the next
+// time it's used, the same as any other context-aware query.
+func (u *UnitOfWork) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return fmt.Errorf("%w (rollback also failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
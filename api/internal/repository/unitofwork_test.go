@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/internal/repository"
+)
+
+func TestUnitOfWork_CommitsOnSuccess(t *testing.T) {
+	db, err := sql.Open("fakedb", nextDSN())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	uow := repository.NewUnitOfWork(db)
+
+	var ranWithTx bool
+	err = uow.WithTx(context.Background(), func(tx *sql.Tx) error {
+		ranWithTx = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ranWithTx {
+		t.Error("expected fn to be called with a *sql.Tx")
+	}
+}
+
+func TestUnitOfWork_RollsBackAndReturnsErrOnFailure(t *testing.T) {
+	db, err := sql.Open("fakedb", nextDSN())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	uow := repository.NewUnitOfWork(db)
+
+	fnErr := errors.New("callback failed")
+	err = uow.WithTx(context.Background(), func(tx *sql.Tx) error {
+		return fnErr
+	})
+	if !errors.Is(err, fnErr) {
+		t.Errorf("expected WithTx to return the callback's error unwrapped, got: %v", err)
+	}
+}
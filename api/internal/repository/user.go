@@ -8,73 +8,157 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/ai-teammate/mytube/api/internal/repository/ids"
 )
 
 // User represents a row in the users table.
 type User struct {
-	ID          string
-	FirebaseUID string
-	Username    string
-	AvatarURL   *string
-	CreatedAt   time.Time
+	ID        string
+	Provider  string
+	Subject   string
+	Username  string
+	AvatarURL *string
+	CreatedAt time.Time
 }
 
 // UserQuerier is the database interface used by UserRepository.
-// Satisfied by *sql.DB and allows tests to inject a stub.
+// Satisfied by *sql.DB and *sql.Tx, and allows tests to inject a stub.
 type UserQuerier interface {
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 }
 
 // UserRepository handles persistence for the users table.
 type UserRepository struct {
-	db UserQuerier
+	db  UserQuerier
+	uow *UnitOfWork
 }
 
-// NewUserRepository constructs a UserRepository backed by db.
+// NewUserRepository constructs a UserRepository backed by db. If db is a
+// *sql.DB, Upsert runs inside a UnitOfWork transaction; other UserQuerier
+// implementations (e.g. test stubs, or a *sql.Tx handed down by a caller
+// that's already in a transaction) fall back to running it directly, since
+// there's nothing left to begin a nested transaction on.
 func NewUserRepository(db UserQuerier) *UserRepository {
-	return &UserRepository{db: db}
+	r := &UserRepository{db: db}
+	if sqlDB, ok := db.(*sql.DB); ok {
+		r.uow = NewUnitOfWork(sqlDB)
+	}
+	return r
 }
 
-// Upsert inserts a new user row for the given firebase_uid, defaulting the
-// username to the email prefix (the part before "@").  On conflict it does
-// nothing, leaving the existing row unchanged.  The current user row is then
-// fetched and returned.
+// upsertSQL both writes and reads the row in one round trip: the DO UPDATE
+// is a no-op (it reassigns username to itself) purely so RETURNING always
+// has a row to give back, whether this call created it or a prior call did.
+// id is supplied by the caller (see ids.NewID) rather than left to a
+// database default, so it's known before the row is ever written.
+const upsertSQL = `
+INSERT INTO users (id, provider, subject, username)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (provider, subject) DO UPDATE SET username = users.username
+RETURNING id, provider, subject, username, avatar_url, created_at`
+
+// Upsert inserts a new user row for the given (provider, subject) pair,
+// defaulting the username to the email prefix (the part before "@"). On
+// conflict the existing row is left unchanged. Either way the current user
+// row is returned.
 //
 // This implements the auto-provisioning behaviour specified in MYTUBE-13: the
 // first successful token verification creates the users row; all subsequent
-// calls are no-ops.
-func (r *UserRepository) Upsert(ctx context.Context, firebaseUID, email string) (*User, error) {
+// calls are no-ops. Keying on (provider, subject) rather than a single
+// firebase_uid column is what lets multiple identity providers share the
+// users table — see auth.MultiVerifier.
+//
+// The insert and the read run as a single statement (rather than an
+// ExecContext followed by a separate SELECT) so there's no window for a
+// concurrent delete of the row to turn this into the same (nil, nil) result
+// as "never existed" — see handler.NewMeHandler. When the repository is
+// backed by a real *sql.DB, that statement additionally runs inside a
+// UnitOfWork transaction, which is mostly free given it's one round trip
+// already, but keeps the behaviour consistent with future Upsert-style
+// methods that do need more than one statement.
+//
+// The id passed to INSERT is generated client-side (ids.NewID) rather than
+// left to the users.id column default, so callers that need to reference
+// the row before this call commits — an outbox row, an emitted event — can
+// generate and use the same id up front. On the (provider, subject)
+// conflict path the generated id is simply discarded in favour of the
+// existing row's id, same as any other column here.
+func (r *UserRepository) Upsert(ctx context.Context, provider, subject, email string) (*User, error) {
 	username := emailPrefix(email)
+	id := ids.NewID()
 
-	const upsertSQL = `
-INSERT INTO users (firebase_uid, username)
-VALUES ($1, $2)
-ON CONFLICT (firebase_uid) DO NOTHING`
+	scan := func(q UserQuerier) (*User, error) {
+		row := q.QueryRowContext(ctx, upsertSQL, id, provider, subject, username)
+		var u User
+		if err := row.Scan(&u.ID, &u.Provider, &u.Subject, &u.Username, &u.AvatarURL, &u.CreatedAt); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("upsert user: %w", err)
+		}
+		return &u, nil
+	}
 
-	if _, err := r.db.ExecContext(ctx, upsertSQL, firebaseUID, username); err != nil {
-		return nil, fmt.Errorf("upsert user: %w", err)
+	if r.uow == nil {
+		return scan(r.db)
 	}
 
-	return r.GetByFirebaseUID(ctx, firebaseUID)
+	var user *User
+	err := r.uow.WithTx(ctx, func(tx *sql.Tx) error {
+		u, err := scan(tx)
+		if err != nil {
+			return err
+		}
+		user = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetByProviderSubject fetches the user row identified by (provider,
+// subject). Returns (nil, nil) when no matching row exists.
+func (r *UserRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*User, error) {
+	const selectSQL = `
+SELECT id, provider, subject, username, avatar_url, created_at
+FROM   users
+WHERE  provider = $1 AND subject = $2`
+
+	row := r.db.QueryRowContext(ctx, selectSQL, provider, subject)
+
+	var u User
+	if err := row.Scan(&u.ID, &u.Provider, &u.Subject, &u.Username, &u.AvatarURL, &u.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get user by provider/subject: %w", err)
+	}
+	return &u, nil
 }
 
-// GetByFirebaseUID fetches the user row identified by firebase_uid.
+// GetByID fetches the user row by its primary key. Used on the session
+// fast path (see middleware.RequireAuthOrSession), where the caller already
+// knows the internal user ID and has no provider/subject to look up by.
 // Returns (nil, nil) when no matching row exists.
-func (r *UserRepository) GetByFirebaseUID(ctx context.Context, firebaseUID string) (*User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*User, error) {
 	const selectSQL = `
-SELECT id, firebase_uid, username, avatar_url, created_at
+SELECT id, provider, subject, username, avatar_url, created_at
 FROM   users
-WHERE  firebase_uid = $1`
+WHERE  id = $1`
 
-	row := r.db.QueryRowContext(ctx, selectSQL, firebaseUID)
+	row := r.db.QueryRowContext(ctx, selectSQL, id)
 
 	var u User
-	if err := row.Scan(&u.ID, &u.FirebaseUID, &u.Username, &u.AvatarURL, &u.CreatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.Provider, &u.Subject, &u.Username, &u.AvatarURL, &u.CreatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("get user by firebase uid: %w", err)
+		return nil, fmt.Errorf("get user by id: %w", err)
 	}
 	return &u, nil
 }
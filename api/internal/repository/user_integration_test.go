@@ -0,0 +1,118 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ai-teammate/mytube/api/internal/repository"
+	"github.com/ai-teammate/mytube/api/internal/repository/repositorytest"
+)
+
+// These mirror the fake-driver cases in user_test.go, but run against a real Postgres
+// (via repositorytest.NewTestDB) so a bad ON CONFLICT clause, wrong column
+// order, or type mismatch actually fails instead of passing against a fake
+// that just echoes back whatever row it was told to return.
+//
+// Run with: go test -tags=integration ./internal/repository/...
+
+func TestUpsert_Integration_CreatesRowOnFirstCall(t *testing.T) {
+	db := repositorytest.NewTestDB(t)
+	repo := repository.NewUserRepository(db)
+
+	user, err := repo.Upsert(context.Background(), "firebase", "uid-1", "alice@example.com")
+	if err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if user == nil {
+		t.Fatal("expected non-nil user")
+	}
+	if user.Username != "alice" {
+		t.Errorf("Username: got %q, want %q", user.Username, "alice")
+	}
+	if user.Provider != "firebase" || user.Subject != "uid-1" {
+		t.Errorf("got (provider, subject) = (%q, %q), want (firebase, uid-1)", user.Provider, user.Subject)
+	}
+}
+
+func TestUpsert_Integration_IsIdempotentOnConflict(t *testing.T) {
+	db := repositorytest.NewTestDB(t)
+	repo := repository.NewUserRepository(db)
+
+	first, err := repo.Upsert(context.Background(), "firebase", "uid-2", "bob@example.com")
+	if err != nil {
+		t.Fatalf("first upsert: %v", err)
+	}
+
+	// A second Upsert for the same (provider, subject) with a different
+	// email must not change the existing row — the ON CONFLICT clause
+	// reassigns username to itself rather than taking the new value.
+	second, err := repo.Upsert(context.Background(), "firebase", "uid-2", "someone-else@example.com")
+	if err != nil {
+		t.Fatalf("second upsert: %v", err)
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("expected the same user ID across upserts, got %q then %q", first.ID, second.ID)
+	}
+	if second.Username != first.Username {
+		t.Errorf("expected username unchanged by conflicting upsert, got %q then %q", first.Username, second.Username)
+	}
+}
+
+func TestUpsert_Integration_ConcurrentUpsertsAreIdempotent(t *testing.T) {
+	db := repositorytest.NewTestDB(t)
+	repo := repository.NewUserRepository(db)
+
+	const concurrency = 20
+	var wg sync.WaitGroup
+	ids := make([]string, concurrency)
+	errs := make([]error, concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			user, err := repo.Upsert(context.Background(), "firebase", "uid-concurrent", "racer@example.com")
+			errs[i] = err
+			if user != nil {
+				ids[i] = user.ID
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("upsert %d: %v", i, err)
+		}
+	}
+	for i := 1; i < concurrency; i++ {
+		if ids[i] != ids[0] {
+			t.Fatalf("expected every concurrent upsert to resolve to the same user ID, got %q at 0 and %q at %d", ids[0], ids[i], i)
+		}
+	}
+
+	got, err := repo.GetByProviderSubject(context.Background(), "firebase", "uid-concurrent")
+	if err != nil {
+		t.Fatalf("get by provider/subject: %v", err)
+	}
+	if got == nil || got.ID != ids[0] {
+		t.Fatalf("expected exactly one row for (firebase, uid-concurrent), got %+v", got)
+	}
+}
+
+func TestGetByProviderSubject_Integration_NotFound(t *testing.T) {
+	db := repositorytest.NewTestDB(t)
+	repo := repository.NewUserRepository(db)
+
+	user, err := repo.GetByProviderSubject(context.Background(), "firebase", "does-not-exist")
+	if err != nil {
+		t.Fatalf("expected nil error for not-found, got: %v", err)
+	}
+	if user != nil {
+		t.Errorf("expected nil user, got: %+v", user)
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ai-teammate/mytube/api/internal/repository"
+	"github.com/ai-teammate/mytube/api/internal/repository/ids"
 )
 
 // ─── minimal fake SQL driver ──────────────────────────────────────────────────
@@ -33,9 +35,15 @@ func nextDSN() string {
 // resultRegistry maps DSN strings to their configured query results.
 var resultRegistry = map[string][]fakeQueryResult{}
 
+// capturedArgsRegistry maps DSN strings to a slot the fake driver records
+// the most recent statement's args into, so tests can assert on them after
+// the call returns.
+var capturedArgsRegistry = map[string]*[]driver.Value{}
+
 type fakeQueryResult struct {
 	columns []string
 	rows    [][]driver.Value
+	err     error
 }
 
 // registerResults stores results under a unique DSN and returns that DSN.
@@ -45,18 +53,28 @@ func registerResults(results []fakeQueryResult) string {
 	return dsn
 }
 
+// registerResultsCapturingArgs is registerResults plus a pointer that's
+// populated with the args the registered statement was called with.
+func registerResultsCapturingArgs(results []fakeQueryResult) (string, *[]driver.Value) {
+	dsn := registerResults(results)
+	captured := &[]driver.Value{}
+	capturedArgsRegistry[dsn] = captured
+	return dsn, captured
+}
+
 // ─── driver implementation ────────────────────────────────────────────────────
 
 type fakeDriver struct{}
 
 func (*fakeDriver) Open(name string) (driver.Conn, error) {
 	results := resultRegistry[name] // nil if not registered — returns empty sets
-	return &fakeConn{results: results}, nil
+	return &fakeConn{results: results, captured: capturedArgsRegistry[name]}, nil
 }
 
 type fakeConn struct {
-	results []fakeQueryResult
-	pos     int
+	results  []fakeQueryResult
+	pos      int
+	captured *[]driver.Value // non-nil when the DSN was registered to capture statement args
 }
 
 func (c *fakeConn) Prepare(_ string) (driver.Stmt, error) {
@@ -65,24 +83,36 @@ func (c *fakeConn) Prepare(_ string) (driver.Stmt, error) {
 		qr = c.results[c.pos]
 		c.pos++
 	}
-	return &fakeStmt{qr: qr}, nil
+	return &fakeStmt{qr: qr, captured: c.captured}, nil
 }
-func (c *fakeConn) Close() error                 { return nil }
-func (c *fakeConn) Begin() (driver.Tx, error)    { return &fakeTx{}, nil }
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{}, nil }
 
 type fakeTx struct{}
 
 func (*fakeTx) Commit() error   { return nil }
 func (*fakeTx) Rollback() error { return nil }
 
-type fakeStmt struct{ qr fakeQueryResult }
+type fakeStmt struct {
+	qr       fakeQueryResult
+	captured *[]driver.Value
+}
 
-func (*fakeStmt) Close() error   { return nil }
-func (*fakeStmt) NumInput() int  { return -1 }
-func (*fakeStmt) Exec(_ []driver.Value) (driver.Result, error) {
+func (*fakeStmt) Close() error  { return nil }
+func (*fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.captured != nil {
+		*s.captured = args
+	}
 	return fakeDriverResult{}, nil
 }
-func (s *fakeStmt) Query(_ []driver.Value) (driver.Rows, error) {
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.captured != nil {
+		*s.captured = args
+	}
+	if s.qr.err != nil {
+		return nil, s.qr.err
+	}
 	return &fakeRows{cols: s.qr.columns, data: s.qr.rows}, nil
 }
 
@@ -116,6 +146,13 @@ func emptyDB() *sql.DB {
 	return db
 }
 
+// errDB returns a *sql.DB whose first query fails with err.
+func errDB(err error) *sql.DB {
+	dsn := registerResults([]fakeQueryResult{{err: err}})
+	db, _ := sql.Open("fakedb", dsn)
+	return db
+}
+
 // userDB returns a *sql.DB whose first QueryRowContext returns the given user.
 func userDB(u *repository.User) *sql.DB {
 	avatarVal := driver.Value(nil)
@@ -124,8 +161,8 @@ func userDB(u *repository.User) *sql.DB {
 	}
 	dsn := registerResults([]fakeQueryResult{
 		{
-			columns: []string{"id", "firebase_uid", "username", "avatar_url", "created_at"},
-			rows:    [][]driver.Value{{u.ID, u.FirebaseUID, u.Username, avatarVal, u.CreatedAt}},
+			columns: []string{"id", "provider", "subject", "username", "avatar_url", "created_at"},
+			rows:    [][]driver.Value{{u.ID, u.Provider, u.Subject, u.Username, avatarVal, u.CreatedAt}},
 		},
 	})
 	db, _ := sql.Open("fakedb", dsn)
@@ -141,46 +178,48 @@ func (okResult) RowsAffected() (int64, error) { return 1, nil }
 
 // ─── UserQuerier stubs ────────────────────────────────────────────────────────
 
-// captureQuerier records ExecContext arguments; QueryRowContext delegates to
-// an empty fakedb DB (no rows returned).
+// captureQuerier records QueryRowContext arguments and returns no rows,
+// simulating an INSERT ... RETURNING that found no row to return.
+// ExecContext/QueryContext are unused by UserRepository today but are
+// implemented to satisfy UserQuerier.
 type captureQuerier struct {
 	capturedArgs []any
-	execErr      error
 }
 
-func (q *captureQuerier) ExecContext(_ context.Context, _ string, args ...any) (sql.Result, error) {
-	q.capturedArgs = args
-	if q.execErr != nil {
-		return nil, q.execErr
-	}
+func (q *captureQuerier) ExecContext(_ context.Context, _ string, _ ...any) (sql.Result, error) {
 	return okResult{}, nil
 }
 
-func (q *captureQuerier) QueryRowContext(_ context.Context, _ string, _ ...any) *sql.Row {
+func (q *captureQuerier) QueryContext(_ context.Context, _ string, _ ...any) (*sql.Rows, error) {
+	return emptyDB().QueryContext(context.Background(), "SELECT 1")
+}
+
+func (q *captureQuerier) QueryRowContext(_ context.Context, _ string, args ...any) *sql.Row {
+	q.capturedArgs = args
 	return emptyDB().QueryRowContext(context.Background(), "SELECT 1")
 }
 
 // rowQuerier returns a fully-populated row from QueryRowContext (for found tests).
 type rowQuerier struct {
-	user    *repository.User
-	execErr error
+	user *repository.User
 }
 
 func (q *rowQuerier) ExecContext(_ context.Context, _ string, _ ...any) (sql.Result, error) {
-	if q.execErr != nil {
-		return nil, q.execErr
-	}
 	return okResult{}, nil
 }
 
+func (q *rowQuerier) QueryContext(_ context.Context, _ string, _ ...any) (*sql.Rows, error) {
+	return emptyDB().QueryContext(context.Background(), "SELECT 1")
+}
+
 func (q *rowQuerier) QueryRowContext(_ context.Context, _ string, _ ...any) *sql.Row {
 	if q.user == nil {
 		return emptyDB().QueryRowContext(context.Background(), "SELECT 1")
 	}
 	return userDB(q.user).QueryRowContext(
 		context.Background(),
-		"SELECT id, firebase_uid, username, avatar_url, created_at FROM users WHERE firebase_uid = $1",
-		q.user.FirebaseUID,
+		"SELECT id, provider, subject, username, avatar_url, created_at FROM users WHERE provider = $1 AND subject = $2",
+		q.user.Provider, q.user.Subject,
 	)
 }
 
@@ -190,14 +229,14 @@ func TestUpsert_UsesEmailPrefix(t *testing.T) {
 	q := &captureQuerier{}
 	repo := repository.NewUserRepository(q)
 
-	_, _ = repo.Upsert(context.Background(), "uid1", "alice@example.com")
+	_, _ = repo.Upsert(context.Background(), "firebase", "uid1", "alice@example.com")
 
-	if len(q.capturedArgs) < 2 {
-		t.Fatalf("expected ≥2 args, got %d", len(q.capturedArgs))
+	if len(q.capturedArgs) < 4 {
+		t.Fatalf("expected ≥4 args, got %d", len(q.capturedArgs))
 	}
-	got, ok := q.capturedArgs[1].(string)
+	got, ok := q.capturedArgs[3].(string)
 	if !ok {
-		t.Fatalf("expected string arg[1], got %T", q.capturedArgs[1])
+		t.Fatalf("expected string arg[3], got %T", q.capturedArgs[3])
 	}
 	if got != "alice" {
 		t.Errorf("expected username 'alice', got %q", got)
@@ -208,38 +247,100 @@ func TestUpsert_EmailWithoutAt(t *testing.T) {
 	q := &captureQuerier{}
 	repo := repository.NewUserRepository(q)
 
-	_, _ = repo.Upsert(context.Background(), "uid2", "noemail")
+	_, _ = repo.Upsert(context.Background(), "firebase", "uid2", "noemail")
 
-	got := q.capturedArgs[1].(string)
+	got := q.capturedArgs[3].(string)
 	if got != "noemail" {
 		t.Errorf("expected username 'noemail', got %q", got)
 	}
 }
 
-func TestUpsert_FirebaseUIDPassedToExec(t *testing.T) {
+func TestUpsert_ProviderAndSubjectPassedToQuery(t *testing.T) {
 	q := &captureQuerier{}
 	repo := repository.NewUserRepository(q)
 
-	_, _ = repo.Upsert(context.Background(), "my-firebase-uid", "user@test.com")
+	_, _ = repo.Upsert(context.Background(), "google", "my-google-sub", "user@test.com")
 
-	got := q.capturedArgs[0].(string)
-	if got != "my-firebase-uid" {
-		t.Errorf("expected firebase_uid 'my-firebase-uid', got %q", got)
+	gotProvider := q.capturedArgs[1].(string)
+	if gotProvider != "google" {
+		t.Errorf("expected provider 'google', got %q", gotProvider)
+	}
+	gotSubject := q.capturedArgs[2].(string)
+	if gotSubject != "my-google-sub" {
+		t.Errorf("expected subject 'my-google-sub', got %q", gotSubject)
 	}
 }
 
-func TestUpsert_ExecError(t *testing.T) {
-	dbErr := errors.New("db connection refused")
-	q := &captureQuerier{execErr: dbErr}
+func TestUpsert_GeneratesIDClientSide(t *testing.T) {
+	q := &captureQuerier{}
 	repo := repository.NewUserRepository(q)
 
-	user, err := repo.Upsert(context.Background(), "uid3", "bob@example.com")
+	_, _ = repo.Upsert(context.Background(), "firebase", "uid-id-test", "erin@example.com")
+
+	if len(q.capturedArgs) < 1 {
+		t.Fatalf("expected ≥1 args, got %d", len(q.capturedArgs))
+	}
+	gotID, ok := q.capturedArgs[0].(string)
+	if !ok {
+		t.Fatalf("expected string arg[0], got %T", q.capturedArgs[0])
+	}
+	if _, err := ids.Parse(gotID); err != nil {
+		t.Errorf("expected arg[0] to be a valid id, got %q: %v", gotID, err)
+	}
+}
+
+func TestUpsert_QueryError(t *testing.T) {
+	dbErr := errors.New("db connection refused")
+	repo := repository.NewUserRepository(errDB(dbErr))
+
+	user, err := repo.Upsert(context.Background(), "firebase", "uid3", "bob@example.com")
 
 	if user != nil {
-		t.Errorf("expected nil user on exec error")
+		t.Errorf("expected nil user on query error")
 	}
-	if !errors.Is(err, dbErr) {
-		t.Errorf("expected wrapped dbErr, got: %v", err)
+	if err == nil || !strings.Contains(err.Error(), dbErr.Error()) {
+		t.Errorf("expected error mentioning %q, got: %v", dbErr, err)
+	}
+}
+
+// TestUpsert_TransactionRunsWhenBackedByRealDB exercises the UnitOfWork path
+// (NewUserRepository given a *sql.DB rather than a stub), verifying the
+// upsert args still reach the underlying statement when run inside a
+// transaction.
+func TestUpsert_TransactionRunsWhenBackedByRealDB(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	expected := &repository.User{
+		ID:        "00000000-0000-0000-0000-000000000004",
+		Provider:  "firebase",
+		Subject:   "firebase-uid-4",
+		Username:  "dave",
+		CreatedAt: now,
+	}
+	dsn, captured := registerResultsCapturingArgs([]fakeQueryResult{
+		{
+			columns: []string{"id", "provider", "subject", "username", "avatar_url", "created_at"},
+			rows:    [][]driver.Value{{expected.ID, expected.Provider, expected.Subject, expected.Username, driver.Value(nil), expected.CreatedAt}},
+		},
+	})
+	db, err := sql.Open("fakedb", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	repo := repository.NewUserRepository(db)
+
+	got, err := repo.Upsert(context.Background(), expected.Provider, expected.Subject, "dave@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Username != expected.Username {
+		t.Errorf("Username: got %q, want %q", got.Username, expected.Username)
+	}
+
+	if len(*captured) < 4 {
+		t.Fatalf("expected ≥4 captured args, got %d", len(*captured))
+	}
+	if gotUsername, _ := (*captured)[3].(string); gotUsername != "dave" {
+		t.Errorf("expected username arg 'dave', got %q", gotUsername)
 	}
 }
 
@@ -247,7 +348,7 @@ func TestUpsert_ReturnsNilWhenSelectNotFound(t *testing.T) {
 	q := &captureQuerier{} // QueryRowContext returns no rows
 	repo := repository.NewUserRepository(q)
 
-	user, err := repo.Upsert(context.Background(), "uid4", "charlie@example.com")
+	user, err := repo.Upsert(context.Background(), "firebase", "uid4", "charlie@example.com")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -260,15 +361,16 @@ func TestUpsert_ReturnsNilWhenSelectNotFound(t *testing.T) {
 func TestUpsert_ReturnsUserWhenFound(t *testing.T) {
 	now := time.Now().Truncate(time.Second)
 	expected := &repository.User{
-		ID:          "00000000-0000-0000-0000-000000000003",
-		FirebaseUID: "firebase-uid-3",
-		Username:    "carol",
-		AvatarURL:   nil,
-		CreatedAt:   now,
+		ID:        "00000000-0000-0000-0000-000000000003",
+		Provider:  "firebase",
+		Subject:   "firebase-uid-3",
+		Username:  "carol",
+		AvatarURL: nil,
+		CreatedAt: now,
 	}
 
 	repo := repository.NewUserRepository(&rowQuerier{user: expected})
-	got, err := repo.Upsert(context.Background(), "firebase-uid-3", "carol@example.com")
+	got, err := repo.Upsert(context.Background(), "firebase", "firebase-uid-3", "carol@example.com")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -281,12 +383,12 @@ func TestUpsert_ReturnsUserWhenFound(t *testing.T) {
 	}
 }
 
-// ─── GetByFirebaseUID tests ───────────────────────────────────────────────────
+// ─── GetByProviderSubject tests ───────────────────────────────────────────────
 
-func TestGetByFirebaseUID_NotFound(t *testing.T) {
+func TestGetByProviderSubject_NotFound(t *testing.T) {
 	repo := repository.NewUserRepository(&rowQuerier{user: nil})
 
-	user, err := repo.GetByFirebaseUID(context.Background(), "unknown-uid")
+	user, err := repo.GetByProviderSubject(context.Background(), "firebase", "unknown-uid")
 
 	if err != nil {
 		t.Fatalf("expected nil error for not-found, got: %v", err)
@@ -296,19 +398,20 @@ func TestGetByFirebaseUID_NotFound(t *testing.T) {
 	}
 }
 
-func TestGetByFirebaseUID_Found(t *testing.T) {
+func TestGetByProviderSubject_Found(t *testing.T) {
 	now := time.Now().Truncate(time.Second)
 	avatarURL := "https://example.com/avatar.png"
 	expected := &repository.User{
-		ID:          "00000000-0000-0000-0000-000000000001",
-		FirebaseUID: "firebase-uid-1",
-		Username:    "alice",
-		AvatarURL:   &avatarURL,
-		CreatedAt:   now,
+		ID:        "00000000-0000-0000-0000-000000000001",
+		Provider:  "firebase",
+		Subject:   "firebase-uid-1",
+		Username:  "alice",
+		AvatarURL: &avatarURL,
+		CreatedAt: now,
 	}
 
 	repo := repository.NewUserRepository(&rowQuerier{user: expected})
-	got, err := repo.GetByFirebaseUID(context.Background(), "firebase-uid-1")
+	got, err := repo.GetByProviderSubject(context.Background(), "firebase", "firebase-uid-1")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -316,9 +419,6 @@ func TestGetByFirebaseUID_Found(t *testing.T) {
 	if got == nil {
 		t.Fatal("expected non-nil user, got nil")
 	}
-	if got.ID != expected.ID {
-		t.Errorf("ID: got %q, want %q", got.ID, expected.ID)
-	}
 	if got.Username != expected.Username {
 		t.Errorf("Username: got %q, want %q", got.Username, expected.Username)
 	}
@@ -327,18 +427,19 @@ func TestGetByFirebaseUID_Found(t *testing.T) {
 	}
 }
 
-func TestGetByFirebaseUID_NilAvatarURL(t *testing.T) {
+func TestGetByProviderSubject_NilAvatarURL(t *testing.T) {
 	now := time.Now().Truncate(time.Second)
 	expected := &repository.User{
-		ID:          "00000000-0000-0000-0000-000000000002",
-		FirebaseUID: "firebase-uid-2",
-		Username:    "bob",
-		AvatarURL:   nil,
-		CreatedAt:   now,
+		ID:        "00000000-0000-0000-0000-000000000002",
+		Provider:  "google",
+		Subject:   "google-sub-2",
+		Username:  "bob",
+		AvatarURL: nil,
+		CreatedAt: now,
 	}
 
 	repo := repository.NewUserRepository(&rowQuerier{user: expected})
-	got, err := repo.GetByFirebaseUID(context.Background(), "firebase-uid-2")
+	got, err := repo.GetByProviderSubject(context.Background(), "google", "google-sub-2")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -350,3 +451,43 @@ func TestGetByFirebaseUID_NilAvatarURL(t *testing.T) {
 		t.Errorf("expected nil AvatarURL, got %q", *got.AvatarURL)
 	}
 }
+
+// ─── GetByID tests ────────────────────────────────────────────────────────────
+
+func TestGetByID_NotFound(t *testing.T) {
+	repo := repository.NewUserRepository(&rowQuerier{user: nil})
+
+	user, err := repo.GetByID(context.Background(), "00000000-0000-0000-0000-000000000099")
+
+	if err != nil {
+		t.Fatalf("expected nil error for not-found, got: %v", err)
+	}
+	if user != nil {
+		t.Errorf("expected nil user, got: %+v", user)
+	}
+}
+
+func TestGetByID_Found(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	expected := &repository.User{
+		ID:        "00000000-0000-0000-0000-000000000003",
+		Provider:  "firebase",
+		Subject:   "firebase-uid-3",
+		Username:  "carol",
+		AvatarURL: nil,
+		CreatedAt: now,
+	}
+
+	repo := repository.NewUserRepository(&rowQuerier{user: expected})
+	got, err := repo.GetByID(context.Background(), expected.ID)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected non-nil user, got nil")
+	}
+	if got.Username != expected.Username {
+		t.Errorf("Username: got %q, want %q", got.Username, expected.Username)
+	}
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MemoryCache is an in-memory Cache, for local dev and tests that don't need
+// a real Redis connection. It mirrors MemoryStore's role for Store.
+type MemoryCache struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time // session ID -> revoked-until
+}
+
+// NewMemoryCache constructs an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{revoked: make(map[string]time.Time)}
+}
+
+func (c *MemoryCache) IsRevoked(_ context.Context, sessionID string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	until, ok := c.revoked[sessionID]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(until) {
+		delete(c.revoked, sessionID)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (c *MemoryCache) Revoke(_ context.Context, sessionID string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.revoked[sessionID] = time.Now().Add(ttl)
+	return nil
+}
+
+// RedisCache is the production Cache backed by Redis. A revoked session is
+// stored as a key that expires on its own after ttl, so Redis needs no
+// separate cleanup pass.
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache constructs a RedisCache backed by client.
+func NewRedisCache(client *redis.Client) *RedisCache {
+	return &RedisCache{client: client}
+}
+
+func (c *RedisCache) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	n, err := c.client.Exists(ctx, revokedKey(sessionID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis cache: check revocation: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (c *RedisCache) Revoke(ctx context.Context, sessionID string, ttl time.Duration) error {
+	if err := c.client.Set(ctx, revokedKey(sessionID), "1", ttl).Err(); err != nil {
+		return fmt.Errorf("redis cache: revoke: %w", err)
+	}
+	return nil
+}
+
+// revokedKey namespaces revocation entries so the session cache can share a
+// Redis instance with other subsystems without key collisions.
+func revokedKey(sessionID string) string {
+	return "session:revoked:" + sessionID
+}
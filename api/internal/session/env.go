@@ -0,0 +1,58 @@
+package session
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ManagerFromEnv builds a Manager backed by a PostgresStore over db and,
+// when REDIS_ADDR is set, a RedisCache for fast revocation checks.
+//
+//	SESSION_SIGNING_KEY — required. HMAC key used to sign access tokens.
+//	REDIS_ADDR          — optional. "host:port" of a Redis instance used as
+//	                       the revocation Cache. When unset, Manager falls
+//	                       back to trusting HMAC-valid access tokens until
+//	                       their own (short) expiry — see Manager.Logout.
+//	SESSION_ACCESS_TTL  — optional Go duration string, default 15m.
+//	SESSION_REFRESH_TTL — optional Go duration string, default 720h (30d).
+func ManagerFromEnv(db *sql.DB) (*Manager, error) {
+	key := os.Getenv("SESSION_SIGNING_KEY")
+	if key == "" {
+		return nil, fmt.Errorf("session: SESSION_SIGNING_KEY env var is not set")
+	}
+
+	accessTTL, err := durationFromEnv("SESSION_ACCESS_TTL", defaultAccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	refreshTTL, err := durationFromEnv("SESSION_REFRESH_TTL", defaultRefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	opts := []ManagerOption{WithAccessTokenTTL(accessTTL), WithRefreshTokenTTL(refreshTTL)}
+
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		client := redis.NewClient(&redis.Options{Addr: addr})
+		opts = append(opts, WithCache(NewRedisCache(client)))
+	}
+
+	return NewManager(NewPostgresStore(db), []byte(key), opts...), nil
+}
+
+// durationFromEnv parses the Go duration string in the named env var,
+// falling back to fallback when the variable is unset.
+func durationFromEnv(name string, fallback time.Duration) (time.Duration, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("session: parse %s: %w", name, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,242 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultAccessTokenTTL and defaultRefreshTokenTTL bound how long issued
+// tokens remain valid when the caller does not override them via
+// NewManager's opts.
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Cache provides a fast, optional revocation check for access tokens so the
+// middleware fast path doesn't need a Postgres round trip on every request.
+// When Manager has no Cache configured, an HMAC-valid, unexpired access
+// token is trusted until its own expiry — logout still works, it just takes
+// effect no later than the access token's (short) remaining lifetime.
+type Cache interface {
+	// IsRevoked reports whether sessionID was revoked before ttl elapsed.
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+	// Revoke marks sessionID revoked for ttl, after which the cache entry
+	// may expire (the session row is deleted from Store regardless).
+	Revoke(ctx context.Context, sessionID string, ttl time.Duration) error
+}
+
+// IssuedTokens is returned on successful exchange or refresh: a short-lived
+// access token for the request hot path and a rotating opaque refresh token.
+type IssuedTokens struct {
+	SessionID        string
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// Manager issues, authenticates, refreshes, and revokes Sessions. It is the
+// single entry point handler.NewExchangeHandler and friends use, so neither
+// the signing key nor Store ever need to leak into the handler package.
+type Manager struct {
+	store      Store
+	cache      Cache
+	signingKey []byte
+
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// ManagerOption customizes a Manager constructed by NewManager.
+type ManagerOption func(*Manager)
+
+// WithCache attaches a revocation Cache (e.g. Redis-backed) to the Manager.
+func WithCache(c Cache) ManagerOption {
+	return func(m *Manager) { m.cache = c }
+}
+
+// WithAccessTokenTTL overrides defaultAccessTokenTTL.
+func WithAccessTokenTTL(ttl time.Duration) ManagerOption {
+	return func(m *Manager) { m.accessTTL = ttl }
+}
+
+// WithRefreshTokenTTL overrides defaultRefreshTokenTTL.
+func WithRefreshTokenTTL(ttl time.Duration) ManagerOption {
+	return func(m *Manager) { m.refreshTTL = ttl }
+}
+
+// NewManager constructs a Manager backed by store and signingKey. signingKey
+// is the HMAC key used to sign and verify access tokens; callers typically
+// read it from the SESSION_SIGNING_KEY environment variable (see
+// ManagerFromEnv).
+func NewManager(store Store, signingKey []byte, opts ...ManagerOption) *Manager {
+	m := &Manager{
+		store:      store,
+		signingKey: signingKey,
+		accessTTL:  defaultAccessTokenTTL,
+		refreshTTL: defaultRefreshTokenTTL,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Exchange creates a new Session for (userID, provider) and returns its
+// initial access/refresh token pair. Called once per device/browser, after
+// the caller has already verified an upstream Firebase/OIDC ID token and
+// resolved it to an internal user row.
+func (m *Manager) Exchange(ctx context.Context, userID, provider, email string, r *http.Request) (*IssuedTokens, error) {
+	sessionID, err := newOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("session exchange: %w", err)
+	}
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("session exchange: %w", err)
+	}
+
+	now := time.Now()
+	sess := &Session{
+		ID:               sessionID,
+		UserID:           userID,
+		Provider:         provider,
+		Email:            email,
+		IssuedAt:         now,
+		ExpiresAt:        now.Add(m.refreshTTL),
+		LastSeen:         now,
+		UserAgent:        r.UserAgent(),
+		IP:               clientIP(r),
+		RefreshTokenHash: hashRefreshToken(refreshToken),
+	}
+	if err := m.store.Create(ctx, sess); err != nil {
+		return nil, fmt.Errorf("session exchange: %w", err)
+	}
+
+	return m.issueTokens(sess, refreshToken)
+}
+
+// Refresh rotates the refresh token for the session it names and returns a
+// fresh access/refresh token pair. The presented refreshToken stops working
+// once UpdateRefreshToken has replaced its hash; a call that fails before
+// that (expired session, token generation or store error) leaves it valid.
+func (m *Manager) Refresh(ctx context.Context, refreshToken string, r *http.Request) (*IssuedTokens, error) {
+	sess, err := m.store.GetByRefreshTokenHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("session refresh: %w", err)
+	}
+	if time.Now().After(sess.ExpiresAt) {
+		return nil, fmt.Errorf("session refresh: session %s expired", sess.ID)
+	}
+
+	newRefreshToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("session refresh: %w", err)
+	}
+	newExpiresAt := time.Now().Add(m.refreshTTL)
+	if err := m.store.UpdateRefreshToken(ctx, sess.ID, hashRefreshToken(newRefreshToken), newExpiresAt); err != nil {
+		return nil, fmt.Errorf("session refresh: %w", err)
+	}
+	sess.ExpiresAt = newExpiresAt
+	sess.UserAgent = r.UserAgent()
+	sess.IP = clientIP(r)
+	_ = m.store.Touch(ctx, sess.ID, time.Now())
+
+	return m.issueTokens(sess, newRefreshToken)
+}
+
+// Authenticate verifies an access token's HMAC signature and expiry, then
+// (when a Cache is configured) checks that its session hasn't been revoked
+// since it was issued. This is the fast path: it never queries Store.
+func (m *Manager) Authenticate(ctx context.Context, accessToken string) (*Session, error) {
+	claims, err := verifyAccessToken(m.signingKey, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("session authenticate: %w", err)
+	}
+
+	if m.cache != nil {
+		revoked, err := m.cache.IsRevoked(ctx, claims.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("session authenticate: check revocation: %w", err)
+		}
+		if revoked {
+			return nil, fmt.Errorf("session authenticate: session %s was revoked", claims.SessionID)
+		}
+	}
+
+	return &Session{
+		ID:        claims.SessionID,
+		UserID:    claims.UserID,
+		Provider:  claims.Provider,
+		Email:     claims.Email,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// Logout deletes sessionID from Store and, when a Cache is configured, marks
+// it revoked for the remainder of its access token's lifetime so the fast
+// path stops trusting already-issued access tokens immediately rather than
+// waiting out their TTL.
+func (m *Manager) Logout(ctx context.Context, sessionID string) error {
+	if m.cache != nil {
+		if err := m.cache.Revoke(ctx, sessionID, m.accessTTL); err != nil {
+			return fmt.Errorf("session logout: revoke in cache: %w", err)
+		}
+	}
+	if err := m.store.Delete(ctx, sessionID); err != nil {
+		return fmt.Errorf("session logout: %w", err)
+	}
+	return nil
+}
+
+// ListSessions returns every stored session for userID, for
+// GET /api/auth/sessions. Sessions past ExpiresAt are not filtered out.
+func (m *Manager) ListSessions(ctx context.Context, userID string) ([]*Session, error) {
+	sessions, err := m.store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// issueTokens signs a fresh access token for sess and pairs it with
+// refreshToken into an IssuedTokens.
+func (m *Manager) issueTokens(sess *Session, refreshToken string) (*IssuedTokens, error) {
+	accessExpiresAt := time.Now().Add(m.accessTTL)
+	accessToken, err := signAccessToken(m.signingKey, accessTokenClaims{
+		SessionID: sess.ID,
+		UserID:    sess.UserID,
+		Provider:  sess.Provider,
+		Email:     sess.Email,
+		ExpiresAt: accessExpiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sign access token: %w", err)
+	}
+
+	return &IssuedTokens{
+		SessionID:        sess.ID,
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: sess.ExpiresAt,
+	}, nil
+}
+
+// clientIP extracts the caller's address for audit purposes, preferring the
+// first hop recorded in X-Forwarded-For (as set by Cloud Run's load
+// balancer) over RemoteAddr, which on Cloud Run names the proxy, not the
+// client.
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		if first, _, ok := strings.Cut(fwd, ","); ok {
+			return strings.TrimSpace(first)
+		}
+		return strings.TrimSpace(fwd)
+	}
+	return r.RemoteAddr
+}
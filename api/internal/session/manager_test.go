@@ -0,0 +1,180 @@
+package session_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/api/internal/session"
+)
+
+func newTestManager(opts ...session.ManagerOption) *session.Manager {
+	return session.NewManager(session.NewMemoryStore(), []byte("test-signing-key"), opts...)
+}
+
+func TestManager_Exchange_IssuesTokens(t *testing.T) {
+	m := newTestManager()
+	r := httptest.NewRequest("POST", "/api/auth/exchange", nil)
+
+	tokens, err := m.Exchange(context.Background(), "user-1", "firebase", "a@b.com", r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatal("expected non-empty access and refresh tokens")
+	}
+	if tokens.AccessToken == tokens.RefreshToken {
+		t.Error("access and refresh tokens must differ")
+	}
+}
+
+func TestManager_Authenticate_ValidAccessToken(t *testing.T) {
+	m := newTestManager()
+	r := httptest.NewRequest("POST", "/api/auth/exchange", nil)
+
+	tokens, err := m.Exchange(context.Background(), "user-1", "firebase", "a@b.com", r)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	sess, err := m.Authenticate(context.Background(), tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if sess.UserID != "user-1" {
+		t.Errorf("UserID: got %q, want %q", sess.UserID, "user-1")
+	}
+	if sess.Email != "a@b.com" {
+		t.Errorf("Email: got %q, want %q", sess.Email, "a@b.com")
+	}
+}
+
+func TestManager_Authenticate_RejectsTamperedToken(t *testing.T) {
+	m := newTestManager()
+	r := httptest.NewRequest("POST", "/api/auth/exchange", nil)
+
+	tokens, err := m.Exchange(context.Background(), "user-1", "firebase", "a@b.com", r)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if _, err := m.Authenticate(context.Background(), tokens.AccessToken+"tampered"); err == nil {
+		t.Error("expected error authenticating a tampered access token")
+	}
+}
+
+func TestManager_Authenticate_RejectsExpiredToken(t *testing.T) {
+	m := newTestManager(session.WithAccessTokenTTL(-time.Minute))
+	r := httptest.NewRequest("POST", "/api/auth/exchange", nil)
+
+	tokens, err := m.Exchange(context.Background(), "user-1", "firebase", "a@b.com", r)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if _, err := m.Authenticate(context.Background(), tokens.AccessToken); err == nil {
+		t.Error("expected error authenticating an already-expired access token")
+	}
+}
+
+func TestManager_Refresh_RotatesRefreshToken(t *testing.T) {
+	m := newTestManager()
+	r := httptest.NewRequest("POST", "/api/auth/exchange", nil)
+
+	first, err := m.Exchange(context.Background(), "user-1", "firebase", "a@b.com", r)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	second, err := m.Refresh(context.Background(), first.RefreshToken, r)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("expected a new refresh token after rotation")
+	}
+	if second.SessionID != first.SessionID {
+		t.Error("expected the same session ID to persist across refresh")
+	}
+
+	if _, err := m.Refresh(context.Background(), first.RefreshToken, r); err == nil {
+		t.Error("expected the old refresh token to be rejected after rotation")
+	}
+}
+
+func TestManager_Refresh_UnknownToken(t *testing.T) {
+	m := newTestManager()
+	r := httptest.NewRequest("POST", "/api/auth/refresh", nil)
+
+	if _, err := m.Refresh(context.Background(), "does-not-exist", r); err == nil {
+		t.Error("expected error refreshing an unknown refresh token")
+	}
+}
+
+func TestManager_Logout_InvalidatesSessionEvenWithoutCache(t *testing.T) {
+	m := newTestManager()
+	r := httptest.NewRequest("POST", "/api/auth/exchange", nil)
+
+	tokens, err := m.Exchange(context.Background(), "user-1", "firebase", "a@b.com", r)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if err := m.Logout(context.Background(), tokens.SessionID); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+
+	// Without a Cache, Manager can't revoke an already-issued access token
+	// before its own expiry — it only guarantees the refresh token (and
+	// hence further session renewal) is dead.
+	if _, err := m.Refresh(context.Background(), tokens.RefreshToken, r); err == nil {
+		t.Error("expected refresh to fail after logout")
+	}
+}
+
+func TestManager_Logout_WithCache_RevokesAccessTokenImmediately(t *testing.T) {
+	cache := session.NewMemoryCache()
+	m := newTestManager(session.WithCache(cache))
+	r := httptest.NewRequest("POST", "/api/auth/exchange", nil)
+
+	tokens, err := m.Exchange(context.Background(), "user-1", "firebase", "a@b.com", r)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if _, err := m.Authenticate(context.Background(), tokens.AccessToken); err != nil {
+		t.Fatalf("authenticate before logout: %v", err)
+	}
+
+	if err := m.Logout(context.Background(), tokens.SessionID); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+
+	if _, err := m.Authenticate(context.Background(), tokens.AccessToken); err == nil {
+		t.Error("expected authenticate to fail for a revoked session once a Cache is configured")
+	}
+}
+
+func TestManager_ListSessions_ReturnsOnlyThatUsersSessions(t *testing.T) {
+	m := newTestManager()
+	r := httptest.NewRequest("POST", "/api/auth/exchange", nil)
+
+	if _, err := m.Exchange(context.Background(), "user-1", "firebase", "a@b.com", r); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if _, err := m.Exchange(context.Background(), "user-1", "google", "a@b.com", r); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if _, err := m.Exchange(context.Background(), "user-2", "firebase", "c@d.com", r); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	sessions, err := m.ListSessions(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("list sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions for user-1, got %d", len(sessions))
+	}
+}
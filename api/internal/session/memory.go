@@ -0,0 +1,104 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store, for local dev and tests that don't need
+// a real Postgres connection.
+type MemoryStore struct {
+	mu        sync.Mutex
+	byID      map[string]*Session
+	byRefresh map[string]string // refresh token hash -> session ID
+}
+
+// NewMemoryStore constructs an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		byID:      make(map[string]*Session),
+		byRefresh: make(map[string]string),
+	}
+}
+
+func (m *MemoryStore) Create(_ context.Context, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *s
+	m.byID[s.ID] = &cp
+	m.byRefresh[s.RefreshTokenHash] = s.ID
+	return nil
+}
+
+func (m *MemoryStore) Get(_ context.Context, sessionID string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.byID[sessionID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (m *MemoryStore) GetByRefreshTokenHash(_ context.Context, hash string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, ok := m.byRefresh[hash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *m.byID[id]
+	return &cp, nil
+}
+
+func (m *MemoryStore) UpdateRefreshToken(_ context.Context, sessionID, hash string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.byID[sessionID]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(m.byRefresh, s.RefreshTokenHash)
+	s.RefreshTokenHash = hash
+	s.ExpiresAt = expiresAt
+	m.byRefresh[hash] = sessionID
+	return nil
+}
+
+func (m *MemoryStore) Touch(_ context.Context, sessionID string, lastSeen time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.byID[sessionID]
+	if !ok {
+		return ErrNotFound
+	}
+	s.LastSeen = lastSeen
+	return nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.byID[sessionID]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(m.byRefresh, s.RefreshTokenHash)
+	delete(m.byID, sessionID)
+	return nil
+}
+
+func (m *MemoryStore) ListByUser(_ context.Context, userID string) ([]*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*Session
+	for _, s := range m.byID {
+		if s.UserID == userID {
+			cp := *s
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
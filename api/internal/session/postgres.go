@@ -0,0 +1,143 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Querier is the database interface used by PostgresStore. Satisfied by
+// *sql.DB and allows tests to inject a stub, mirroring
+// repository.UserQuerier.
+type Querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// PostgresStore is the production Store backed by the sessions table.
+type PostgresStore struct {
+	db Querier
+}
+
+// NewPostgresStore constructs a PostgresStore backed by db.
+func NewPostgresStore(db Querier) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Create(ctx context.Context, sess *Session) error {
+	const insertSQL = `
+INSERT INTO sessions (session_id, user_id, provider, issued_at, expires_at, last_seen, user_agent, ip, refresh_token_hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := s.db.ExecContext(ctx, insertSQL,
+		sess.ID, sess.UserID, sess.Provider, sess.IssuedAt, sess.ExpiresAt, sess.LastSeen,
+		sess.UserAgent, sess.IP, sess.RefreshTokenHash)
+	if err != nil {
+		return fmt.Errorf("create session: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Get(ctx context.Context, sessionID string) (*Session, error) {
+	const selectSQL = `
+SELECT session_id, user_id, provider, issued_at, expires_at, last_seen, user_agent, ip, refresh_token_hash
+FROM   sessions
+WHERE  session_id = $1`
+
+	return s.scanOne(s.db.QueryRowContext(ctx, selectSQL, sessionID))
+}
+
+func (s *PostgresStore) GetByRefreshTokenHash(ctx context.Context, hash string) (*Session, error) {
+	const selectSQL = `
+SELECT session_id, user_id, provider, issued_at, expires_at, last_seen, user_agent, ip, refresh_token_hash
+FROM   sessions
+WHERE  refresh_token_hash = $1`
+
+	return s.scanOne(s.db.QueryRowContext(ctx, selectSQL, hash))
+}
+
+func (s *PostgresStore) UpdateRefreshToken(ctx context.Context, sessionID, hash string, expiresAt time.Time) error {
+	const updateSQL = `
+UPDATE sessions
+SET    refresh_token_hash = $2, expires_at = $3
+WHERE  session_id = $1`
+
+	res, err := s.db.ExecContext(ctx, updateSQL, sessionID, hash, expiresAt)
+	if err != nil {
+		return fmt.Errorf("update session refresh token: %w", err)
+	}
+	return checkRowAffected(res)
+}
+
+func (s *PostgresStore) Touch(ctx context.Context, sessionID string, lastSeen time.Time) error {
+	const updateSQL = `UPDATE sessions SET last_seen = $2 WHERE session_id = $1`
+
+	res, err := s.db.ExecContext(ctx, updateSQL, sessionID, lastSeen)
+	if err != nil {
+		return fmt.Errorf("touch session: %w", err)
+	}
+	return checkRowAffected(res)
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, sessionID string) error {
+	const deleteSQL = `DELETE FROM sessions WHERE session_id = $1`
+
+	res, err := s.db.ExecContext(ctx, deleteSQL, sessionID)
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return checkRowAffected(res)
+}
+
+func (s *PostgresStore) ListByUser(ctx context.Context, userID string) ([]*Session, error) {
+	const selectSQL = `
+SELECT session_id, user_id, provider, issued_at, expires_at, last_seen, user_agent, ip, refresh_token_hash
+FROM   sessions
+WHERE  user_id = $1
+ORDER  BY issued_at DESC`
+
+	rows, err := s.db.QueryContext(ctx, selectSQL, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions for user: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Session
+	for rows.Next() {
+		var sess Session
+		if err := rows.Scan(&sess.ID, &sess.UserID, &sess.Provider, &sess.IssuedAt, &sess.ExpiresAt,
+			&sess.LastSeen, &sess.UserAgent, &sess.IP, &sess.RefreshTokenHash); err != nil {
+			return nil, fmt.Errorf("scan session row: %w", err)
+		}
+		out = append(out, &sess)
+	}
+	return out, rows.Err()
+}
+
+func (s *PostgresStore) scanOne(row *sql.Row) (*Session, error) {
+	var sess Session
+	err := row.Scan(&sess.ID, &sess.UserID, &sess.Provider, &sess.IssuedAt, &sess.ExpiresAt,
+		&sess.LastSeen, &sess.UserAgent, &sess.IP, &sess.RefreshTokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("scan session: %w", err)
+	}
+	return &sess, nil
+}
+
+// checkRowAffected returns ErrNotFound when res reports zero rows affected.
+func checkRowAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package session implements server-side session tokens: a short-lived
+// signed access token for the request hot path and a rotating opaque
+// refresh token backed by a Store, so /api/me and friends don't need to
+// re-verify an upstream Firebase/OIDC ID token on every request.
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Store lookups that find no matching session.
+var ErrNotFound = errors.New("session: not found")
+
+// Session is a single logged-in session, one row per device/browser the
+// user is signed into.
+type Session struct {
+	ID        string
+	UserID    string
+	Provider  string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	LastSeen  time.Time
+	UserAgent string
+	IP        string
+
+	// Email is carried on the signed access token so the middleware fast
+	// path can skip a user lookup entirely. It is not a sessions table
+	// column and is left zero-value on Sessions loaded from Store (e.g. via
+	// ListByUser).
+	Email string
+
+	// RefreshTokenHash is the SHA-256 hash of the current refresh token.
+	// The refresh token itself is never persisted.
+	RefreshTokenHash string
+}
+
+// Store persists Sessions. Implementations must be safe for concurrent use.
+// The split from the user-identity persistence in api/internal/repository
+// mirrors how similar Go auth services keep token state separate from user
+// state, so either can be swapped (e.g. Store backed by Redis instead of
+// Postgres) without touching the other.
+type Store interface {
+	Create(ctx context.Context, s *Session) error
+	Get(ctx context.Context, sessionID string) (*Session, error)
+	GetByRefreshTokenHash(ctx context.Context, hash string) (*Session, error)
+	UpdateRefreshToken(ctx context.Context, sessionID, hash string, expiresAt time.Time) error
+	Touch(ctx context.Context, sessionID string, lastSeen time.Time) error
+	Delete(ctx context.Context, sessionID string) error
+	ListByUser(ctx context.Context, userID string) ([]*Session, error)
+}
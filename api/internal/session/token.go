@@ -0,0 +1,106 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// accessTokenPrefix identifies the compact token format produced by
+// signAccessToken, so the middleware fast path can recognize a session
+// access token and skip upstream ID-token verification entirely.
+const accessTokenPrefix = "mts1."
+
+// IsAccessToken reports whether token looks like a session access token
+// produced by signAccessToken, as opposed to an upstream Firebase/OIDC ID
+// token. It does not verify the signature — callers must still call
+// Manager.Authenticate (or verifyAccessToken) before trusting the token.
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, accessTokenPrefix)
+}
+
+// accessTokenClaims is the payload embedded in a signed access token.
+type accessTokenClaims struct {
+	SessionID string `json:"sid"`
+	UserID    string `json:"uid"`
+	Provider  string `json:"provider"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// signAccessToken produces a compact "mts1.<payload>.<signature>" token:
+// payload and signature are base64url, signature is HMAC-SHA256 over the
+// payload segment keyed by key. Like infra/transcoder-trigger's
+// signJWTAssertion (which hand-rolls RS256 JWT signing over base64url
+// segments), it is built on the standard library rather than a JWT library.
+func signAccessToken(key []byte, claims accessTokenClaims) (string, error) {
+	payloadJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("marshal access token claims: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(payloadJSON)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	sig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	return accessTokenPrefix + payload + "." + sig, nil
+}
+
+// verifyAccessToken checks the HMAC signature and expiry of a token produced
+// by signAccessToken.
+func verifyAccessToken(key []byte, token string) (accessTokenClaims, error) {
+	rest := strings.TrimPrefix(token, accessTokenPrefix)
+	if rest == token {
+		return accessTokenClaims{}, fmt.Errorf("not a session access token")
+	}
+
+	parts := strings.SplitN(rest, ".", 2)
+	if len(parts) != 2 {
+		return accessTokenClaims{}, fmt.Errorf("malformed access token")
+	}
+	payload, sig := parts[0], parts[1]
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	wantSig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if subtle.ConstantTimeCompare([]byte(sig), []byte(wantSig)) != 1 {
+		return accessTokenClaims{}, fmt.Errorf("invalid access token signature")
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return accessTokenClaims{}, fmt.Errorf("decode access token payload: %w", err)
+	}
+	var claims accessTokenClaims
+	if err := json.Unmarshal(payloadJSON, &claims); err != nil {
+		return accessTokenClaims{}, fmt.Errorf("parse access token payload: %w", err)
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return accessTokenClaims{}, fmt.Errorf("access token expired at %d", claims.ExpiresAt)
+	}
+	return claims, nil
+}
+
+// newOpaqueToken returns a random 256-bit, base64url-encoded token, used for
+// both session IDs and refresh tokens.
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken returns the SHA-256 hash of a refresh token, base64url
+// encoded, so the token value itself never needs to be stored at rest.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
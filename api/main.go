@@ -2,23 +2,22 @@ package main
 
 import (
 	"context"
-	"embed"
-	"io/fs"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 
 	"github.com/ai-teammate/mytube/api/internal/auth"
 	"github.com/ai-teammate/mytube/api/internal/database"
+	"github.com/ai-teammate/mytube/api/internal/events"
 	"github.com/ai-teammate/mytube/api/internal/handler"
 	"github.com/ai-teammate/mytube/api/internal/middleware"
 	"github.com/ai-teammate/mytube/api/internal/migration"
 	"github.com/ai-teammate/mytube/api/internal/repository"
+	"github.com/ai-teammate/mytube/api/internal/session"
+	"github.com/ai-teammate/mytube/api/migrations"
 )
 
-//go:embed migrations/*.sql
-var rawMigrationsFS embed.FS
-
 func main() {
 	ctx := context.Background()
 
@@ -27,27 +26,41 @@ func main() {
 		log.Fatalf("db open: %v", err)
 	}
 
-	// Sub-FS so that the root of migrationsFS contains *.sql directly.
-	migrationsFS, err := fs.Sub(rawMigrationsFS, "migrations")
-	if err != nil {
-		log.Fatalf("migrations sub-fs: %v", err)
-	}
-
-	if err := migration.RunMigrations(db, migrationsFS.(fs.ReadDirFS)); err != nil {
+	if err := migration.RunMigrations(db, migrations.FS); err != nil {
 		log.Fatalf("migrate: %v", err)
 	}
 
-	verifier, err := auth.NewFirebaseVerifier(ctx)
+	verifier, err := auth.FromEnv(ctx)
 	if err != nil {
-		log.Fatalf("firebase verifier: %v", err)
+		log.Fatalf("auth providers: %v", err)
 	}
 
 	userRepo := repository.NewUserRepository(db)
-	authMiddleware := middleware.RequireAuth(verifier)
+
+	sessions, err := session.ManagerFromEnv(db)
+	if err != nil {
+		log.Fatalf("session manager: %v", err)
+	}
+	authMiddleware := middleware.RequireAuthOrSession(verifier, sessions)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", handler.NewHealthHandler(db))
 	mux.Handle("/api/me", authMiddleware(handler.NewMeHandler(userRepo)))
+
+	mux.HandleFunc("/api/auth/exchange", handler.NewExchangeHandler(verifier, userRepo, sessions))
+	mux.HandleFunc("/api/auth/refresh", handler.NewRefreshHandler(sessions))
+	mux.Handle("/api/auth/logout", authMiddleware(handler.NewLogoutHandler(sessions)))
+	mux.Handle("/api/auth/sessions", authMiddleware(handler.NewSessionsHandler(sessions)))
+
+	// The events subscription is optional: if EVENTS_SUBSCRIPTION is unset
+	// (e.g. in environments without Pub/Sub configured), the SSE endpoint is
+	// simply not registered rather than serving broken responses.
+	if eventSub, err := events.NewSubscriberFromEnv(ctx); err != nil {
+		log.Printf("events subscriber not configured, skipping /api/videos/{id}/events: %v", err)
+	} else {
+		mux.Handle("/api/videos/", authMiddleware(handler.NewEventsHandler(eventSub)))
+	}
+
 	// Catch-all: return 404 for any path not matched above.
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.NotFound(w, r)
@@ -57,6 +70,7 @@ func main() {
 	if port == "" {
 		port = "8080"
 	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	log.Printf("listening on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	log.Fatal(http.ListenAndServe(":"+port, middleware.RequestLogger(logger)(mux)))
 }
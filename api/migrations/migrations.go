@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL files in this directory so both the
+// production binary (api/main.go) and test harnesses
+// (internal/repository/repositorytest) can apply the same migrations
+// without each keeping their own go:embed directive in sync.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -1,15 +1,39 @@
 // Package event parses CloudEvent payloads sent by Eventarc for GCS object
-// finalization events.
+// finalization events, across every envelope shape Eventarc/Pub/Sub can
+// deliver: the legacy flat "Google events" body, the CloudEvents 1.0
+// structured content mode, and the CloudEvents 1.0 binary content mode —
+// either as direct ce-* HTTP headers or, when a Pub/Sub push subscription
+// sits in front of the trigger, as ce-* Pub/Sub message attributes with the
+// StorageObject JSON base64-encoded in the message data.
 package event
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"path"
 	"strings"
 )
 
+// cloudEventTypeFinalized is the ce-type value Eventarc sets for a GCS
+// object-finalize notification, whether carried in a header, a Pub/Sub
+// message attribute, or a structured CloudEvent's "type" field.
+const cloudEventTypeFinalized = "google.cloud.storage.object.v1.finalized"
+
+// cloudEventsContentType is the Content-Type header value identifying the
+// CloudEvents 1.0 structured content mode.
+const cloudEventsContentType = "application/cloudevents+json"
+
+// ErrUnsupportedEventType is returned when an event's type names something
+// other than an object-finalize notification (e.g. a delete or
+// metadata-update event), so the caller can ack it with 204 rather than
+// treat it as a parse failure.
+var ErrUnsupportedEventType = errors.New("event: unsupported CloudEvent type")
+
 // StorageObject holds the subset of GCS object metadata that the trigger
 // needs from an Eventarc google.cloud.storage.object.v1.finalized payload.
 type StorageObject struct {
@@ -17,6 +41,18 @@ type StorageObject struct {
 	Bucket string `json:"bucket"`
 	// Name is the GCS object path (e.g. "raw/<uuid>.mp4").
 	Name string `json:"name"`
+	// Generation is the GCS object generation number. It changes on every
+	// overwrite of the same object path, which combined with Bucket and
+	// Name makes IdempotencyKey unique per underlying upload rather than
+	// per path. Optional, since older events predate this field.
+	Generation string `json:"generation"`
+}
+
+// IdempotencyKey returns a key identifying this exact object generation,
+// stable across repeated at-least-once Eventarc deliveries of the same
+// finalize event but distinct across re-uploads of the same object path.
+func (o StorageObject) IdempotencyKey() string {
+	return o.Bucket + "/" + o.Name + "#" + o.Generation
 }
 
 // VideoID extracts the video UUID from the object name.
@@ -36,6 +72,39 @@ func (o StorageObject) VideoID() (string, error) {
 	return id, nil
 }
 
+// ObjectFinalizeEvent is the common representation every envelope shape
+// ParseRequest understands is decoded into, so the handler doesn't need to
+// know which shape a given delivery used. The CE* fields are zero-valued for
+// the legacy envelope, which carries no CloudEvent metadata.
+type ObjectFinalizeEvent struct {
+	StorageObject
+
+	// CEID is the CloudEvents ce-id: Eventarc sets it deterministically per
+	// underlying GCS notification, making it usable as an idempotency key
+	// in place of StorageObject.IdempotencyKey.
+	CEID string
+	// CESource is the CloudEvents ce-source, e.g.
+	// "//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads".
+	CESource string
+	// CEType is the CloudEvents ce-type. Always cloudEventTypeFinalized: a
+	// different value fails parsing with ErrUnsupportedEventType.
+	CEType string
+	// CESubject is the CloudEvents ce-subject, e.g. "objects/raw/<uuid>.mp4".
+	CESubject string
+	// CETime is the CloudEvents ce-time, RFC3339-formatted.
+	CETime string
+}
+
+// IdempotencyKey returns evt.CEID when this event carried CloudEvent
+// metadata, falling back to the embedded StorageObject's generation-based
+// key for the legacy envelope, which has no ce-id.
+func (evt ObjectFinalizeEvent) IdempotencyKey() string {
+	if evt.CEID != "" {
+		return evt.CEID
+	}
+	return evt.StorageObject.IdempotencyKey()
+}
+
 // Parse decodes a JSON-encoded GCS StorageObject from r.
 func Parse(r io.Reader) (StorageObject, error) {
 	var obj StorageObject
@@ -50,3 +119,148 @@ func Parse(r io.Reader) (StorageObject, error) {
 	}
 	return obj, nil
 }
+
+// ParseRequest decodes body as whichever envelope shape r carries:
+//
+//  1. A structured CloudEvent (Content-Type: application/cloudevents+json),
+//     the GCS StorageObject nested under the envelope's "data" field.
+//  2. A Pub/Sub push request (a top-level "message" field), the binary
+//     CloudEvents content mode's ce-* attributes on message.attributes and
+//     the StorageObject JSON base64-encoded in message.data.
+//  3. The CloudEvents 1.0 binary content mode delivered directly: ce-*
+//     HTTP headers, the StorageObject JSON as the body.
+//  4. The legacy flat Eventarc "Google events" envelope: just the
+//     StorageObject JSON as the body.
+//
+// It returns ErrUnsupportedEventType if a CloudEvent's type isn't an
+// object-finalize notification, so the caller can ack it with 204 rather
+// than treat it as a parse failure.
+func ParseRequest(r *http.Request, body []byte) (ObjectFinalizeEvent, error) {
+	if ct := r.Header.Get("Content-Type"); strings.HasPrefix(ct, cloudEventsContentType) {
+		return parseStructuredCloudEvent(body)
+	}
+	if msg, ok := decodePubSubPush(body); ok {
+		data, err := base64.StdEncoding.DecodeString(msg.Data)
+		if err != nil {
+			return ObjectFinalizeEvent{}, fmt.Errorf("decode pubsub message data: %w", err)
+		}
+		return parseBinaryCloudEvent(msg.Attributes, data)
+	}
+	if r.Header.Get("ce-specversion") != "" {
+		return parseBinaryCloudEvent(headerAttrs(r.Header), body)
+	}
+
+	obj, err := Parse(bytes.NewReader(body))
+	if err != nil {
+		return ObjectFinalizeEvent{}, err
+	}
+	return ObjectFinalizeEvent{StorageObject: obj}, nil
+}
+
+// structuredCloudEvent mirrors the fields of a CloudEvents 1.0 structured
+// JSON envelope that ParseRequest needs.
+type structuredCloudEvent struct {
+	ID      string          `json:"id"`
+	Source  string          `json:"source"`
+	Type    string          `json:"type"`
+	Subject string          `json:"subject"`
+	Time    string          `json:"time"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// parseStructuredCloudEvent decodes body as a CloudEvents 1.0 structured
+// envelope, validating its type and nested StorageObject.
+func parseStructuredCloudEvent(body []byte) (ObjectFinalizeEvent, error) {
+	var ce structuredCloudEvent
+	if err := json.Unmarshal(body, &ce); err != nil {
+		return ObjectFinalizeEvent{}, fmt.Errorf("decode structured cloud event: %w", err)
+	}
+	if ce.Type != cloudEventTypeFinalized {
+		return ObjectFinalizeEvent{}, fmt.Errorf("%w: %q", ErrUnsupportedEventType, ce.Type)
+	}
+	if ce.ID == "" {
+		return ObjectFinalizeEvent{}, fmt.Errorf("structured cloud event missing id")
+	}
+	obj, err := Parse(bytes.NewReader(ce.Data))
+	if err != nil {
+		return ObjectFinalizeEvent{}, err
+	}
+	return ObjectFinalizeEvent{
+		StorageObject: obj,
+		CEID:          ce.ID,
+		CESource:      ce.Source,
+		CEType:        ce.Type,
+		CESubject:     ce.Subject,
+		CETime:        ce.Time,
+	}, nil
+}
+
+// pubsubPushBody mirrors the fields of a Pub/Sub push subscription request
+// body that ParseRequest needs. See
+// https://cloud.google.com/pubsub/docs/push#receive_push.
+type pubsubPushBody struct {
+	Message *pubsubMessage `json:"message"`
+}
+
+type pubsubMessage struct {
+	// Data is the base64-encoded message payload: for an Eventarc trigger
+	// routed through Pub/Sub, the StorageObject JSON.
+	Data string `json:"data"`
+	// Attributes carries the binary CloudEvents content mode's ce-* keys in
+	// place of HTTP headers, since a Pub/Sub push request only has one
+	// Content-Type and one set of headers for the whole envelope.
+	Attributes map[string]string `json:"attributes"`
+}
+
+// decodePubSubPush reports whether body is a Pub/Sub push request (it has a
+// top-level "message" field) and, if so, returns its message. A body that
+// fails to unmarshal or has no "message" field is reported as ok=false so
+// the caller falls through to try the next envelope shape instead of
+// treating it as a parse error.
+func decodePubSubPush(body []byte) (pubsubMessage, bool) {
+	var wrapper pubsubPushBody
+	if err := json.Unmarshal(body, &wrapper); err != nil || wrapper.Message == nil {
+		return pubsubMessage{}, false
+	}
+	return *wrapper.Message, true
+}
+
+// headerAttrs collects the ce-* HTTP headers parseBinaryCloudEvent needs
+// into the same shape as a Pub/Sub message's attributes, so both binary
+// content mode transports share one parser.
+func headerAttrs(h http.Header) map[string]string {
+	return map[string]string{
+		"ce-id":      h.Get("ce-id"),
+		"ce-source":  h.Get("ce-source"),
+		"ce-type":    h.Get("ce-type"),
+		"ce-subject": h.Get("ce-subject"),
+		"ce-time":    h.Get("ce-time"),
+	}
+}
+
+// parseBinaryCloudEvent decodes the CloudEvents 1.0 binary content mode
+// shared by both transports it's delivered over: ce-* metadata in attrs
+// (HTTP headers or Pub/Sub message attributes), the StorageObject JSON in
+// data.
+func parseBinaryCloudEvent(attrs map[string]string, data []byte) (ObjectFinalizeEvent, error) {
+	ceType := attrs["ce-type"]
+	if ceType != cloudEventTypeFinalized {
+		return ObjectFinalizeEvent{}, fmt.Errorf("%w: %q", ErrUnsupportedEventType, ceType)
+	}
+	ceID := attrs["ce-id"]
+	if ceID == "" {
+		return ObjectFinalizeEvent{}, fmt.Errorf("cloud event missing ce-id")
+	}
+	obj, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return ObjectFinalizeEvent{}, err
+	}
+	return ObjectFinalizeEvent{
+		StorageObject: obj,
+		CEID:          ceID,
+		CESource:      attrs["ce-source"],
+		CEType:        ceType,
+		CESubject:     attrs["ce-subject"],
+		CETime:        attrs["ce-time"],
+	}, nil
+}
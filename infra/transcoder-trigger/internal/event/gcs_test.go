@@ -1,6 +1,13 @@
 package event_test
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -66,6 +73,25 @@ func TestVideoID_OnlyExtension(t *testing.T) {
 	}
 }
 
+// ── IdempotencyKey ────────────────────────────────────────────────────────────
+
+func TestIdempotencyKey_VariesByGeneration(t *testing.T) {
+	first := event.StorageObject{Bucket: "b", Name: "raw/abc.mp4", Generation: "1"}
+	second := event.StorageObject{Bucket: "b", Name: "raw/abc.mp4", Generation: "2"}
+
+	if first.IdempotencyKey() == second.IdempotencyKey() {
+		t.Errorf("expected different generations to produce different keys, got %q for both", first.IdempotencyKey())
+	}
+}
+
+func TestIdempotencyKey_StableForSameObject(t *testing.T) {
+	obj := event.StorageObject{Bucket: "b", Name: "raw/abc.mp4", Generation: "1"}
+
+	if obj.IdempotencyKey() != obj.IdempotencyKey() {
+		t.Error("expected IdempotencyKey to be deterministic")
+	}
+}
+
 // ── Parse ─────────────────────────────────────────────────────────────────────
 
 func TestParse_Valid(t *testing.T) {
@@ -82,6 +108,17 @@ func TestParse_Valid(t *testing.T) {
 	}
 }
 
+func TestParse_Generation(t *testing.T) {
+	body := `{"bucket":"mytube-raw-uploads","name":"raw/abc.mp4","generation":"1234567890"}`
+	obj, err := event.Parse(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.Generation != "1234567890" {
+		t.Errorf("unexpected generation: %q", obj.Generation)
+	}
+}
+
 func TestParse_MissingBucket(t *testing.T) {
 	body := `{"name":"raw/abc.mp4"}`
 	_, err := event.Parse(strings.NewReader(body))
@@ -111,3 +148,197 @@ func TestParse_Empty(t *testing.T) {
 		t.Fatal("expected error for empty body, got nil")
 	}
 }
+
+// ── ParseRequest: legacy envelope ─────────────────────────────────────────────
+
+func TestParseRequest_LegacyEnvelope(t *testing.T) {
+	body := []byte(validStorageObjectJSON)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	evt, err := event.ParseRequest(req, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evt.Bucket != "mytube-raw-uploads" || evt.Name != "raw/abc.mp4" {
+		t.Errorf("unexpected object: %+v", evt)
+	}
+	if evt.CEID != "" {
+		t.Errorf("expected no CloudEvent metadata, got CEID %q", evt.CEID)
+	}
+	if evt.IdempotencyKey() != evt.StorageObject.IdempotencyKey() {
+		t.Errorf("expected IdempotencyKey to fall back to the StorageObject key")
+	}
+}
+
+// ── ParseRequest: direct binary CloudEvent (ce-* headers) ────────────────────
+
+func binaryCloudEventRequest(ceType, ceID, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-type", ceType)
+	req.Header.Set("ce-source", "//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads")
+	req.Header.Set("ce-subject", "objects/raw/abc.mp4")
+	req.Header.Set("ce-time", "2026-07-29T00:00:00Z")
+	if ceID != "" {
+		req.Header.Set("ce-id", ceID)
+	}
+	return req
+}
+
+func TestParseRequest_BinaryCloudEvent_Valid(t *testing.T) {
+	req := binaryCloudEventRequest("google.cloud.storage.object.v1.finalized", "event-1", validStorageObjectJSON)
+
+	evt, err := event.ParseRequest(req, []byte(validStorageObjectJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evt.Bucket != "mytube-raw-uploads" || evt.Name != "raw/abc.mp4" {
+		t.Errorf("unexpected object: %+v", evt)
+	}
+	if evt.CEID != "event-1" {
+		t.Errorf("unexpected CEID: %q", evt.CEID)
+	}
+	if evt.CESubject != "objects/raw/abc.mp4" {
+		t.Errorf("unexpected CESubject: %q", evt.CESubject)
+	}
+	if evt.IdempotencyKey() != "event-1" {
+		t.Errorf("expected IdempotencyKey to be the ce-id, got %q", evt.IdempotencyKey())
+	}
+}
+
+func TestParseRequest_BinaryCloudEvent_UnsupportedType_ReturnsErrUnsupportedEventType(t *testing.T) {
+	req := binaryCloudEventRequest("google.cloud.storage.object.v1.deleted", "event-1", validStorageObjectJSON)
+
+	_, err := event.ParseRequest(req, []byte(validStorageObjectJSON))
+	if !errors.Is(err, event.ErrUnsupportedEventType) {
+		t.Errorf("expected ErrUnsupportedEventType, got %v", err)
+	}
+}
+
+func TestParseRequest_BinaryCloudEvent_MissingCeID_ReturnsError(t *testing.T) {
+	req := binaryCloudEventRequest("google.cloud.storage.object.v1.finalized", "", validStorageObjectJSON)
+
+	_, err := event.ParseRequest(req, []byte(validStorageObjectJSON))
+	if err == nil {
+		t.Fatal("expected error for missing ce-id, got nil")
+	}
+}
+
+func TestParseRequest_BinaryCloudEvent_InvalidBody_ReturnsError(t *testing.T) {
+	req := binaryCloudEventRequest("google.cloud.storage.object.v1.finalized", "event-1", "not-json")
+
+	_, err := event.ParseRequest(req, []byte("not-json"))
+	if err == nil {
+		t.Fatal("expected error for invalid body, got nil")
+	}
+}
+
+// ── ParseRequest: structured CloudEvent ───────────────────────────────────────
+
+func structuredCloudEventRequest(ceType, data string) *http.Request {
+	body := `{"specversion":"1.0","id":"event-2","source":"//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads",` +
+		`"type":"` + ceType + `","subject":"objects/raw/abc.mp4","time":"2026-07-29T00:00:00Z","data":` + data + `}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	return req
+}
+
+func TestParseRequest_StructuredCloudEvent_Valid(t *testing.T) {
+	req := structuredCloudEventRequest("google.cloud.storage.object.v1.finalized", validStorageObjectJSON)
+	body := readBody(t, req)
+
+	evt, err := event.ParseRequest(req, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evt.Bucket != "mytube-raw-uploads" || evt.Name != "raw/abc.mp4" {
+		t.Errorf("unexpected object: %+v", evt)
+	}
+	if evt.CEID != "event-2" {
+		t.Errorf("unexpected CEID: %q", evt.CEID)
+	}
+	if evt.CETime != "2026-07-29T00:00:00Z" {
+		t.Errorf("unexpected CETime: %q", evt.CETime)
+	}
+}
+
+func TestParseRequest_StructuredCloudEvent_UnsupportedType_ReturnsErrUnsupportedEventType(t *testing.T) {
+	req := structuredCloudEventRequest("google.cloud.storage.object.v1.deleted", validStorageObjectJSON)
+	body := readBody(t, req)
+
+	_, err := event.ParseRequest(req, body)
+	if !errors.Is(err, event.ErrUnsupportedEventType) {
+		t.Errorf("expected ErrUnsupportedEventType, got %v", err)
+	}
+}
+
+func TestParseRequest_StructuredCloudEvent_InvalidData_ReturnsError(t *testing.T) {
+	req := structuredCloudEventRequest("google.cloud.storage.object.v1.finalized", `{"name":"raw/abc.mp4"}`)
+	body := readBody(t, req)
+
+	_, err := event.ParseRequest(req, body)
+	if err == nil {
+		t.Fatal("expected error for storage object missing bucket, got nil")
+	}
+}
+
+// ── ParseRequest: Pub/Sub push binary CloudEvent ──────────────────────────────
+
+func pubsubPushRequest(ceType string) (*http.Request, []byte) {
+	data := base64.StdEncoding.EncodeToString([]byte(validStorageObjectJSON))
+	body := fmt.Sprintf(`{"message":{"data":%q,"attributes":{"ce-id":"event-3","ce-type":%q,`+
+		`"ce-source":"//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads",`+
+		`"ce-subject":"objects/raw/abc.mp4"},"messageId":"123456"},"subscription":"projects/p/subscriptions/s"}`,
+		data, ceType)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	return req, []byte(body)
+}
+
+func TestParseRequest_PubSubPush_Valid(t *testing.T) {
+	req, body := pubsubPushRequest("google.cloud.storage.object.v1.finalized")
+
+	evt, err := event.ParseRequest(req, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evt.Bucket != "mytube-raw-uploads" || evt.Name != "raw/abc.mp4" {
+		t.Errorf("unexpected object: %+v", evt)
+	}
+	if evt.CEID != "event-3" {
+		t.Errorf("unexpected CEID: %q", evt.CEID)
+	}
+}
+
+func TestParseRequest_PubSubPush_UnsupportedType_ReturnsErrUnsupportedEventType(t *testing.T) {
+	req, body := pubsubPushRequest("google.cloud.storage.object.v1.deleted")
+
+	_, err := event.ParseRequest(req, body)
+	if !errors.Is(err, event.ErrUnsupportedEventType) {
+		t.Errorf("expected ErrUnsupportedEventType, got %v", err)
+	}
+}
+
+func TestParseRequest_PubSubPush_InvalidBase64Data_ReturnsError(t *testing.T) {
+	body := `{"message":{"data":"not-base64!!!","attributes":{"ce-id":"event-3",` +
+		`"ce-type":"google.cloud.storage.object.v1.finalized"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+	_, err := event.ParseRequest(req, []byte(body))
+	if err == nil {
+		t.Fatal("expected error for invalid base64 data, got nil")
+	}
+}
+
+// ── helpers ───────────────────────────────────────────────────────────────────
+
+const validStorageObjectJSON = `{"bucket":"mytube-raw-uploads","name":"raw/abc.mp4"}`
+
+func readBody(t *testing.T, req *http.Request) []byte {
+	t.Helper()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("read request body: %v", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultDedupTTL bounds how long a delivery's dedup key is remembered, long
+// enough to cover Eventarc/Pub/Sub's at-least-once redelivery window without
+// keeping every key forever.
+const defaultDedupTTL = 24 * time.Hour
+
+// Deduper is a fast, TTL-bounded pre-check NewTriggerHandler consults before
+// store.Create's own (authoritative but slower) idempotency check, so a hot
+// duplicate redelivery can short-circuit without a round trip to Store's
+// backing database. Unlike Store, a Deduper failure is non-fatal: see
+// WithDeduper.
+type Deduper interface {
+	// SeenWithin reports whether key was marked seen less than ttl ago.
+	SeenWithin(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// MarkSeen records key as seen now, expiring after ttl.
+	MarkSeen(ctx context.Context, key string, ttl time.Duration) error
+}
+
+// defaultMemoryDeduperCapacity bounds MemoryDeduper's memory use; the oldest
+// key is evicted once it's exceeded, same trade-off as a bounded LRU cache.
+const defaultMemoryDeduperCapacity = 10000
+
+// MemoryDeduper is an in-memory, TTL-aware LRU Deduper, for local/dev and
+// tests that don't need a real Firestore or Redis connection.
+type MemoryDeduper struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // front = most recently marked seen
+}
+
+type dedupEntry struct {
+	key    string
+	seenAt time.Time
+}
+
+// NewMemoryDeduper constructs a MemoryDeduper holding at most capacity keys.
+// A non-positive capacity falls back to defaultMemoryDeduperCapacity.
+func NewMemoryDeduper(capacity int) *MemoryDeduper {
+	if capacity <= 0 {
+		capacity = defaultMemoryDeduperCapacity
+	}
+	return &MemoryDeduper{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (d *MemoryDeduper) SeenWithin(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	elem, ok := d.items[key]
+	if !ok {
+		return false, nil
+	}
+	entry := elem.Value.(*dedupEntry)
+	if time.Since(entry.seenAt) > ttl {
+		d.order.Remove(elem)
+		delete(d.items, key)
+		return false, nil
+	}
+	d.order.MoveToFront(elem)
+	return true, nil
+}
+
+func (d *MemoryDeduper) MarkSeen(_ context.Context, key string, _ time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if elem, ok := d.items[key]; ok {
+		elem.Value.(*dedupEntry).seenAt = time.Now()
+		d.order.MoveToFront(elem)
+		return nil
+	}
+
+	elem := d.order.PushFront(&dedupEntry{key: key, seenAt: time.Now()})
+	d.items[key] = elem
+
+	if d.order.Len() > d.capacity {
+		oldest := d.order.Back()
+		if oldest != nil {
+			d.order.Remove(oldest)
+			delete(d.items, oldest.Value.(*dedupEntry).key)
+		}
+	}
+	return nil
+}
+
+// WithDeduper attaches a fast dedup pre-check NewTriggerHandler consults
+// before recording the delivery in Store. On a hit it responds 204 without
+// invoking the executor and logs duplicate=true; on a Deduper error it logs
+// a warning and falls open — proceeding as if the key hadn't been seen —
+// since a broken dedup store must never cause a real upload's transcode to
+// be silently dropped. Store.Create remains the authoritative check.
+func WithDeduper(d Deduper) TriggerOption {
+	return func(c *triggerConfig) { c.dedup = d }
+}
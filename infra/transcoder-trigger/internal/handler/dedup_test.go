@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/handler"
+)
+
+func TestMemoryDeduper_FirstDelivery_IsNotSeen(t *testing.T) {
+	d := handler.NewMemoryDeduper(0)
+
+	seen, err := d.SeenWithin(context.Background(), "key-1", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen {
+		t.Error("expected an unmarked key not to be seen")
+	}
+}
+
+func TestMemoryDeduper_MarkSeen_IsSeenWithinTTL(t *testing.T) {
+	d := handler.NewMemoryDeduper(0)
+	ctx := context.Background()
+
+	if err := d.MarkSeen(ctx, "key-1", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	seen, err := d.SeenWithin(ctx, "key-1", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !seen {
+		t.Error("expected a marked key to be seen within its ttl")
+	}
+}
+
+func TestMemoryDeduper_MarkSeen_IsNotSeenAfterTTLElapses(t *testing.T) {
+	d := handler.NewMemoryDeduper(0)
+	ctx := context.Background()
+
+	if err := d.MarkSeen(ctx, "key-1", time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	seen, err := d.SeenWithin(ctx, "key-1", time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen {
+		t.Error("expected an expired key not to be seen")
+	}
+}
+
+func TestMemoryDeduper_EvictsOldestKeyPastCapacity(t *testing.T) {
+	d := handler.NewMemoryDeduper(2)
+	ctx := context.Background()
+
+	_ = d.MarkSeen(ctx, "key-1", time.Hour)
+	_ = d.MarkSeen(ctx, "key-2", time.Hour)
+	_ = d.MarkSeen(ctx, "key-3", time.Hour)
+
+	seen, _ := d.SeenWithin(ctx, "key-1", time.Hour)
+	if seen {
+		t.Error("expected the oldest key to have been evicted past capacity")
+	}
+	seen, _ = d.SeenWithin(ctx, "key-3", time.Hour)
+	if !seen {
+		t.Error("expected the most recently marked key to still be seen")
+	}
+}
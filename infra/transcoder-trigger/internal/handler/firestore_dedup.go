@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultDedupCollection is the Firestore collection FirestoreDeduper uses
+// when Collection is unset.
+const defaultDedupCollection = "transcoder-trigger-dedup"
+
+// dedupDoc is the Firestore document shape FirestoreDeduper stores, keyed by
+// the dedup key as the document ID.
+type dedupDoc struct {
+	SeenAt time.Time `firestore:"seenAt"`
+}
+
+// FirestoreDeduper is a production Deduper backed by a Firestore
+// collection, mirroring jobs.FirestoreStore's role for Store. It doesn't
+// rely on Firestore's own TTL policy for expiry: SeenWithin compares the
+// stored SeenAt against the caller-supplied ttl itself, so a shorter ttl
+// can be honored without waiting on Firestore's TTL deletion, which runs on
+// its own schedule and is only relied on here to eventually reclaim space.
+type FirestoreDeduper struct {
+	Client *firestore.Client
+	// Collection overrides defaultDedupCollection when set.
+	Collection string
+}
+
+// NewFirestoreDeduper constructs a FirestoreDeduper backed by client,
+// recording documents in collection (or defaultDedupCollection if empty).
+func NewFirestoreDeduper(client *firestore.Client, collection string) *FirestoreDeduper {
+	return &FirestoreDeduper{Client: client, Collection: collection}
+}
+
+func (d *FirestoreDeduper) collection() string {
+	if d.Collection == "" {
+		return defaultDedupCollection
+	}
+	return d.Collection
+}
+
+func (d *FirestoreDeduper) doc(key string) *firestore.DocumentRef {
+	return d.Client.Collection(d.collection()).Doc(key)
+}
+
+func (d *FirestoreDeduper) SeenWithin(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	snap, err := d.doc(key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("firestore dedup: get %q: %w", key, err)
+	}
+	var doc dedupDoc
+	if err := snap.DataTo(&doc); err != nil {
+		return false, fmt.Errorf("firestore dedup: decode %q: %w", key, err)
+	}
+	return time.Since(doc.SeenAt) <= ttl, nil
+}
+
+func (d *FirestoreDeduper) MarkSeen(ctx context.Context, key string, _ time.Duration) error {
+	_, err := d.doc(key).Set(ctx, dedupDoc{SeenAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("firestore dedup: set %q: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,373 @@
+package handler
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// googleIssuer is the "iss" claim Google stamps on ID tokens minted for
+	// Pub/Sub push's OIDC authentication.
+	googleIssuer = "https://accounts.google.com"
+	// googleJWKSURL serves the RSA keys Google signs ID tokens with.
+	googleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+	// defaultClockSkew bounds how far a token's iat/exp may drift from this
+	// server's clock before being rejected.
+	defaultClockSkew = 2 * time.Minute
+	// defaultJWKSMaxAge is how long a fetched JWKS is reused when the
+	// response carries no (or an unparsable) Cache-Control max-age.
+	defaultJWKSMaxAge = 1 * time.Hour
+)
+
+// OIDCVerifier validates the "Authorization: Bearer <jwt>" ID token Pub/Sub
+// push attaches to every request, so the trigger endpoint can reject callers
+// that aren't the configured push subscription. It hand-rolls JWT parsing
+// and RS256 verification with crypto/rsa, matching jobs.signJWTAssertion's
+// approach on the signing side of this same service.
+type OIDCVerifier struct {
+	// Audience is the expected "aud" claim — the trigger's Cloud Run
+	// service URL, as configured on the push subscription.
+	Audience string
+	// ClockSkew bounds how far a token's iat/exp may drift from this
+	// server's clock. Defaults to defaultClockSkew when zero.
+	ClockSkew time.Duration
+	// AllowedEmails, when non-empty, restricts accepted tokens to these
+	// service-account emails. Empty means any audience-matching token is
+	// accepted.
+	AllowedEmails map[string]bool
+	// JWKSURL overrides googleJWKSURL; exposed for tests.
+	JWKSURL string
+	// Client fetches the JWKS. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey // kid -> public key
+	fetchedAt time.Time
+	maxAge    time.Duration
+}
+
+// OIDCOption customizes an OIDCVerifier constructed by NewOIDCVerifier.
+type OIDCOption func(*OIDCVerifier)
+
+// WithClockSkew overrides defaultClockSkew.
+func WithClockSkew(d time.Duration) OIDCOption {
+	return func(v *OIDCVerifier) { v.ClockSkew = d }
+}
+
+// WithAllowedEmails restricts NewOIDCVerifier to only accept tokens whose
+// "email" claim is one of emails.
+func WithAllowedEmails(emails ...string) OIDCOption {
+	return func(v *OIDCVerifier) {
+		if v.AllowedEmails == nil {
+			v.AllowedEmails = make(map[string]bool, len(emails))
+		}
+		for _, e := range emails {
+			v.AllowedEmails[e] = true
+		}
+	}
+}
+
+// NewOIDCVerifier constructs an OIDCVerifier checking tokens are issued by
+// Google for audience.
+func NewOIDCVerifier(audience string, opts ...OIDCOption) *OIDCVerifier {
+	v := &OIDCVerifier{
+		Audience:  audience,
+		ClockSkew: defaultClockSkew,
+		JWKSURL:   googleJWKSURL,
+		Client:    http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// oidcClaims is the subset of standard OIDC ID token claims this package
+// checks. Unrecognised claims are ignored.
+type oidcClaims struct {
+	Issuer   string `json:"iss"`
+	Audience string `json:"aud"`
+	Email    string `json:"email"`
+	Expiry   int64  `json:"exp"`
+	IssuedAt int64  `json:"iat"`
+}
+
+// errAudienceMismatch and errEmailNotAllowed are returned by VerifyIDToken,
+// together with the decoded claims, only after the signature and iss/exp/iat
+// checks pass, so RequireOIDC can tell an unauthenticated request (401)
+// apart from an authenticated one the configured policy still rejects (403).
+var (
+	errAudienceMismatch = fmt.Errorf("token audience does not match")
+	errEmailNotAllowed  = fmt.Errorf("token email is not in the allow-list")
+)
+
+// VerifyIDToken validates idToken's RS256 signature against Google's JWKS
+// (fetched and cached per the response's Cache-Control max-age) and its
+// iss/exp/iat claims, then checks aud and, if configured, email.
+func (v *OIDCVerifier) VerifyIDToken(ctx context.Context, idToken string) (*oidcClaims, error) {
+	header, claims, signingInput, sig, err := splitOIDCToken(idToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: %w", err)
+	}
+
+	key, err := v.publicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: resolve signing key %q: %w", header.Kid, err)
+	}
+
+	hashed := sha256.Sum256([]byte(signingInput))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig); err != nil {
+		return nil, fmt.Errorf("oidc: signature verification failed: %w", err)
+	}
+
+	if claims.Issuer != googleIssuer {
+		return nil, fmt.Errorf("oidc: unexpected issuer %q, want %q", claims.Issuer, googleIssuer)
+	}
+	now := time.Now()
+	skew := v.ClockSkew
+	if skew == 0 {
+		skew = defaultClockSkew
+	}
+	if claims.Expiry != 0 && now.After(time.Unix(claims.Expiry, 0).Add(skew)) {
+		return nil, fmt.Errorf("oidc: token expired at %d", claims.Expiry)
+	}
+	if claims.IssuedAt != 0 && now.Before(time.Unix(claims.IssuedAt, 0).Add(-skew)) {
+		return nil, fmt.Errorf("oidc: token issued in the future (iat %d)", claims.IssuedAt)
+	}
+
+	if v.Audience != "" && claims.Audience != v.Audience {
+		return &claims, errAudienceMismatch
+	}
+	if len(v.AllowedEmails) > 0 && !v.AllowedEmails[claims.Email] {
+		return &claims, errEmailNotAllowed
+	}
+
+	return &claims, nil
+}
+
+// oidcHeader is the subset of a JWT header this package needs.
+type oidcHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// splitOIDCToken decodes a compact JWT's header and payload segments
+// (without verifying the signature) and returns the raw signing input
+// (header.payload) and decoded signature bytes for the caller to verify
+// separately.
+func splitOIDCToken(token string) (header oidcHeader, claims oidcClaims, signingInput string, signature []byte, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return oidcHeader{}, oidcClaims{}, "", nil, fmt.Errorf("malformed JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return oidcHeader{}, oidcClaims{}, "", nil, fmt.Errorf("decode JWT header: %w", err)
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return oidcHeader{}, oidcClaims{}, "", nil, fmt.Errorf("parse JWT header: %w", err)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return oidcHeader{}, oidcClaims{}, "", nil, fmt.Errorf("decode JWT claims: %w", err)
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return oidcHeader{}, oidcClaims{}, "", nil, fmt.Errorf("parse JWT claims: %w", err)
+	}
+
+	signature, err = base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return oidcHeader{}, oidcClaims{}, "", nil, fmt.Errorf("decode JWT signature: %w", err)
+	}
+
+	return header, claims, parts[0] + "." + parts[1], signature, nil
+}
+
+// publicKey returns the RSA public key for kid, refreshing the cached JWKS
+// if it's stale or doesn't contain kid (handles Google's key rotation).
+func (v *OIDCVerifier) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	stale := v.fetchedAt.IsZero() || time.Since(v.fetchedAt) > v.maxAge
+	key, ok := v.keys[kid]
+	v.mu.Unlock()
+	if ok && !stale {
+		return key, nil
+	}
+
+	if err := v.refreshKeys(ctx); err != nil {
+		if ok {
+			// Serve the stale key rather than failing outright if refresh
+			// fails but we already had a match.
+			return key, nil
+		}
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	key, ok = v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no matching key in JWKS")
+	}
+	return key, nil
+}
+
+// jwkSet is a JSON Web Key Set as returned by JWKSURL.
+type jwkSet struct {
+	Keys []jwkKey `json:"keys"`
+}
+
+// jwkKey is a single RSA JSON Web Key.
+type jwkKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// refreshKeys fetches the current JWKS, replacing v.keys and v.maxAge from
+// the response's Cache-Control header.
+func (v *OIDCVerifier) refreshKeys(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.jwksURL(), nil)
+	if err != nil {
+		return fmt.Errorf("build jwks request: %w", err)
+	}
+	resp, err := v.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks endpoint returned %d", resp.StatusCode)
+	}
+
+	var set jwkSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := rsaPublicKeyFromJWK(k)
+		if err != nil {
+			return fmt.Errorf("parse jwk %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = pub
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.maxAge = cacheControlMaxAge(resp.Header.Get("Cache-Control"), defaultJWKSMaxAge)
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *OIDCVerifier) jwksURL() string {
+	if v.JWKSURL != "" {
+		return v.JWKSURL
+	}
+	return googleJWKSURL
+}
+
+func (v *OIDCVerifier) httpClient() *http.Client {
+	if v.Client != nil {
+		return v.Client
+	}
+	return http.DefaultClient
+}
+
+// cacheControlMaxAge extracts "max-age=N" (seconds) from a Cache-Control
+// header value, falling back to def if it's missing or unparsable.
+func cacheControlMaxAge(header string, def time.Duration) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.TrimSpace(directive)
+		if secs, ok := strings.CutPrefix(directive, "max-age="); ok {
+			if n, err := strconv.Atoi(secs); err == nil && n > 0 {
+				return time.Duration(n) * time.Second
+			}
+		}
+	}
+	return def
+}
+
+// rsaPublicKeyFromJWK decodes an RSA JWK's base64url-encoded modulus (n) and
+// exponent (e) into an *rsa.PublicKey.
+func rsaPublicKeyFromJWK(k jwkKey) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+
+	e := new(big.Int).SetBytes(eBytes)
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(e.Int64()),
+	}, nil
+}
+
+// RequireOIDC wraps next with v's Bearer token verification, rejecting
+// requests that aren't an authenticated call from the configured Pub/Sub
+// push subscription: 401 on a missing or invalid token, 403 if the token is
+// valid but fails the audience or email-allow-list check.
+func RequireOIDC(v *OIDCVerifier, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := oidcBearerToken(r)
+		if !ok {
+			http.Error(w, "missing or malformed Authorization header", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := v.VerifyIDToken(r.Context(), token)
+		switch {
+		case err == nil:
+			next(w, r)
+		case err == errAudienceMismatch || err == errEmailNotAllowed:
+			log.Printf("trigger: oidc token rejected for %s: %v", claims.Email, err)
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			log.Printf("trigger: oidc verification failed: %v", err)
+			http.Error(w, "invalid or expired token", http.StatusUnauthorized)
+		}
+	}
+}
+
+// oidcBearerToken extracts the token from an "Authorization: Bearer <token>"
+// header. Returns ("", false) when the header is absent or not a Bearer
+// scheme.
+func oidcBearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if h == "" {
+		return "", false
+	}
+	parts := strings.SplitN(h, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", false
+	}
+	token := strings.TrimSpace(parts[1])
+	if token == "" {
+		return "", false
+	}
+	return token, true
+}
@@ -0,0 +1,271 @@
+package handler_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/handler"
+)
+
+// ── fixture RSA key + JWKS server ─────────────────────────────────────────────
+
+const testKid = "test-key-1"
+
+func mustGenerateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	return key
+}
+
+func base64URLEncode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// signTestIDToken builds and signs an RS256 JWT with the given claims,
+// mirroring jobs.signJWTAssertion's approach on the signing side.
+func signTestIDToken(t *testing.T, key *rsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+
+	header := map[string]string{"alg": "RS256", "typ": "JWT", "kid": testKid}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("marshal header: %v", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+
+	signingInput := base64URLEncode(headerJSON) + "." + base64URLEncode(claimsJSON)
+	hashed := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	return signingInput + "." + base64URLEncode(sig)
+}
+
+func testIDTokenClaims(audience string, now time.Time) map[string]any {
+	return map[string]any{
+		"iss":   "https://accounts.google.com",
+		"aud":   audience,
+		"email": "pubsub-push@my-project.iam.gserviceaccount.com",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+}
+
+// newTestJWKSServer serves key's public half as a single-entry JWKS.
+func newTestJWKSServer(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+
+	n := base64URLEncode(key.PublicKey.N.Bytes())
+	e := base64URLEncode(big.NewInt(int64(key.PublicKey.E)).Bytes())
+
+	body, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{"kid": testKid, "kty": "RSA", "n": n, "e": e},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_, _ = w.Write(body)
+	}))
+}
+
+func newTestVerifier(t *testing.T, key *rsa.PrivateKey, audience string, opts ...handler.OIDCOption) *handler.OIDCVerifier {
+	t.Helper()
+	srv := newTestJWKSServer(t, key)
+	t.Cleanup(srv.Close)
+
+	v := handler.NewOIDCVerifier(audience, opts...)
+	v.JWKSURL = srv.URL
+	return v
+}
+
+// ── RequireOIDC tests ──────────────────────────────────────────────────────────
+
+func TestRequireOIDC_ValidToken_CallsNext(t *testing.T) {
+	key := mustGenerateRSAKey(t)
+	v := newTestVerifier(t, key, "https://trigger.example.com")
+	token := signTestIDToken(t, key, testIDTokenClaims("https://trigger.example.com", time.Now()))
+
+	called := false
+	h := handler.RequireOIDC(v, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if !called {
+		t.Error("expected next to be called for a valid token")
+	}
+}
+
+func TestRequireOIDC_MissingAuthorizationHeader_Returns401(t *testing.T) {
+	key := mustGenerateRSAKey(t)
+	v := newTestVerifier(t, key, "https://trigger.example.com")
+
+	called := false
+	h := handler.RequireOIDC(v, func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+	if called {
+		t.Error("next must not be called without a token")
+	}
+}
+
+func TestRequireOIDC_InvalidSignature_Returns401(t *testing.T) {
+	key := mustGenerateRSAKey(t)
+	otherKey := mustGenerateRSAKey(t)
+	v := newTestVerifier(t, key, "https://trigger.example.com")
+	token := signTestIDToken(t, otherKey, testIDTokenClaims("https://trigger.example.com", time.Now()))
+
+	h := handler.RequireOIDC(v, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next must not be called for an invalid signature")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestRequireOIDC_ExpiredToken_Returns401(t *testing.T) {
+	key := mustGenerateRSAKey(t)
+	v := newTestVerifier(t, key, "https://trigger.example.com")
+	claims := testIDTokenClaims("https://trigger.example.com", time.Now().Add(-time.Hour))
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	token := signTestIDToken(t, key, claims)
+
+	h := handler.RequireOIDC(v, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next must not be called for an expired token")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestRequireOIDC_WrongAudience_Returns403(t *testing.T) {
+	key := mustGenerateRSAKey(t)
+	v := newTestVerifier(t, key, "https://trigger.example.com")
+	token := signTestIDToken(t, key, testIDTokenClaims("https://some-other-service.example.com", time.Now()))
+
+	h := handler.RequireOIDC(v, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next must not be called for a wrong audience")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestRequireOIDC_EmailNotAllowed_Returns403(t *testing.T) {
+	key := mustGenerateRSAKey(t)
+	v := newTestVerifier(t, key, "https://trigger.example.com",
+		handler.WithAllowedEmails("someone-else@my-project.iam.gserviceaccount.com"))
+	token := signTestIDToken(t, key, testIDTokenClaims("https://trigger.example.com", time.Now()))
+
+	h := handler.RequireOIDC(v, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next must not be called for a disallowed email")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestRequireOIDC_AllowedEmail_CallsNext(t *testing.T) {
+	key := mustGenerateRSAKey(t)
+	v := newTestVerifier(t, key, "https://trigger.example.com",
+		handler.WithAllowedEmails("pubsub-push@my-project.iam.gserviceaccount.com"))
+	token := signTestIDToken(t, key, testIDTokenClaims("https://trigger.example.com", time.Now()))
+
+	called := false
+	h := handler.RequireOIDC(v, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if !called {
+		t.Error("expected next to be called for an allowed email")
+	}
+}
+
+func TestRequireOIDC_ClockSkew_AllowsSlightlyExpiredToken(t *testing.T) {
+	key := mustGenerateRSAKey(t)
+	v := newTestVerifier(t, key, "https://trigger.example.com", handler.WithClockSkew(time.Minute))
+	claims := testIDTokenClaims("https://trigger.example.com", time.Now())
+	claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+	token := signTestIDToken(t, key, claims)
+
+	called := false
+	h := handler.RequireOIDC(v, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	if !called {
+		t.Error("expected a token within clock skew to be accepted")
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisDeduper is a production Deduper backed by Redis, mirroring
+// session.RedisCache's role for session revocation. A seen key expires on
+// its own after ttl, so Redis needs no separate cleanup pass.
+type RedisDeduper struct {
+	client *redis.Client
+}
+
+// NewRedisDeduper constructs a RedisDeduper backed by client.
+func NewRedisDeduper(client *redis.Client) *RedisDeduper {
+	return &RedisDeduper{client: client}
+}
+
+func (d *RedisDeduper) SeenWithin(ctx context.Context, key string, _ time.Duration) (bool, error) {
+	n, err := d.client.Exists(ctx, dedupKey(key)).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis dedup: check %q: %w", key, err)
+	}
+	return n > 0, nil
+}
+
+func (d *RedisDeduper) MarkSeen(ctx context.Context, key string, ttl time.Duration) error {
+	if err := d.client.Set(ctx, dedupKey(key), "1", ttl).Err(); err != nil {
+		return fmt.Errorf("redis dedup: mark %q: %w", key, err)
+	}
+	return nil
+}
+
+// dedupKey namespaces dedup entries so the trigger can share a Redis
+// instance with other subsystems without key collisions.
+func dedupKey(key string) string {
+	return "trigger:dedup:" + key
+}
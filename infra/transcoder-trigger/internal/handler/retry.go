@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/notify"
+)
+
+const (
+	// defaultMaxAttempts preserves today's behaviour (a single Execute call,
+	// no retries) for a zero-value RetryPolicy.
+	defaultMaxAttempts = 1
+	// defaultRetryBaseDelay is the delay before the first retry; it doubles
+	// on each subsequent attempt.
+	defaultRetryBaseDelay = 500 * time.Millisecond
+)
+
+// RetryPolicy bounds how many times NewTriggerHandler retries a failed
+// executor.Execute call before giving up on the delivery and handing it to
+// DeadLetter, so a poison event can't make Eventarc redeliver and retry an
+// expensive Cloud Run Job execution forever.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of Execute attempts, including the
+	// first. Zero (the default RetryPolicy) means one attempt, i.e. no
+	// retries — matching NewTriggerHandler's behaviour before RetryPolicy
+	// existed.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on each
+	// subsequent attempt. Defaults to defaultRetryBaseDelay when zero.
+	BaseDelay time.Duration
+	// IsTransient classifies err as worth retrying (true) or permanent
+	// (false — e.g. a 4xx from the Cloud Run Jobs API), which skips any
+	// remaining retries and goes straight to DeadLetter. Defaults to
+	// DefaultIsTransient when nil.
+	IsTransient func(error) bool
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) baseDelay() time.Duration {
+	if p.BaseDelay <= 0 {
+		return defaultRetryBaseDelay
+	}
+	return p.BaseDelay
+}
+
+func (p RetryPolicy) isTransient(err error) bool {
+	if p.IsTransient == nil {
+		return DefaultIsTransient(err)
+	}
+	return p.IsTransient(err)
+}
+
+// DefaultIsTransient classifies err as transient unless it wraps
+// jobs.ErrPermanent, which CloudRunJobRunner wraps around run-job responses
+// with a status other than 429 or 5xx (e.g. a 4xx from the Cloud Run Jobs
+// API). Every other error counts as transient, including transport errors
+// that CloudRunJobRunner itself does not retry.
+func DefaultIsTransient(err error) bool {
+	return !errors.Is(err, jobs.ErrPermanent)
+}
+
+// executeWithRetries calls executor.Execute, retrying per policy until it
+// succeeds, hits a permanent error, or exhausts its attempts — in which
+// case the last error is returned for the caller to dead-letter. On success
+// it returns the execution name Execute reported. The returned attempt is
+// the 1-based number of the final Execute call, for notify.Event.Attempt.
+func executeWithRetries(ctx context.Context, executor JobExecutor, req jobs.ExecuteRequest, policy RetryPolicy) (string, int, error) {
+	delay := policy.baseDelay()
+	maxAttempts := policy.maxAttempts()
+	var lastErr error
+	attempt := 1
+	for ; attempt <= maxAttempts; attempt++ {
+		req.Attempt = attempt
+		executionName, err := executor.Execute(ctx, req)
+		if err == nil {
+			return executionName, attempt, nil
+		}
+		lastErr = err
+		if !policy.isTransient(lastErr) {
+			return "", attempt, lastErr
+		}
+		if attempt < maxAttempts {
+			select {
+			case <-ctx.Done():
+				return "", attempt, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+	}
+	return "", attempt - 1, lastErr
+}
+
+// DeadLetter publishes a raw event NewTriggerHandler has given up retrying,
+// together with the failure reason, so an operator can inspect or replay it
+// instead of it silently vanishing once the handler acks it with 204 to
+// stop further Eventarc redelivery.
+type DeadLetter interface {
+	Publish(ctx context.Context, rawEvent []byte, reason string) error
+}
+
+// deadLetterMessage is the JSON payload PubSubDeadLetter publishes.
+type deadLetterMessage struct {
+	RawEvent json.RawMessage `json:"raw_event"`
+	Reason   string          `json:"reason"`
+}
+
+// PubSubDeadLetter publishes exhausted events to a Pub/Sub topic as JSON, so
+// an operator (or a replay tool subscribed to the topic) can act on them.
+type PubSubDeadLetter struct {
+	topic *pubsub.Topic
+}
+
+// NewPubSubDeadLetter constructs a PubSubDeadLetter for the given project
+// and topic ID. The topic must already exist.
+func NewPubSubDeadLetter(ctx context.Context, projectID, topicID string) (*PubSubDeadLetter, error) {
+	client, err := pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("create pubsub client: %w", err)
+	}
+	return &PubSubDeadLetter{topic: client.Topic(topicID)}, nil
+}
+
+// Publish JSON-wraps rawEvent and reason and publishes them to the topic,
+// blocking until the publish completes or ctx is done.
+func (d *PubSubDeadLetter) Publish(ctx context.Context, rawEvent []byte, reason string) error {
+	data, err := json.Marshal(deadLetterMessage{RawEvent: rawEvent, Reason: reason})
+	if err != nil {
+		return fmt.Errorf("marshal dead-letter message: %w", err)
+	}
+	result := d.topic.Publish(ctx, &pubsub.Message{Data: data})
+	if _, err := result.Get(ctx); err != nil {
+		return fmt.Errorf("publish dead-letter message: %w", err)
+	}
+	return nil
+}
+
+// triggerConfig holds NewTriggerHandler's optional settings, customized via
+// TriggerOption.
+type triggerConfig struct {
+	retry      RetryPolicy
+	deadLetter DeadLetter
+	dedup      Deduper
+	jobStore   jobs.JobStore
+	publisher  notify.Publisher
+}
+
+// TriggerOption customizes a handler constructed by NewTriggerHandler.
+type TriggerOption func(*triggerConfig)
+
+// WithRetryPolicy overrides the default RetryPolicy (a single attempt, i.e.
+// no retries) NewTriggerHandler applies to a failed executor.Execute call.
+func WithRetryPolicy(p RetryPolicy) TriggerOption {
+	return func(c *triggerConfig) { c.retry = p }
+}
+
+// WithDeadLetter attaches a DeadLetter sink that receives events
+// NewTriggerHandler has given up retrying (see RetryPolicy). Without one
+// configured, exhausting retries falls back to today's behaviour: a 500
+// response, relying on Eventarc's own redelivery.
+func WithDeadLetter(d DeadLetter) TriggerOption {
+	return func(c *triggerConfig) { c.deadLetter = d }
+}
+
+// WithJobStore attaches the jobs.JobStore NewTriggerHandler records each
+// execution into, keyed by VideoID, for the job-management HTTP surface
+// (GET /jobs, GET/DELETE /jobs/{videoID} — see NewRouter) to read from.
+// Defaults to an in-memory jobs.JobStore when unset.
+func WithJobStore(s jobs.JobStore) TriggerOption {
+	return func(c *triggerConfig) { c.jobStore = s }
+}
+
+// WithPublisher attaches the notify.Publisher NewTriggerHandler reports
+// transcode.started/succeeded/failed lifecycle events to. Defaults to a
+// notify.NoopPublisher when unset.
+func WithPublisher(p notify.Publisher) TriggerOption {
+	return func(c *triggerConfig) { c.publisher = p }
+}
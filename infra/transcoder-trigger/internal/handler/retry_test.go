@@ -0,0 +1,177 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/handler"
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+// ── flakyExecutor ─────────────────────────────────────────────────────────────
+
+// flakyExecutor fails its first failUntil calls, then succeeds, recording the
+// Attempt number each call was made with.
+type flakyExecutor struct {
+	failUntil int
+	err       error
+
+	calls    int
+	attempts []int
+}
+
+func (f *flakyExecutor) Execute(_ context.Context, req jobs.ExecuteRequest) (string, error) {
+	f.calls++
+	f.attempts = append(f.attempts, req.Attempt)
+	if f.calls <= f.failUntil {
+		if f.err != nil {
+			return "", f.err
+		}
+		return "", errors.New("transient failure")
+	}
+	return "exec-name", nil
+}
+
+// ── stubDeadLetter ────────────────────────────────────────────────────────────
+
+type stubDeadLetter struct {
+	err        error
+	publishes  int
+	lastEvent  []byte
+	lastReason string
+}
+
+func (d *stubDeadLetter) Publish(_ context.Context, rawEvent []byte, reason string) error {
+	d.publishes++
+	d.lastEvent = rawEvent
+	d.lastReason = reason
+	return d.err
+}
+
+// ── DefaultIsTransient ────────────────────────────────────────────────────────
+
+func TestDefaultIsTransient_PermanentError_ReturnsFalse(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", jobs.ErrPermanent)
+	if handler.DefaultIsTransient(err) {
+		t.Error("expected a wrapped jobs.ErrPermanent to be classified as non-transient")
+	}
+}
+
+func TestDefaultIsTransient_OtherError_ReturnsTrue(t *testing.T) {
+	if !handler.DefaultIsTransient(errors.New("connection reset")) {
+		t.Error("expected a generic error to be classified as transient")
+	}
+}
+
+// ── RetryPolicy via NewTriggerHandler ─────────────────────────────────────────
+
+func TestTriggerHandler_RetriesTransientFailureThenSucceeds(t *testing.T) {
+	exec := &flakyExecutor{failUntil: 2}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket",
+		handler.WithRetryPolicy(handler.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if exec.calls != 3 {
+		t.Errorf("expected 3 Execute calls, got %d", exec.calls)
+	}
+	if got := exec.attempts; len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("expected attempts [1 2 3], got %v", got)
+	}
+}
+
+func TestTriggerHandler_NoDeadLetter_ExhaustedRetries_Returns500(t *testing.T) {
+	exec := &flakyExecutor{failUntil: 10}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket",
+		handler.WithRetryPolicy(handler.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	if exec.calls != 2 {
+		t.Errorf("expected 2 Execute calls, got %d", exec.calls)
+	}
+}
+
+func TestTriggerHandler_DeadLetter_PublishesAndReturns204AfterExhaustingRetries(t *testing.T) {
+	exec := &flakyExecutor{failUntil: 10}
+	dl := &stubDeadLetter{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket",
+		handler.WithRetryPolicy(handler.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		handler.WithDeadLetter(dl))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if dl.publishes != 1 {
+		t.Fatalf("expected 1 dead-letter publish, got %d", dl.publishes)
+	}
+	if dl.lastReason == "" {
+		t.Error("expected a non-empty failure reason")
+	}
+	if string(dl.lastEvent) != validBody() {
+		t.Errorf("expected the raw event body to be dead-lettered, got %q", dl.lastEvent)
+	}
+}
+
+func TestTriggerHandler_DeadLetter_PermanentError_SkipsRetries(t *testing.T) {
+	exec := &flakyExecutor{failUntil: 10, err: fmt.Errorf("bad request: %w", jobs.ErrPermanent)}
+	dl := &stubDeadLetter{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket",
+		handler.WithRetryPolicy(handler.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}),
+		handler.WithDeadLetter(dl))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if exec.calls != 1 {
+		t.Errorf("expected a permanent error to skip remaining retries, got %d calls", exec.calls)
+	}
+	if dl.publishes != 1 {
+		t.Errorf("expected 1 dead-letter publish, got %d", dl.publishes)
+	}
+}
+
+func TestTriggerHandler_DeadLetter_PublishError_Returns500(t *testing.T) {
+	exec := &flakyExecutor{failUntil: 10}
+	dl := &stubDeadLetter{err: errors.New("topic unavailable")}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket",
+		handler.WithRetryPolicy(handler.RetryPolicy{MaxAttempts: 1, BaseDelay: time.Millisecond}),
+		handler.WithDeadLetter(dl))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 when the dead-letter publish itself fails, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+// version is stamped onto GET /healthz responses. Overridden at build time
+// via -ldflags "-X .../handler.version=...", left as "dev" otherwise.
+var version = "dev"
+
+// startedAt records process start, for GET /healthz's uptime field.
+var startedAt = time.Now()
+
+// JobCanceler cancels a running job execution. Satisfied by
+// *jobs.CloudRunJobRunner; lets DELETE /jobs/{videoID} work against a stub
+// in tests. Executors that don't implement it (e.g. jobs.LocalExecutor)
+// respond 501 to a cancel request.
+type JobCanceler interface {
+	CancelExecution(ctx context.Context, executionName string) error
+}
+
+// NewRouter builds the transcoder-trigger's full HTTP surface:
+//
+//	POST   /              — the Eventarc/Pub/Sub push trigger
+//	GET    /healthz        — {status, version, uptime}
+//	GET    /jobs           — every tracked execution
+//	GET    /jobs/{videoID} — single execution lookup, 404 if untracked
+//	DELETE /jobs/{videoID} — cancel the execution via executor's JobCanceler
+//
+// triggerHandler is a handler built by NewTriggerHandler (optionally wrapped
+// in middleware, e.g. RequireOIDC, by the caller) and is mounted at "/"
+// as-is; NewRouter only adds the read-only and cancellation routes around
+// it. executor is consulted for JobCanceler on DELETE /jobs/{videoID}; it
+// should be the same executor passed to NewTriggerHandler. jobStore should
+// be the same jobs.JobStore given to NewTriggerHandler via WithJobStore, so
+// every route reads and writes the same set of tracked executions.
+func NewRouter(triggerHandler http.HandlerFunc, executor JobExecutor, jobStore jobs.JobStore) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", triggerHandler)
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/jobs", handleJobsList(jobStore))
+	mux.HandleFunc("/jobs/", handleJob(executor, jobStore))
+	return mux
+}
+
+// healthzResponse is GET /healthz's JSON body.
+type healthzResponse struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+	Uptime  string `json:"uptime"`
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(healthzResponse{
+		Status:  "ok",
+		Version: version,
+		Uptime:  time.Since(startedAt).String(),
+	})
+}
+
+// jobResponse is the JSON shape GET /jobs and GET /jobs/{videoID} return for
+// one tracked execution.
+type jobResponse struct {
+	VideoID       string    `json:"video_id"`
+	ExecutionName string    `json:"execution_name"`
+	State         string    `json:"state"`
+	StartedAt     time.Time `json:"started_at"`
+}
+
+func toJobResponse(rec jobs.JobRecord) jobResponse {
+	return jobResponse{
+		VideoID:       rec.VideoID,
+		ExecutionName: rec.ExecutionName,
+		State:         string(rec.State),
+		StartedAt:     rec.StartedAt,
+	}
+}
+
+func handleJobsList(store jobs.JobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		recs, err := store.List(r.Context())
+		if err != nil {
+			log.Printf("GET /jobs: list: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		out := make([]jobResponse, len(recs))
+		for i, rec := range recs {
+			out[i] = toJobResponse(rec)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out)
+	}
+}
+
+// handleJob serves GET and DELETE /jobs/{videoID}.
+func handleJob(executor JobExecutor, store jobs.JobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		videoID := strings.TrimPrefix(r.URL.Path, "/jobs/")
+		if videoID == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			getJob(w, r, store, videoID)
+		case http.MethodDelete:
+			cancelJob(w, r, executor, store, videoID)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func getJob(w http.ResponseWriter, r *http.Request, store jobs.JobStore, videoID string) {
+	rec, ok, err := store.Get(r.Context(), videoID)
+	if err != nil {
+		log.Printf("GET /jobs/%s: lookup: %v", videoID, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(toJobResponse(rec))
+}
+
+func cancelJob(w http.ResponseWriter, r *http.Request, executor JobExecutor, store jobs.JobStore, videoID string) {
+	rec, ok, err := store.Get(r.Context(), videoID)
+	if err != nil {
+		log.Printf("DELETE /jobs/%s: lookup: %v", videoID, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if rec.State != jobs.StatusRunning {
+		http.Error(w, fmt.Sprintf("execution already %s", rec.State), http.StatusConflict)
+		return
+	}
+
+	canceler, ok := executor.(JobCanceler)
+	if !ok {
+		http.Error(w, "executor does not support cancellation", http.StatusNotImplemented)
+		return
+	}
+	if err := canceler.CancelExecution(r.Context(), rec.ExecutionName); err != nil {
+		log.Printf("DELETE /jobs/%s: cancel %s: %v", videoID, rec.ExecutionName, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if err := store.UpdateState(r.Context(), videoID, jobs.StatusFailed); err != nil {
+		log.Printf("DELETE /jobs/%s: mark cancelled: %v", videoID, err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
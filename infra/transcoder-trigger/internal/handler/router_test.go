@@ -0,0 +1,192 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/handler"
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+// stubCanceler is a mockExecutor that also implements handler.JobCanceler,
+// for testing DELETE /jobs/{videoID}.
+type stubCanceler struct {
+	mockExecutor
+	cancelErr    error
+	canceledName string
+	cancelCalled bool
+}
+
+func (c *stubCanceler) CancelExecution(_ context.Context, executionName string) error {
+	c.cancelCalled = true
+	c.canceledName = executionName
+	return c.cancelErr
+}
+
+func noopTrigger(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestRouter_Healthz(t *testing.T) {
+	router := handler.NewRouter(noopTrigger, &mockExecutor{}, jobs.NewMemoryJobStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body["status"] != "ok" {
+		t.Errorf("unexpected status: %q", body["status"])
+	}
+}
+
+func TestRouter_TriggerStillMountedAtRoot(t *testing.T) {
+	router := handler.NewRouter(noopTrigger, &mockExecutor{}, jobs.NewMemoryJobStore())
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 from the trigger handler, got %d", rec.Code)
+	}
+}
+
+func TestRouter_JobsList(t *testing.T) {
+	store := jobs.NewMemoryJobStore()
+	store.Put(context.Background(), jobs.JobRecord{VideoID: "vid-1", ExecutionName: "exec-1", State: jobs.StatusRunning, StartedAt: time.Now()})
+	store.Put(context.Background(), jobs.JobRecord{VideoID: "vid-2", ExecutionName: "exec-2", State: jobs.StatusSucceeded, StartedAt: time.Now()})
+
+	router := handler.NewRouter(noopTrigger, &mockExecutor{}, store)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected 2 jobs, got %d", len(got))
+	}
+}
+
+func TestRouter_JobLookup_Found(t *testing.T) {
+	store := jobs.NewMemoryJobStore()
+	store.Put(context.Background(), jobs.JobRecord{VideoID: "vid-1", ExecutionName: "exec-1", State: jobs.StatusRunning, StartedAt: time.Now()})
+
+	router := handler.NewRouter(noopTrigger, &mockExecutor{}, store)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/vid-1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got["video_id"] != "vid-1" {
+		t.Errorf("unexpected video_id: %v", got["video_id"])
+	}
+}
+
+func TestRouter_JobLookup_NotFound(t *testing.T) {
+	router := handler.NewRouter(noopTrigger, &mockExecutor{}, jobs.NewMemoryJobStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/unknown", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestRouter_CancelJob_Success(t *testing.T) {
+	store := jobs.NewMemoryJobStore()
+	store.Put(context.Background(), jobs.JobRecord{VideoID: "vid-1", ExecutionName: "exec-1", State: jobs.StatusRunning, StartedAt: time.Now()})
+	canceler := &stubCanceler{}
+
+	router := handler.NewRouter(noopTrigger, canceler, store)
+
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/vid-1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if !canceler.cancelCalled || canceler.canceledName != "exec-1" {
+		t.Errorf("expected CancelExecution to be called with exec-1, got called=%v name=%q", canceler.cancelCalled, canceler.canceledName)
+	}
+	rec2, ok, err := store.Get(context.Background(), "vid-1")
+	if err != nil || !ok {
+		t.Fatalf("expected job still tracked after cancel, got ok=%v err=%v", ok, err)
+	}
+	if rec2.State != jobs.StatusFailed {
+		t.Errorf("expected cancelled job marked failed, got %q", rec2.State)
+	}
+}
+
+func TestRouter_CancelJob_NotFound(t *testing.T) {
+	router := handler.NewRouter(noopTrigger, &stubCanceler{}, jobs.NewMemoryJobStore())
+
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/unknown", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestRouter_CancelJob_AlreadyFinished_Returns409(t *testing.T) {
+	store := jobs.NewMemoryJobStore()
+	store.Put(context.Background(), jobs.JobRecord{VideoID: "vid-1", ExecutionName: "exec-1", State: jobs.StatusSucceeded, StartedAt: time.Now()})
+	canceler := &stubCanceler{}
+
+	router := handler.NewRouter(noopTrigger, canceler, store)
+
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/vid-1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d", rec.Code)
+	}
+	if canceler.cancelCalled {
+		t.Error("expected CancelExecution not to be called for an already-finished job")
+	}
+}
+
+func TestRouter_CancelJob_ExecutorWithoutCanceler_Returns501(t *testing.T) {
+	store := jobs.NewMemoryJobStore()
+	store.Put(context.Background(), jobs.JobRecord{VideoID: "vid-1", ExecutionName: "exec-1", State: jobs.StatusRunning, StartedAt: time.Now()})
+
+	router := handler.NewRouter(noopTrigger, &mockExecutor{}, store)
+
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/vid-1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("expected 501, got %d", rec.Code)
+	}
+}
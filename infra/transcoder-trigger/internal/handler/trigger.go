@@ -2,54 +2,226 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/event"
 	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/notify"
 )
 
 // JobExecutor is the interface the handler depends on to start a Cloud Run Job.
 // It is satisfied by *jobs.CloudRunJobRunner and allows tests to inject a mock.
 type JobExecutor interface {
-	Execute(ctx context.Context, req jobs.ExecuteRequest) error
+	Execute(ctx context.Context, req jobs.ExecuteRequest) (executionName string, err error)
+}
+
+// runsToTerminal reports whether executor satisfies jobs.TerminalExecutor
+// and its Execute call runs to completion, so a nil error can be trusted as
+// "the transcode succeeded" rather than just "the job was submitted".
+func runsToTerminal(executor JobExecutor) bool {
+	te, ok := executor.(jobs.TerminalExecutor)
+	return ok && te.RunsToTerminal()
 }
 
 // NewTriggerHandler returns an http.HandlerFunc that:
-//  1. Parses the GCS StorageObject from the Eventarc request body.
+//  1. Parses the GCS object-finalize event from the request, content
+//     negotiating across every envelope shape Eventarc/Pub/Sub can deliver
+//     it in — see event.ParseRequest.
 //  2. Extracts the VIDEO_ID from the object name.
-//  3. Calls executor.Execute with the job env-var overrides.
+//  3. If a Deduper is configured (see WithDeduper), checks it for the
+//     idempotency key as a fast pre-check before store.Create, short-
+//     circuiting with 204 on a hit. A Deduper error fails open (logged, not
+//     fatal) so a broken dedup store can't drop a real event.
+//  4. Records the event's idempotency key in store, short-circuiting with
+//     204 if it's already recorded — GCS finalize events are delivered
+//     at-least-once, so the same event can otherwise launch two Cloud Run
+//     executions racing on the same HLS output.
+//  5. Calls executor.Execute with the job env-var overrides, retrying per
+//     opts' RetryPolicy (a single attempt by default) and, once attempts
+//     are exhausted, handing the raw event to its DeadLetter sink if one is
+//     configured — see WithRetryPolicy and WithDeadLetter.
+//  6. Records the execution in a JobStore (in-memory by default, see
+//     WithJobStore), keyed by VideoID, for the job-management HTTP surface
+//     (GET /jobs, GET/DELETE /jobs/{videoID} — see NewRouter) to list,
+//     look up, and cancel.
+//  7. Reports transcode.started to a notify.Publisher (a no-op by default,
+//     see WithPublisher) once Execute is called, and transcode.failed if it
+//     returns an error. transcode.succeeded is only reported when executor
+//     satisfies jobs.TerminalExecutor and runs to completion inline (e.g.
+//     LocalExecutor, or CloudRunJobRunner with PollUntilTerminal) — for a
+//     fire-and-forget executor a nil error only means submission succeeded.
 //
 // hlsBucket is the destination GCS bucket passed to the transcoder job.
-func NewTriggerHandler(executor JobExecutor, hlsBucket string) http.HandlerFunc {
+func NewTriggerHandler(executor JobExecutor, store jobs.Store, hlsBucket string, opts ...TriggerOption) http.HandlerFunc {
+	var cfg triggerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.jobStore == nil {
+		cfg.jobStore = jobs.NewMemoryJobStore()
+	}
+	if cfg.publisher == nil {
+		cfg.publisher = notify.NoopPublisher{}
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
-		obj, err := event.Parse(r.Body)
+		bodyBytes, err := io.ReadAll(r.Body)
 		if err != nil {
+			log.Printf("trigger: read request body: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+
+		evt, err := event.ParseRequest(r, bodyBytes)
+		if err != nil {
+			if errors.Is(err, event.ErrUnsupportedEventType) {
+				log.Printf("trigger: %v, acking without work", err)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
 			log.Printf("trigger: parse event: %v", err)
 			http.Error(w, "bad request", http.StatusBadRequest)
 			return
 		}
 
-		videoID, err := obj.VideoID()
+		videoID, err := evt.VideoID()
 		if err != nil {
 			log.Printf("trigger: extract video ID: %v", err)
 			http.Error(w, "bad request", http.StatusBadRequest)
 			return
 		}
 
+		key := evt.IdempotencyKey()
+
+		if cfg.dedup != nil {
+			seen, dedupErr := cfg.dedup.SeenWithin(r.Context(), key, defaultDedupTTL)
+			if dedupErr != nil {
+				log.Printf("trigger: dedup check for %s failed, failing open: %v", key, dedupErr)
+			} else if seen {
+				log.Printf("trigger: duplicate=true key=%s, skipping", key)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		if err := store.Create(r.Context(), key, videoID); err != nil {
+			if errors.Is(err, jobs.ErrAlreadyExists) {
+				log.Printf("trigger: duplicate delivery for %s, skipping", key)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			log.Printf("trigger: record idempotency key %s: %v", key, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		if cfg.dedup != nil {
+			if dedupErr := cfg.dedup.MarkSeen(r.Context(), key, defaultDedupTTL); dedupErr != nil {
+				log.Printf("trigger: dedup mark-seen for %s failed: %v", key, dedupErr)
+			}
+		}
+
 		req := jobs.ExecuteRequest{
-			RawObjectPath: obj.Name,
+			RawObjectPath:  evt.Name,
+			VideoID:        videoID,
+			HLSBucket:      hlsBucket,
+			IdempotencyKey: key,
+			CEEventID:      evt.CEID,
+			CESource:       evt.CESource,
+			CEType:         evt.CEType,
+			CESubject:      evt.CESubject,
+			CETime:         evt.CETime,
+		}
+
+		if pubErr := cfg.publisher.Publish(r.Context(), notify.Event{
+			Type:      notify.EventStarted,
+			VideoID:   videoID,
+			RawObject: evt.Name,
+			HLSBucket: hlsBucket,
+			EventTime: time.Now(),
+			Attempt:   1,
+		}); pubErr != nil {
+			log.Printf("trigger: publish %s for %s: %v", notify.EventStarted, videoID, pubErr)
+		}
+
+		executionName, attempt, execErr := executeWithRetries(r.Context(), executor, req, cfg.retry)
+		if jsErr := cfg.jobStore.Put(r.Context(), jobs.JobRecord{
 			VideoID:       videoID,
-			HLSBucket:     hlsBucket,
+			ExecutionName: executionName,
+			State:         jobs.StatusRunning,
+			StartedAt:     time.Now(),
+		}); jsErr != nil {
+			log.Printf("trigger: record job %s: %v", videoID, jsErr)
 		}
 
-		if err := executor.Execute(r.Context(), req); err != nil {
-			log.Printf("trigger: execute job: %v", err)
+		if execErr != nil {
+			log.Printf("trigger: execute job: %v", execErr)
+			if sErr := store.UpdateStatus(r.Context(), key, jobs.StatusFailed); sErr != nil {
+				log.Printf("trigger: mark %s failed: %v", key, sErr)
+			}
+			if jsErr := cfg.jobStore.UpdateState(r.Context(), videoID, jobs.StatusFailed); jsErr != nil {
+				log.Printf("trigger: mark job %s failed: %v", videoID, jsErr)
+			}
+			if pubErr := cfg.publisher.Publish(r.Context(), notify.Event{
+				Type:      notify.EventFailed,
+				VideoID:   videoID,
+				RawObject: evt.Name,
+				HLSBucket: hlsBucket,
+				EventTime: time.Now(),
+				Attempt:   attempt,
+				Error:     execErr.Error(),
+			}); pubErr != nil {
+				log.Printf("trigger: publish %s for %s: %v", notify.EventFailed, videoID, pubErr)
+			}
+			if cfg.deadLetter != nil {
+				if dlErr := cfg.deadLetter.Publish(r.Context(), bodyBytes, execErr.Error()); dlErr != nil {
+					log.Printf("trigger: publish dead letter for %s: %v", key, dlErr)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+				log.Printf("trigger: dead-lettered %s after exhausting retries: %v", key, execErr)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
 			http.Error(w, "internal error", http.StatusInternalServerError)
 			return
 		}
 
+		// A nil execErr only means the transcode actually finished when
+		// executor runs to completion inline (see jobs.TerminalExecutor);
+		// CloudRunJobRunner without PollUntilTerminal and KubernetesExecutor
+		// both return as soon as the job is merely submitted, so reporting
+		// StatusSucceeded/transcode.succeeded here would be a lie. Those
+		// executors leave the execution recorded as StatusRunning above
+		// until something else (LRO polling, a completion webhook) observes
+		// it finish.
+		if runsToTerminal(executor) {
+			if err := store.UpdateStatus(r.Context(), key, jobs.StatusSucceeded); err != nil {
+				log.Printf("trigger: mark %s succeeded: %v", key, err)
+			}
+			if jsErr := cfg.jobStore.UpdateState(r.Context(), videoID, jobs.StatusSucceeded); jsErr != nil {
+				log.Printf("trigger: mark job %s succeeded: %v", videoID, jsErr)
+			}
+			if pubErr := cfg.publisher.Publish(r.Context(), notify.Event{
+				Type:          notify.EventSucceeded,
+				VideoID:       videoID,
+				RawObject:     evt.Name,
+				HLSBucket:     hlsBucket,
+				ExecutionName: executionName,
+				EventTime:     time.Now(),
+				Attempt:       attempt,
+			}); pubErr != nil {
+				log.Printf("trigger: publish %s for %s: %v", notify.EventSucceeded, videoID, pubErr)
+			}
+		}
+
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
@@ -2,28 +2,109 @@ package handler_test
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/handler"
 	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/notify"
 )
 
 // ── mockExecutor ──────────────────────────────────────────────────────────────
 
 type mockExecutor struct {
-	err      error
-	received jobs.ExecuteRequest
-	called   bool
+	err           error
+	executionName string
+	received      jobs.ExecuteRequest
+	called        bool
 }
 
-func (m *mockExecutor) Execute(_ context.Context, req jobs.ExecuteRequest) error {
+// RunsToTerminal reports true: mockExecutor models an executor whose
+// Execute call runs to completion, like LocalExecutor, so tests built
+// around it can assert on StatusSucceeded/transcode.succeeded.
+func (m *mockExecutor) RunsToTerminal() bool { return true }
+
+func (m *mockExecutor) Execute(_ context.Context, req jobs.ExecuteRequest) (string, error) {
 	m.called = true
 	m.received = req
-	return m.err
+	return m.executionName, m.err
+}
+
+// ── stubStore ──────────────────────────────────────────────────────────────────
+
+// stubStore is a jobs.Store stub that lets tests force Create/UpdateStatus
+// errors independently of a real backend's semantics.
+type stubStore struct {
+	createErr error
+	updateErr error
+
+	createCalls int
+	updates     []jobs.ExecutionStatus
+}
+
+func (s *stubStore) Create(_ context.Context, _, _ string) error {
+	s.createCalls++
+	return s.createErr
+}
+
+func (s *stubStore) UpdateStatus(_ context.Context, _ string, status jobs.ExecutionStatus) error {
+	s.updates = append(s.updates, status)
+	return s.updateErr
+}
+
+func (s *stubStore) Sweep(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+// ── stubDeduper ──────────────────────────────────────────────────────────────
+
+// stubDeduper is a handler.Deduper stub that lets tests force SeenWithin's
+// result or error independently of a real backend's semantics.
+type stubDeduper struct {
+	seen      bool
+	seenErr   error
+	markCalls int
+	markErr   error
+}
+
+func (d *stubDeduper) SeenWithin(context.Context, string, time.Duration) (bool, error) {
+	return d.seen, d.seenErr
+}
+
+func (d *stubDeduper) MarkSeen(context.Context, string, time.Duration) error {
+	d.markCalls++
+	return d.markErr
+}
+
+// ── fakePublisher ─────────────────────────────────────────────────────────────
+
+// fakePublisher is a notify.Publisher stub that records every Event it's
+// given, in order, so tests can assert on the lifecycle events a request
+// produced.
+type fakePublisher struct {
+	events []notify.Event
+	err    error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, evt notify.Event) error {
+	p.events = append(p.events, evt)
+	return p.err
+}
+
+func (p *fakePublisher) countOf(t notify.EventType) int {
+	n := 0
+	for _, evt := range p.events {
+		if evt.Type == t {
+			n++
+		}
+	}
+	return n
 }
 
 // ── helpers ───────────────────────────────────────────────────────────────────
@@ -36,7 +117,7 @@ func validBody() string {
 
 func TestTriggerHandler_Success(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "mytube-hls-output")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "mytube-hls-output")
 
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
 	rec := httptest.NewRecorder()
@@ -53,7 +134,7 @@ func TestTriggerHandler_Success(t *testing.T) {
 
 func TestTriggerHandler_PassesCorrectVideoID(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
 
 	body := `{"bucket":"mytube-raw-uploads","name":"raw/550e8400-e29b-41d4-a716-446655440000.mp4"}`
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
@@ -71,7 +152,7 @@ func TestTriggerHandler_PassesCorrectVideoID(t *testing.T) {
 
 func TestTriggerHandler_PassesRawObjectPath(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
 
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
 	rec := httptest.NewRecorder()
@@ -85,7 +166,7 @@ func TestTriggerHandler_PassesRawObjectPath(t *testing.T) {
 
 func TestTriggerHandler_PassesHLSBucket(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "my-hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "my-hls-bucket")
 
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
 	rec := httptest.NewRecorder()
@@ -97,9 +178,25 @@ func TestTriggerHandler_PassesHLSBucket(t *testing.T) {
 	}
 }
 
+func TestTriggerHandler_PassesIdempotencyKey(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	body := `{"bucket":"mytube-raw-uploads","name":"raw/abc123.mp4","generation":"42"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	want := "mytube-raw-uploads/raw/abc123.mp4#42"
+	if exec.received.IdempotencyKey != want {
+		t.Errorf("unexpected IdempotencyKey: got %q, want %q", exec.received.IdempotencyKey, want)
+	}
+}
+
 func TestTriggerHandler_InvalidJSON_Returns400(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
 
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not-json"))
 	rec := httptest.NewRecorder()
@@ -116,7 +213,7 @@ func TestTriggerHandler_InvalidJSON_Returns400(t *testing.T) {
 
 func TestTriggerHandler_MissingBucket_Returns400(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
 
 	body := `{"name":"raw/abc.mp4"}`
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
@@ -131,7 +228,7 @@ func TestTriggerHandler_MissingBucket_Returns400(t *testing.T) {
 
 func TestTriggerHandler_MissingName_Returns400(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
 
 	body := `{"bucket":"mytube-raw-uploads"}`
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
@@ -146,7 +243,7 @@ func TestTriggerHandler_MissingName_Returns400(t *testing.T) {
 
 func TestTriggerHandler_UnextractableVideoID_Returns400(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
 
 	// Name with only an extension results in empty video ID.
 	body := `{"bucket":"b","name":"raw/.mp4"}`
@@ -162,7 +259,7 @@ func TestTriggerHandler_UnextractableVideoID_Returns400(t *testing.T) {
 
 func TestTriggerHandler_ExecutorError_Returns500(t *testing.T) {
 	exec := &mockExecutor{err: errors.New("cloud run api error")}
-	h := handler.NewTriggerHandler(exec, "hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
 
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
 	rec := httptest.NewRecorder()
@@ -174,9 +271,129 @@ func TestTriggerHandler_ExecutorError_Returns500(t *testing.T) {
 	}
 }
 
+func TestTriggerHandler_ExecutorError_MarksExecutionFailed(t *testing.T) {
+	exec := &mockExecutor{err: errors.New("cloud run api error")}
+	store := &stubStore{}
+	h := handler.NewTriggerHandler(exec, store, "hls-bucket")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if len(store.updates) != 1 || store.updates[0] != jobs.StatusFailed {
+		t.Errorf("expected a single StatusFailed update, got %v", store.updates)
+	}
+}
+
+func TestTriggerHandler_Success_MarksExecutionSucceeded(t *testing.T) {
+	exec := &mockExecutor{}
+	store := &stubStore{}
+	h := handler.NewTriggerHandler(exec, store, "hls-bucket")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if len(store.updates) != 1 || store.updates[0] != jobs.StatusSucceeded {
+		t.Errorf("expected a single StatusSucceeded update, got %v", store.updates)
+	}
+}
+
+// submitOnlyExecutor models a fire-and-forget executor like
+// CloudRunJobRunner with PollUntilTerminal unset, or KubernetesExecutor: it
+// does not implement jobs.TerminalExecutor, so Execute returning nil means
+// only that the job was submitted, not that it finished.
+type submitOnlyExecutor struct {
+	executionName string
+}
+
+func (e *submitOnlyExecutor) Execute(context.Context, jobs.ExecuteRequest) (string, error) {
+	return e.executionName, nil
+}
+
+func TestTriggerHandler_NonTerminalExecutor_DoesNotMarkSucceeded(t *testing.T) {
+	exec := &submitOnlyExecutor{executionName: "exec-1"}
+	store := &stubStore{}
+	h := handler.NewTriggerHandler(exec, store, "hls-bucket")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if len(store.updates) != 0 {
+		t.Errorf("expected no status update for a non-terminal executor's submission, got %v", store.updates)
+	}
+}
+
+func TestTriggerHandler_NonTerminalExecutor_DoesNotPublishSucceeded(t *testing.T) {
+	exec := &submitOnlyExecutor{executionName: "exec-1"}
+	pub := &fakePublisher{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket", handler.WithPublisher(pub))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if n := pub.countOf(notify.EventStarted); n != 1 {
+		t.Errorf("expected exactly 1 %s event, got %d", notify.EventStarted, n)
+	}
+	if n := pub.countOf(notify.EventSucceeded); n != 0 {
+		t.Errorf("expected no %s event for a non-terminal executor's submission, got %d", notify.EventSucceeded, n)
+	}
+}
+
+func TestTriggerHandler_Success_PublishesOneStartedEvent(t *testing.T) {
+	exec := &mockExecutor{}
+	pub := &fakePublisher{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket", handler.WithPublisher(pub))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if n := pub.countOf(notify.EventStarted); n != 1 {
+		t.Errorf("expected exactly 1 %s event, got %d", notify.EventStarted, n)
+	}
+	if n := pub.countOf(notify.EventFailed); n != 0 {
+		t.Errorf("expected no %s event on success, got %d", notify.EventFailed, n)
+	}
+}
+
+func TestTriggerHandler_ExecutorError_PublishesFailedEventBefore500(t *testing.T) {
+	exec := &mockExecutor{err: errors.New("cloud run api error")}
+	pub := &fakePublisher{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket", handler.WithPublisher(pub))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	if n := pub.countOf(notify.EventFailed); n != 1 {
+		t.Errorf("expected exactly 1 %s event, got %d", notify.EventFailed, n)
+	}
+	if len(pub.events) == 0 || pub.events[len(pub.events)-1].Type != notify.EventFailed {
+		t.Error("expected the transcode.failed event to be published before the 500 response")
+	}
+}
+
 func TestTriggerHandler_EmptyBody_Returns400(t *testing.T) {
 	exec := &mockExecutor{}
-	h := handler.NewTriggerHandler(exec, "hls-bucket")
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
 
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
 	rec := httptest.NewRecorder()
@@ -187,3 +404,375 @@ func TestTriggerHandler_EmptyBody_Returns400(t *testing.T) {
 		t.Errorf("expected 400 for empty body, got %d", rec.Code)
 	}
 }
+
+// ── idempotency ────────────────────────────────────────────────────────────────
+
+func TestTriggerHandler_DuplicateDelivery_ShortCircuitsWith204(t *testing.T) {
+	exec := &mockExecutor{}
+	store := jobs.NewMemoryStore()
+	h := handler.NewTriggerHandler(exec, store, "hls-bucket")
+
+	first := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	h(httptest.NewRecorder(), first)
+	if !exec.called {
+		t.Fatal("expected executor to be called on first delivery")
+	}
+
+	exec.called = false
+	second := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+	h(rec, second)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for duplicate delivery, got %d", rec.Code)
+	}
+	if exec.called {
+		t.Error("executor must not run again for a duplicate delivery")
+	}
+}
+
+func TestTriggerHandler_DifferentGeneration_IsNotADuplicate(t *testing.T) {
+	exec := &mockExecutor{}
+	store := jobs.NewMemoryStore()
+	h := handler.NewTriggerHandler(exec, store, "hls-bucket")
+
+	first := `{"bucket":"b","name":"raw/abc.mp4","generation":"1"}`
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(first)))
+
+	exec.called = false
+	second := `{"bucket":"b","name":"raw/abc.mp4","generation":"2"}`
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(second)))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if !exec.called {
+		t.Error("expected executor to run for a different object generation")
+	}
+}
+
+// ── CloudEvents binding ───────────────────────────────────────────────────────
+
+func cloudEventRequest(ceType, ceID, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-type", ceType)
+	req.Header.Set("ce-source", "//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads")
+	if ceID != "" {
+		req.Header.Set("ce-id", ceID)
+	}
+	return req
+}
+
+func TestTriggerHandler_CloudEvent_Success(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := cloudEventRequest("google.cloud.storage.object.v1.finalized", "event-1", validBody())
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if !exec.called {
+		t.Error("expected executor to be called")
+	}
+	if exec.received.IdempotencyKey != "event-1" {
+		t.Errorf("expected idempotency key to be the ce-id, got %q", exec.received.IdempotencyKey)
+	}
+	if exec.received.CEEventID != "event-1" {
+		t.Errorf("expected CEEventID to be the ce-id, got %q", exec.received.CEEventID)
+	}
+}
+
+func TestTriggerHandler_CloudEvent_UnsupportedType_Returns204WithoutCallingExecutor(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := cloudEventRequest("google.cloud.storage.object.v1.deleted", "event-1", validBody())
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for an unsupported event type, got %d", rec.Code)
+	}
+	if exec.called {
+		t.Error("executor must not be called for an unsupported event type")
+	}
+}
+
+func TestTriggerHandler_CloudEvent_MissingCeID_Returns400(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := cloudEventRequest("google.cloud.storage.object.v1.finalized", "", validBody())
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestTriggerHandler_LegacyEnvelope_LeavesCEEventIDEmpty(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	h(httptest.NewRecorder(), req)
+
+	if exec.received.CEEventID != "" {
+		t.Errorf("expected empty CEEventID for the legacy envelope, got %q", exec.received.CEEventID)
+	}
+}
+
+func TestTriggerHandler_CloudEvent_PassesFullMetadata(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := cloudEventRequest("google.cloud.storage.object.v1.finalized", "event-1", validBody())
+	req.Header.Set("ce-subject", "objects/raw/abc123.mp4")
+	req.Header.Set("ce-time", "2026-07-29T00:00:00Z")
+
+	h(httptest.NewRecorder(), req)
+
+	if exec.received.CESource != "//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads" {
+		t.Errorf("unexpected CESource: %q", exec.received.CESource)
+	}
+	if exec.received.CEType != "google.cloud.storage.object.v1.finalized" {
+		t.Errorf("unexpected CEType: %q", exec.received.CEType)
+	}
+	if exec.received.CESubject != "objects/raw/abc123.mp4" {
+		t.Errorf("unexpected CESubject: %q", exec.received.CESubject)
+	}
+	if exec.received.CETime != "2026-07-29T00:00:00Z" {
+		t.Errorf("unexpected CETime: %q", exec.received.CETime)
+	}
+}
+
+// ── structured CloudEvent envelope ────────────────────────────────────────────
+
+func structuredCloudEventBody(ceType string) string {
+	return `{"specversion":"1.0","id":"event-2","source":"//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads",` +
+		`"type":"` + ceType + `","subject":"objects/raw/abc123.mp4","time":"2026-07-29T00:00:00Z","data":` + validBody() + `}`
+}
+
+func structuredCloudEventRequest(ceType string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(structuredCloudEventBody(ceType)))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	return req
+}
+
+func TestTriggerHandler_StructuredCloudEvent_Success(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := structuredCloudEventRequest("google.cloud.storage.object.v1.finalized")
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if exec.received.VideoID != "abc123" {
+		t.Errorf("unexpected VideoID: %q", exec.received.VideoID)
+	}
+	if exec.received.CEEventID != "event-2" {
+		t.Errorf("expected CEEventID to be the structured event's id, got %q", exec.received.CEEventID)
+	}
+}
+
+func TestTriggerHandler_StructuredCloudEvent_UnsupportedType_Returns204WithoutCallingExecutor(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := structuredCloudEventRequest("google.cloud.storage.object.v1.deleted")
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for an unsupported event type, got %d", rec.Code)
+	}
+	if exec.called {
+		t.Error("executor must not be called for an unsupported event type")
+	}
+}
+
+// ── Pub/Sub push binary CloudEvent ────────────────────────────────────────────
+
+func pubsubPushBody(ceType string) string {
+	data := base64.StdEncoding.EncodeToString([]byte(validBody()))
+	return fmt.Sprintf(`{"message":{"data":%q,"attributes":{"ce-id":"event-3","ce-type":%q,`+
+		`"ce-source":"//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads",`+
+		`"ce-subject":"objects/raw/abc123.mp4"},"messageId":"123456"},"subscription":"projects/p/subscriptions/s"}`,
+		data, ceType)
+}
+
+func TestTriggerHandler_PubSubPush_Success(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(pubsubPushBody("google.cloud.storage.object.v1.finalized")))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if exec.received.VideoID != "abc123" {
+		t.Errorf("unexpected VideoID: %q", exec.received.VideoID)
+	}
+	if exec.received.CEEventID != "event-3" {
+		t.Errorf("expected CEEventID to be the ce-id attribute, got %q", exec.received.CEEventID)
+	}
+}
+
+func TestTriggerHandler_PubSubPush_UnsupportedType_Returns204WithoutCallingExecutor(t *testing.T) {
+	exec := &mockExecutor{}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(pubsubPushBody("google.cloud.storage.object.v1.deleted")))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for an unsupported event type, got %d", rec.Code)
+	}
+	if exec.called {
+		t.Error("executor must not be called for an unsupported event type")
+	}
+}
+
+// ── Deduper pre-check ──────────────────────────────────────────────────────────
+
+func TestTriggerHandler_DeduperSeen_ShortCircuitsWith204WithoutStoreOrExecutor(t *testing.T) {
+	exec := &mockExecutor{}
+	store := &stubStore{}
+	dedup := &stubDeduper{seen: true}
+	h := handler.NewTriggerHandler(exec, store, "hls-bucket", handler.WithDeduper(dedup))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if exec.called {
+		t.Error("executor must not run when the deduper reports a duplicate")
+	}
+	if store.createCalls != 0 {
+		t.Errorf("expected store.Create not to be called, got %d calls", store.createCalls)
+	}
+}
+
+func TestTriggerHandler_DeduperNotSeen_MarksSeenAfterSuccess(t *testing.T) {
+	exec := &mockExecutor{}
+	dedup := &stubDeduper{seen: false}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket", handler.WithDeduper(dedup))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if !exec.called {
+		t.Error("expected executor to be called")
+	}
+	if dedup.markCalls != 1 {
+		t.Errorf("expected MarkSeen to be called once, got %d", dedup.markCalls)
+	}
+}
+
+func TestTriggerHandler_DeduperError_FailsOpenAndStillExecutes(t *testing.T) {
+	exec := &mockExecutor{}
+	dedup := &stubDeduper{seenErr: errors.New("dedup store unavailable")}
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket", handler.WithDeduper(dedup))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	if !exec.called {
+		t.Error("expected executor to still be called when the deduper fails open")
+	}
+}
+
+// ── JobStore recording ───────────────────────────────────────────────────────
+
+func TestTriggerHandler_Success_RecordsJobWithExecutionName(t *testing.T) {
+	exec := &mockExecutor{executionName: "projects/p/locations/r/jobs/j/executions/e-1"}
+	jobStore := jobs.NewMemoryJobStore()
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket", handler.WithJobStore(jobStore))
+
+	body := `{"bucket":"b","name":"raw/550e8400-e29b-41d4-a716-446655440000.mp4"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	got, ok, err := jobStore.Get(context.Background(), "550e8400-e29b-41d4-a716-446655440000")
+	if err != nil || !ok {
+		t.Fatalf("expected a recorded job, got ok=%v err=%v", ok, err)
+	}
+	if got.ExecutionName != "projects/p/locations/r/jobs/j/executions/e-1" {
+		t.Errorf("unexpected ExecutionName: %q", got.ExecutionName)
+	}
+	if got.State != jobs.StatusSucceeded {
+		t.Errorf("expected StatusSucceeded, got %q", got.State)
+	}
+}
+
+func TestTriggerHandler_ExecutorError_RecordsJobFailed(t *testing.T) {
+	exec := &mockExecutor{err: errors.New("cloud run api error")}
+	jobStore := jobs.NewMemoryJobStore()
+	h := handler.NewTriggerHandler(exec, jobs.NewMemoryStore(), "hls-bucket", handler.WithJobStore(jobStore))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	got, ok, err := jobStore.Get(context.Background(), "abc123")
+	if err != nil || !ok {
+		t.Fatalf("expected a recorded job, got ok=%v err=%v", ok, err)
+	}
+	if got.State != jobs.StatusFailed {
+		t.Errorf("expected StatusFailed, got %q", got.State)
+	}
+}
+
+func TestTriggerHandler_StoreCreateError_Returns500(t *testing.T) {
+	exec := &mockExecutor{}
+	store := &stubStore{createErr: errors.New("store unavailable")}
+	h := handler.NewTriggerHandler(exec, store, "hls-bucket")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(validBody()))
+	rec := httptest.NewRecorder()
+
+	h(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	if exec.called {
+		t.Error("executor must not run when the store is unavailable")
+	}
+}
@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultBatchConcurrency bounds how many ExecuteBatch requests run at once
+// when BatchConcurrency is unset.
+const defaultBatchConcurrency = 8
+
+// ExecuteResult is the outcome of one ExecuteRequest within a batch.
+type ExecuteResult struct {
+	Request       ExecuteRequest
+	ExecutionName string
+	Err           error
+}
+
+// BatchConcurrency bounds how many concurrent run-job calls ExecuteBatch
+// issues. Zero means defaultBatchConcurrency; a negative value disables
+// parallelism and falls back to serial dispatch (e.g. for callers who need
+// predictable ordering or are debugging rate limits).
+func (r *CloudRunJobRunner) batchConcurrency() int {
+	if r.BatchConcurrency < 0 {
+		return 1
+	}
+	if r.BatchConcurrency == 0 {
+		return defaultBatchConcurrency
+	}
+	return r.BatchConcurrency
+}
+
+// ExecuteBatch runs multiple job executions as a single logical operation:
+// it fetches one access token up front (shared across every item rather than
+// re-fetched per Execute call) and dispatches requests with bounded
+// parallelism, collecting a per-item result instead of failing fast. This
+// matters when a Pub/Sub push delivers many raw/*.mp4 events in one batch.
+//
+// If token acquisition itself fails, ExecuteBatch returns that error rather
+// than per-item results, since no request could possibly succeed.
+func (r *CloudRunJobRunner) ExecuteBatch(ctx context.Context, reqs []ExecuteRequest) ([]ExecuteResult, error) {
+	token, _, err := r.tokenSource().Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ExecuteResult, len(reqs))
+	concurrency := r.batchConcurrency()
+
+	if concurrency <= 1 {
+		for i, req := range reqs {
+			name, err := r.executeWithToken(ctx, req, token)
+			results[i] = ExecuteResult{Request: req, ExecutionName: name, Err: err}
+		}
+		return results, nil
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, req ExecuteRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			name, err := r.executeWithToken(ctx, req, token)
+			results[i] = ExecuteResult{Request: req, ExecutionName: name, Err: err}
+		}(i, req)
+	}
+	wg.Wait()
+
+	return results, nil
+}
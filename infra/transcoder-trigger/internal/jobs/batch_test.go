@@ -0,0 +1,153 @@
+package jobs_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+// concurrencyTrackingDoer records the peak number of concurrent Do calls and
+// always succeeds, simulating the metadata + run-job round trips.
+type concurrencyTrackingDoer struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	calls    int
+}
+
+func (d *concurrencyTrackingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.mu.Lock()
+	d.inFlight++
+	if d.inFlight > d.peak {
+		d.peak = d.inFlight
+	}
+	d.calls++
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		d.inFlight--
+		d.mu.Unlock()
+	}()
+
+	if strings.Contains(req.URL.String(), "metadata.google.internal") {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"access_token":"shared-tok","expires_in":3599}`)),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"name":"exec"}`)),
+	}, nil
+}
+
+func TestExecuteBatch_SharesOneTokenFetch(t *testing.T) {
+	doer := &concurrencyTrackingDoer{}
+	runner := &jobs.CloudRunJobRunner{
+		Project: "p", Region: "r", JobName: "j", Client: doer,
+	}
+
+	reqs := make([]jobs.ExecuteRequest, 5)
+	for i := range reqs {
+		reqs[i] = jobs.ExecuteRequest{VideoID: fmt.Sprintf("vid-%d", i)}
+	}
+
+	results, err := runner.ExecuteBatch(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(reqs) {
+		t.Fatalf("got %d results, want %d", len(results), len(reqs))
+	}
+	for _, res := range results {
+		if res.Err != nil {
+			t.Errorf("unexpected per-item error: %v", res.Err)
+		}
+	}
+	// One metadata call + one run call per request = len(reqs)+1 total calls.
+	if doer.calls != len(reqs)+1 {
+		t.Errorf("expected %d total HTTP calls (1 token fetch + %d run calls), got %d", len(reqs)+1, len(reqs), doer.calls)
+	}
+}
+
+func TestExecuteBatch_RespectsConcurrencyBound(t *testing.T) {
+	doer := &concurrencyTrackingDoer{}
+	runner := &jobs.CloudRunJobRunner{
+		Project: "p", Region: "r", JobName: "j", Client: doer, BatchConcurrency: 2,
+	}
+
+	reqs := make([]jobs.ExecuteRequest, 10)
+	if _, err := runner.ExecuteBatch(context.Background(), reqs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if doer.peak > 2 {
+		t.Errorf("peak concurrent HTTP calls = %d, want <= 2", doer.peak)
+	}
+}
+
+func TestExecuteBatch_NegativeConcurrencyRunsSerially(t *testing.T) {
+	doer := &concurrencyTrackingDoer{}
+	runner := &jobs.CloudRunJobRunner{
+		Project: "p", Region: "r", JobName: "j", Client: doer, BatchConcurrency: -1,
+	}
+
+	reqs := make([]jobs.ExecuteRequest, 4)
+	if _, err := runner.ExecuteBatch(context.Background(), reqs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doer.peak > 1 {
+		t.Errorf("expected serial dispatch, peak concurrency = %d", doer.peak)
+	}
+}
+
+func TestExecuteBatch_PerItemErrorDoesNotFailOthers(t *testing.T) {
+	runner := &jobs.CloudRunJobRunner{
+		Project: "p", Region: "r", JobName: "j",
+		Client: &partialFailureDoer{},
+	}
+
+	reqs := []jobs.ExecuteRequest{{VideoID: "a"}, {VideoID: "b"}, {VideoID: "c"}}
+	results, err := runner.ExecuteBatch(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("unexpected batch-level error: %v", err)
+	}
+
+	var failures int
+	for _, res := range results {
+		if res.Err != nil {
+			failures++
+		}
+	}
+	if failures != 1 {
+		t.Errorf("expected exactly 1 per-item failure, got %d", failures)
+	}
+}
+
+// partialFailureDoer succeeds on the token fetch and on run calls for every
+// video ID except "b", which it fails.
+type partialFailureDoer struct {
+	mu sync.Mutex
+}
+
+func (d *partialFailureDoer) Do(req *http.Request) (*http.Response, error) {
+	if strings.Contains(req.URL.String(), "metadata.google.internal") {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"access_token":"tok","expires_in":3599}`)),
+		}, nil
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	if strings.Contains(string(body), `"VIDEO_ID","value":"b"`) {
+		return &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader("denied"))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultExecutionsCollection is the Firestore collection FirestoreStore
+// uses when Collection is unset.
+const defaultExecutionsCollection = "transcoder-job-executions"
+
+// executionDoc is the Firestore document shape for one recorded execution,
+// keyed by the idempotency key as the document ID.
+type executionDoc struct {
+	ExecutionName string    `firestore:"executionName"`
+	Status        string    `firestore:"status"`
+	CreatedAt     time.Time `firestore:"createdAt"`
+}
+
+// FirestoreStore is the production Store, backed by a Firestore collection
+// in the same GCP project as the Cloud Run Job. Document creation uses
+// Firestore's own exists-precondition (DocumentRef.Create), so the
+// INSERT-IF-NOT-EXISTS Store.Create requires is atomic without a
+// client-side transaction.
+type FirestoreStore struct {
+	Client *firestore.Client
+	// Collection overrides defaultExecutionsCollection when set.
+	Collection string
+}
+
+// NewFirestoreStore constructs a FirestoreStore backed by client, recording
+// documents in collection (or defaultExecutionsCollection if empty).
+func NewFirestoreStore(client *firestore.Client, collection string) *FirestoreStore {
+	return &FirestoreStore{Client: client, Collection: collection}
+}
+
+func (s *FirestoreStore) collection() string {
+	if s.Collection == "" {
+		return defaultExecutionsCollection
+	}
+	return s.Collection
+}
+
+func (s *FirestoreStore) doc(key string) *firestore.DocumentRef {
+	return s.Client.Collection(s.collection()).Doc(key)
+}
+
+func (s *FirestoreStore) Create(ctx context.Context, key, executionName string) error {
+	_, err := s.doc(key).Create(ctx, executionDoc{
+		ExecutionName: executionName,
+		Status:        string(StatusRunning),
+		CreatedAt:     time.Now(),
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return ErrAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("create execution record for %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *FirestoreStore) UpdateStatus(ctx context.Context, key string, st ExecutionStatus) error {
+	_, err := s.doc(key).Update(ctx, []firestore.Update{
+		{Path: "status", Value: string(st)},
+	})
+	if err != nil {
+		return fmt.Errorf("update execution status for %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *FirestoreStore) Sweep(ctx context.Context, olderThan time.Time) (int, error) {
+	iter := s.Client.Collection(s.collection()).
+		Where("status", "==", string(StatusFailed)).
+		Where("createdAt", "<", olderThan).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var n int
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return n, fmt.Errorf("sweep expired executions: %w", err)
+		}
+		if _, err := doc.Ref.Delete(ctx); err != nil {
+			return n, fmt.Errorf("sweep: delete %q: %w", doc.Ref.ID, err)
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// JobRecord is one tracked Cloud Run Job execution exposed via the
+// job-management HTTP surface (see handler.NewRouter). Unlike Store, which
+// is keyed by the triggering event's idempotency key, JobRecord is keyed by
+// VideoID — an operator looks a job up by the video they care about, not the
+// GCS event that launched it.
+type JobRecord struct {
+	VideoID       string
+	ExecutionName string
+	State         ExecutionStatus
+	StartedAt     time.Time
+}
+
+// JobStore tracks JobRecords for the job-management HTTP surface.
+// Implementations must be safe for concurrent use.
+type JobStore interface {
+	// Put records or replaces the row for rec.VideoID.
+	Put(ctx context.Context, rec JobRecord) error
+	// UpdateState transitions the row for videoID to state.
+	UpdateState(ctx context.Context, videoID string, state ExecutionStatus) error
+	// Get returns the row for videoID, or ok=false if none is recorded.
+	Get(ctx context.Context, videoID string) (rec JobRecord, ok bool, err error)
+	// List returns every tracked row, in no particular order.
+	List(ctx context.Context) ([]JobRecord, error)
+}
+
+// MemoryJobStore is an in-memory JobStore, the default NewTriggerHandler and
+// NewRouter use when none is configured.
+type MemoryJobStore struct {
+	mu   sync.Mutex
+	rows map[string]JobRecord
+}
+
+// NewMemoryJobStore constructs an empty MemoryJobStore.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{rows: make(map[string]JobRecord)}
+}
+
+func (m *MemoryJobStore) Put(_ context.Context, rec JobRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows[rec.VideoID] = rec
+	return nil
+}
+
+func (m *MemoryJobStore) UpdateState(_ context.Context, videoID string, state ExecutionStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.rows[videoID]
+	if !ok {
+		return fmt.Errorf("jobs: update job state: video %q not recorded", videoID)
+	}
+	rec.State = state
+	m.rows[videoID] = rec
+	return nil
+}
+
+func (m *MemoryJobStore) Get(_ context.Context, videoID string) (JobRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.rows[videoID]
+	return rec, ok, nil
+}
+
+func (m *MemoryJobStore) List(_ context.Context) ([]JobRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]JobRecord, 0, len(m.rows))
+	for _, rec := range m.rows {
+		out = append(out, rec)
+	}
+	return out, nil
+}
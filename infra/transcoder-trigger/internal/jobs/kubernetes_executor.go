@@ -0,0 +1,96 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultBackoffLimit is how many times Kubernetes retries a failed
+// transcoder Pod before marking the Job failed.
+const defaultBackoffLimit = 2
+
+// KubernetesExecutor runs the transcoder as a Kubernetes batch/v1 Job
+// instead of dispatching to Cloud Run Jobs, for on-prem and self-managed
+// cluster deployments.
+type KubernetesExecutor struct {
+	// Client is the Kubernetes API client. Use kubernetes.NewForConfig in
+	// production, or k8s.io/client-go/kubernetes/fake in tests.
+	Client kubernetes.Interface
+	// Namespace is the namespace the Job is created in. Defaults to
+	// "default" when empty.
+	Namespace string
+	// Image is the transcoder container image the Job's Pod runs.
+	Image string
+	// BackoffLimit overrides defaultBackoffLimit when set.
+	BackoffLimit *int32
+}
+
+// NewKubernetesExecutor constructs a KubernetesExecutor that creates Jobs
+// running image in namespace via client.
+func NewKubernetesExecutor(client kubernetes.Interface, namespace, image string) *KubernetesExecutor {
+	return &KubernetesExecutor{Client: client, Namespace: namespace, Image: image}
+}
+
+// namespace returns e.Namespace, falling back to "default".
+func (e *KubernetesExecutor) namespace() string {
+	if e.Namespace == "" {
+		return "default"
+	}
+	return e.Namespace
+}
+
+// backoffLimit returns e.BackoffLimit, falling back to defaultBackoffLimit.
+func (e *KubernetesExecutor) backoffLimit() int32 {
+	if e.BackoffLimit != nil {
+		return *e.BackoffLimit
+	}
+	return defaultBackoffLimit
+}
+
+// Execute creates a batch/v1 Job running e.Image with the per-video env var
+// overrides, mirroring the Cloud Run Jobs container overrides. It returns the
+// created Job's name, for the job-management HTTP surface (see
+// handler.NewRouter) to track. Like CloudRunJobRunner with PollUntilTerminal
+// unset, it returns as soon as the Job is created — KubernetesExecutor does
+// not implement RunsToTerminal, so a nil error here must not be read as the
+// transcode having succeeded.
+func (e *KubernetesExecutor) Execute(ctx context.Context, req ExecuteRequest) (string, error) {
+	backoffLimit := e.backoffLimit()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "mytube-transcoder-",
+			Namespace:    e.namespace(),
+			Labels:       map[string]string{"app": "mytube-transcoder", "video-id": req.VideoID},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:  "transcoder",
+							Image: e.Image,
+							Env: []corev1.EnvVar{
+								{Name: "RAW_OBJECT_PATH", Value: req.RawObjectPath},
+								{Name: "VIDEO_ID", Value: req.VideoID},
+								{Name: "HLS_BUCKET", Value: req.HLSBucket},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	created, err := e.Client.BatchV1().Jobs(e.namespace()).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("create transcoder job for video %s: %w", req.VideoID, err)
+	}
+	return created.Name, nil
+}
@@ -0,0 +1,97 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+func TestKubernetesExecutor_Execute_CreatesJobInNamespace(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	e := jobs.NewKubernetesExecutor(client, "mytube", "gcr.io/mytube/transcoder:latest")
+
+	if _, err := e.Execute(context.Background(), jobs.ExecuteRequest{VideoID: "vid"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jobList, err := client.BatchV1().Jobs("mytube").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobList.Items) != 1 {
+		t.Fatalf("expected 1 job created, got %d", len(jobList.Items))
+	}
+}
+
+func TestKubernetesExecutor_Execute_DefaultsNamespace(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	e := &jobs.KubernetesExecutor{Client: client, Image: "gcr.io/mytube/transcoder:latest"}
+
+	if _, err := e.Execute(context.Background(), jobs.ExecuteRequest{VideoID: "vid"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jobList, err := client.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobList.Items) != 1 {
+		t.Fatalf("expected 1 job in default namespace, got %d", len(jobList.Items))
+	}
+}
+
+func TestKubernetesExecutor_Execute_ReturnsCreatedJobName(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	e := jobs.NewKubernetesExecutor(client, "mytube", "gcr.io/mytube/transcoder:latest")
+
+	name, err := e.Execute(context.Background(), jobs.ExecuteRequest{VideoID: "vid"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name == "" {
+		t.Error("expected a non-empty execution name")
+	}
+
+	jobList, err := client.BatchV1().Jobs("mytube").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobList.Items) != 1 || jobList.Items[0].Name != name {
+		t.Errorf("expected the returned name to match the created job's name, got %q", name)
+	}
+}
+
+func TestKubernetesExecutor_Execute_SetsEnvVarsAndImage(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	e := jobs.NewKubernetesExecutor(client, "mytube", "gcr.io/mytube/transcoder:latest")
+
+	_, _ = e.Execute(context.Background(), jobs.ExecuteRequest{
+		RawObjectPath: "raw/abc.mp4",
+		VideoID:       "abc",
+		HLSBucket:     "mytube-hls-output",
+	})
+
+	jobList, _ := client.BatchV1().Jobs("mytube").List(context.Background(), metav1.ListOptions{})
+	container := jobList.Items[0].Spec.Template.Spec.Containers[0]
+	if container.Image != "gcr.io/mytube/transcoder:latest" {
+		t.Errorf("image = %q, want gcr.io/mytube/transcoder:latest", container.Image)
+	}
+
+	envMap := make(map[string]string)
+	for _, e := range container.Env {
+		envMap[e.Name] = e.Value
+	}
+	if envMap["RAW_OBJECT_PATH"] != "raw/abc.mp4" {
+		t.Errorf("RAW_OBJECT_PATH = %q", envMap["RAW_OBJECT_PATH"])
+	}
+	if envMap["VIDEO_ID"] != "abc" {
+		t.Errorf("VIDEO_ID = %q", envMap["VIDEO_ID"])
+	}
+	if envMap["HLS_BUCKET"] != "mytube-hls-output" {
+		t.Errorf("HLS_BUCKET = %q", envMap["HLS_BUCKET"])
+	}
+}
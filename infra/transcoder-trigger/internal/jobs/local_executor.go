@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// CommandRunner abstracts exec.CommandContext so tests can inject a stub
+// without shelling out to a real transcoder binary.
+type CommandRunner interface {
+	Run(ctx context.Context, name string, env []string) error
+}
+
+// ExecCommandRunner is the real CommandRunner that shells out to the system.
+type ExecCommandRunner struct{}
+
+// Run executes name with env appended to the current process's environment,
+// surfacing combined stdout/stderr on failure.
+func (ExecCommandRunner) Run(ctx context.Context, name string, env []string) error {
+	cmd := exec.CommandContext(ctx, name)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("transcoder exited with error: %w\noutput:\n%s", err, string(out))
+	}
+	return nil
+}
+
+// LocalExecutor runs the transcoder binary as a subprocess on the local
+// machine instead of dispatching to Cloud Run Jobs, so `docker-compose up`
+// and local end-to-end runs work without GCP credentials.
+type LocalExecutor struct {
+	// BinaryPath is the path to the transcoder binary. Defaults to
+	// "transcoder" (resolved via PATH) when empty.
+	BinaryPath string
+	// Cmd runs the subprocess; defaults to ExecCommandRunner{}.
+	Cmd CommandRunner
+}
+
+// NewLocalExecutor constructs a LocalExecutor that runs binaryPath using the
+// real ExecCommandRunner.
+func NewLocalExecutor(binaryPath string) *LocalExecutor {
+	return &LocalExecutor{BinaryPath: binaryPath, Cmd: ExecCommandRunner{}}
+}
+
+// binaryPath returns e.BinaryPath, falling back to "transcoder" resolved via
+// PATH when unset.
+func (e *LocalExecutor) binaryPath() string {
+	if e.BinaryPath == "" {
+		return "transcoder"
+	}
+	return e.BinaryPath
+}
+
+// cmdRunner returns e.Cmd, falling back to ExecCommandRunner{}.
+func (e *LocalExecutor) cmdRunner() CommandRunner {
+	if e.Cmd == nil {
+		return ExecCommandRunner{}
+	}
+	return e.Cmd
+}
+
+// RunsToTerminal always reports true: Execute shells out to the transcoder
+// binary and only returns once that subprocess has exited, so a nil error
+// means the transcode actually succeeded, not merely started.
+func (e *LocalExecutor) RunsToTerminal() bool {
+	return true
+}
+
+// Execute runs the transcoder binary as a subprocess, passing req's fields
+// as the same RAW_OBJECT_PATH/VIDEO_ID/HLS_BUCKET environment variables the
+// Cloud Run Job reads. It returns a synthetic execution name (there's no
+// real Cloud Run/Kubernetes execution to name), for the job-management HTTP
+// surface (see handler.NewRouter) to track alongside real executors.
+func (e *LocalExecutor) Execute(ctx context.Context, req ExecuteRequest) (string, error) {
+	env := []string{
+		"RAW_OBJECT_PATH=" + req.RawObjectPath,
+		"VIDEO_ID=" + req.VideoID,
+		"HLS_BUCKET=" + req.HLSBucket,
+	}
+	executionName := "local/" + req.VideoID
+	if err := e.cmdRunner().Run(ctx, e.binaryPath(), env); err != nil {
+		return "", fmt.Errorf("run transcoder locally for video %s: %w", req.VideoID, err)
+	}
+	return executionName, nil
+}
@@ -0,0 +1,98 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+// stubCommandRunner implements jobs.CommandRunner and records calls.
+type stubCommandRunner struct {
+	err   error
+	name  string
+	env   []string
+	calls int
+}
+
+func (s *stubCommandRunner) Run(_ context.Context, name string, env []string) error {
+	s.calls++
+	s.name = name
+	s.env = env
+	return s.err
+}
+
+func TestNewLocalExecutor_Fields(t *testing.T) {
+	e := jobs.NewLocalExecutor("/usr/local/bin/transcoder")
+	if e.BinaryPath != "/usr/local/bin/transcoder" {
+		t.Errorf("BinaryPath = %q, want /usr/local/bin/transcoder", e.BinaryPath)
+	}
+	if e.Cmd == nil {
+		t.Error("expected non-nil Cmd")
+	}
+}
+
+func TestLocalExecutor_Execute_RunsConfiguredBinary(t *testing.T) {
+	stub := &stubCommandRunner{}
+	e := &jobs.LocalExecutor{BinaryPath: "/opt/transcoder", Cmd: stub}
+
+	if _, err := e.Execute(context.Background(), jobs.ExecuteRequest{VideoID: "vid"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.name != "/opt/transcoder" {
+		t.Errorf("ran binary %q, want /opt/transcoder", stub.name)
+	}
+}
+
+func TestLocalExecutor_Execute_DefaultsBinaryPathToTranscoder(t *testing.T) {
+	stub := &stubCommandRunner{}
+	e := &jobs.LocalExecutor{Cmd: stub}
+
+	_, _ = e.Execute(context.Background(), jobs.ExecuteRequest{})
+	if stub.name != "transcoder" {
+		t.Errorf("ran binary %q, want transcoder", stub.name)
+	}
+}
+
+func TestLocalExecutor_Execute_PassesEnvVars(t *testing.T) {
+	stub := &stubCommandRunner{}
+	e := &jobs.LocalExecutor{Cmd: stub}
+
+	_, _ = e.Execute(context.Background(), jobs.ExecuteRequest{
+		RawObjectPath: "raw/abc.mp4",
+		VideoID:       "abc",
+		HLSBucket:     "mytube-hls-output",
+	})
+
+	want := map[string]bool{
+		"RAW_OBJECT_PATH=raw/abc.mp4":  false,
+		"VIDEO_ID=abc":                 false,
+		"HLS_BUCKET=mytube-hls-output": false,
+	}
+	for _, e := range stub.env {
+		if _, ok := want[e]; ok {
+			want[e] = true
+		}
+	}
+	for env, found := range want {
+		if !found {
+			t.Errorf("env vars %v missing %q", stub.env, env)
+		}
+	}
+}
+
+func TestLocalExecutor_Execute_PropagatesError(t *testing.T) {
+	stub := &stubCommandRunner{err: errors.New("exit status 1")}
+	e := &jobs.LocalExecutor{Cmd: stub}
+
+	if _, err := e.Execute(context.Background(), jobs.ExecuteRequest{VideoID: "vid"}); err == nil {
+		t.Fatal("expected error to be propagated")
+	}
+}
+
+func TestLocalExecutor_RunsToTerminal(t *testing.T) {
+	if !(&jobs.LocalExecutor{}).RunsToTerminal() {
+		t.Error("expected LocalExecutor.RunsToTerminal() to always be true")
+	}
+}
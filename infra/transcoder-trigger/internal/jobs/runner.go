@@ -4,15 +4,50 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// Executor executes a Cloud Run Job for a given raw GCS object.
+// ErrPermanent marks a job-execution error as non-retryable (e.g. a 4xx
+// response from the Cloud Run Jobs API, such as a malformed request), so
+// handler.RetryPolicy's classifier can send it straight to a dead-letter
+// sink instead of burning retries on it.
+var ErrPermanent = errors.New("jobs: permanent execution error")
+
+const (
+	// defaultMaxRunRetries bounds how many times a run-job call is retried on
+	// a 429/5xx response before giving up.
+	defaultMaxRunRetries = 3
+	// runRetryBaseDelay is the first backoff delay between run-job retries;
+	// it doubles on each subsequent attempt (200ms, 400ms, 800ms, ...).
+	runRetryBaseDelay = 200 * time.Millisecond
+	// defaultPollInterval is how often Execute polls the run-job LRO when
+	// PollUntilTerminal is set.
+	defaultPollInterval = 2 * time.Second
+)
+
+// Executor executes a Cloud Run Job for a given raw GCS object, returning the
+// resulting execution's name for the job-management HTTP surface (see
+// handler.NewRouter) to track.
 type Executor interface {
-	Execute(ctx context.Context, req ExecuteRequest) error
+	Execute(ctx context.Context, req ExecuteRequest) (executionName string, err error)
+}
+
+// TerminalExecutor is implemented by an Executor whose Execute call blocks
+// until the underlying job reaches a terminal state (succeeded or failed)
+// rather than returning as soon as it's merely submitted. Callers that want
+// to report a transcode as actually succeeded from Execute's return (rather
+// than just started) must type-assert for this first — *CloudRunJobRunner
+// only satisfies it with PollUntilTerminal set, and *KubernetesExecutor
+// never does.
+type TerminalExecutor interface {
+	Executor
+	RunsToTerminal() bool
 }
 
 // ExecuteRequest carries the per-execution environment variable overrides
@@ -24,6 +59,33 @@ type ExecuteRequest struct {
 	VideoID string
 	// HLSBucket is the destination bucket for HLS output.
 	HLSBucket string
+	// IdempotencyKey identifies the GCS object generation this request was
+	// raised for (see event.StorageObject.IdempotencyKey). It is recorded
+	// in a Store by the handler before Execute is called, so a duplicate
+	// at-least-once delivery of the same finalize event short-circuits
+	// instead of launching a second execution; Execute itself doesn't
+	// interpret it.
+	IdempotencyKey string
+	// CEEventID is the CloudEvents 1.0 ce-id, set only when the triggering
+	// request carried CloudEvent metadata (see event.ParseRequest) rather
+	// than the legacy Eventarc envelope. It is passed through to the job as
+	// CE_EVENT_ID so its logs can be correlated back to the originating
+	// event.
+	CEEventID string
+	// CESource, CEType, CESubject, and CETime are the triggering event's
+	// remaining CloudEvent attributes (ce-source, ce-type, ce-subject,
+	// ce-time), passed through as CE_SOURCE/CE_TYPE/CE_SUBJECT/CE_TIME
+	// alongside CEEventID for the same reason. Empty for the legacy
+	// envelope.
+	CESource  string
+	CEType    string
+	CESubject string
+	CETime    string
+	// Attempt is the 1-based attempt number for this Execute call, set by
+	// the handler's retry loop (see handler.RetryPolicy) so a Cloud Run
+	// execution retried after a transient failure can be told apart from
+	// the original attempt via its labels. Zero (unset) omits the label.
+	Attempt int
 }
 
 // HTTPDoer abstracts http.Client.Do so that tests can inject a stub.
@@ -32,23 +94,87 @@ type HTTPDoer interface {
 }
 
 // CloudRunJobRunner calls the Cloud Run Jobs API over HTTP to execute a job.
-// It uses the metadata server token (Application Default Credentials) when
-// running inside Cloud Run.
+// It authenticates via Tokens, which defaults to the GCE metadata server
+// (Application Default Credentials) wrapped in a CachingTokenSource so the
+// metadata server is hit at most once per token lifetime rather than once per
+// job trigger.
 type CloudRunJobRunner struct {
 	Project string
 	Region  string
 	JobName string
 	Client  HTTPDoer
+	Tokens  TokenSource
+	// BatchConcurrency bounds parallelism in ExecuteBatch. See batchConcurrency.
+	BatchConcurrency int
+	// Signer authenticates the run-job request. When nil, the bearer token
+	// from Tokens is attached directly (see signRequest), preserving today's
+	// Cloud Run Jobs behaviour; set it to a SigV4Signer or GCSHMACSigner to
+	// dispatch to a different provider.
+	Signer RequestSigner
+	// MaxRetries bounds how many times a run-job call is retried on a
+	// 429/5xx response, with exponential backoff starting at
+	// runRetryBaseDelay. Defaults to defaultMaxRunRetries when zero.
+	MaxRetries int
+	// PollUntilTerminal, if true, makes Execute poll the run-job LRO after
+	// submission until it reaches a terminal state, so callers can
+	// distinguish "submitted" from "succeeded". When false (the default),
+	// Execute returns as soon as the execution is accepted, matching
+	// today's fire-and-forget behaviour.
+	PollUntilTerminal bool
+	// PollInterval is the delay between LRO polls when PollUntilTerminal is
+	// set. Defaults to defaultPollInterval when zero.
+	PollInterval time.Duration
+}
+
+// maxRetries returns r.MaxRetries, falling back to defaultMaxRunRetries.
+func (r *CloudRunJobRunner) maxRetries() int {
+	if r.MaxRetries <= 0 {
+		return defaultMaxRunRetries
+	}
+	return r.MaxRetries
+}
+
+// pollInterval returns r.PollInterval, falling back to defaultPollInterval.
+func (r *CloudRunJobRunner) pollInterval() time.Duration {
+	if r.PollInterval <= 0 {
+		return defaultPollInterval
+	}
+	return r.PollInterval
 }
 
-// NewCloudRunJobRunner constructs a runner using the default http.Client.
+// NewCloudRunJobRunner constructs a runner using the default http.Client and a
+// cached GCE metadata-server TokenSource.
 func NewCloudRunJobRunner(project, region, jobName string) *CloudRunJobRunner {
 	return &CloudRunJobRunner{
 		Project: project,
 		Region:  region,
 		JobName: jobName,
 		Client:  &http.Client{},
+		Tokens:  NewCachingTokenSource(NewGCEMetadataTokenSource()),
+	}
+}
+
+// tokenSource returns r.Tokens, falling back to an uncached GCE metadata
+// source built from r.Client for runners constructed via a struct literal
+// rather than NewCloudRunJobRunner.
+func (r *CloudRunJobRunner) tokenSource() TokenSource {
+	if r.Tokens != nil {
+		return r.Tokens
+	}
+	return &GCEMetadataTokenSource{Client: r.Client}
+}
+
+// signRequest authenticates req. When Signer is set it takes full control
+// (e.g. AWS SigV4 for a non-Cloud-Run destination); otherwise the already
+// -fetched bearer token (shared across a batch, see ExecuteBatch) is attached
+// directly, preserving today's Cloud Run Jobs behaviour without a redundant
+// token fetch through a BearerSigner.
+func (r *CloudRunJobRunner) signRequest(ctx context.Context, req *http.Request, token string) error {
+	if r.Signer != nil {
+		return r.Signer.Sign(ctx, req)
 	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
 }
 
 // runJobURL returns the Cloud Run Jobs API endpoint for running an execution.
@@ -59,77 +185,202 @@ func (r *CloudRunJobRunner) runJobURL() string {
 	)
 }
 
-// accessToken fetches a short-lived access token from the GCE metadata server.
-// This works inside Cloud Run (and any GCE-based environment).
-func (r *CloudRunJobRunner) accessToken(ctx context.Context) (string, error) {
-	req, err := http.NewRequestWithContext(
+// RunsToTerminal reports whether Execute blocks until the execution reaches
+// a terminal state (succeeded or failed) rather than returning as soon as
+// it's merely submitted — true only when PollUntilTerminal is set. Callers
+// that report transcode.succeeded from Execute's return (see
+// handler.NewTriggerHandler) must check this first: with PollUntilTerminal
+// false, a nil error only means the run-job call was accepted.
+func (r *CloudRunJobRunner) RunsToTerminal() bool {
+	return r.PollUntilTerminal
+}
+
+// Execute calls the Cloud Run Jobs API to start an execution of the job,
+// passing the per-video environment variable overrides, and returns the
+// execution's resource name.
+func (r *CloudRunJobRunner) Execute(ctx context.Context, req ExecuteRequest) (string, error) {
+	token, _, err := r.tokenSource().Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get access token: %w", err)
+	}
+	return r.executeWithToken(ctx, req, token)
+}
+
+// executeWithToken is Execute's implementation given an already-fetched
+// token, shared by ExecuteBatch so a batch of requests pays for one token
+// fetch instead of one per item. A 429/5xx response is retried with
+// exponential backoff (see runRetryBaseDelay); any other error is returned
+// immediately. On success, if PollUntilTerminal is set, it polls the
+// returned execution LRO until it reaches a terminal state.
+func (r *CloudRunJobRunner) executeWithToken(ctx context.Context, req ExecuteRequest, token string) (string, error) {
+	body := buildRunBody(req)
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("marshal run body: %w", err)
+	}
+
+	backoff := runRetryBaseDelay
+	var lastErr error
+	for attempt := 0; attempt <= r.maxRetries(); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		op, retryable, err := r.runOnce(ctx, bodyBytes, token)
+		if err == nil {
+			if r.PollUntilTerminal {
+				return op.Name, r.pollOperation(ctx, op, token)
+			}
+			return op.Name, nil
+		}
+		lastErr = err
+		if !retryable {
+			return "", lastErr
+		}
+	}
+	return "", fmt.Errorf("run job API failed after %d attempts: %w", r.maxRetries()+1, lastErr)
+}
+
+// runOnce issues a single run-job API call, returning the decoded LRO on
+// success. retryable reports whether a non-nil err is worth retrying (a
+// 429/5xx status); transport-level errors are not retried.
+func (r *CloudRunJobRunner) runOnce(ctx context.Context, bodyBytes []byte, token string) (op runOperation, retryable bool, err error) {
+	httpReq, err := http.NewRequestWithContext(
 		ctx,
-		http.MethodGet,
-		"http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token",
-		nil,
+		http.MethodPost,
+		r.runJobURL(),
+		strings.NewReader(string(bodyBytes)),
 	)
 	if err != nil {
-		return "", fmt.Errorf("build metadata request: %w", err)
+		return runOperation{}, false, fmt.Errorf("build run request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if err := r.signRequest(ctx, httpReq, token); err != nil {
+		return runOperation{}, false, fmt.Errorf("sign run request: %w", err)
 	}
-	req.Header.Set("Metadata-Flavor", "Google")
 
-	resp, err := r.Client.Do(req)
+	resp, err := r.Client.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("metadata request: %w", err)
+		return runOperation{}, false, fmt.Errorf("run job request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("metadata server returned %d", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(resp.Body)
+		retryable := isRetryableStatus(resp.StatusCode)
+		err := fmt.Errorf("run job API returned %d: %s", resp.StatusCode, string(raw))
+		if !retryable {
+			err = fmt.Errorf("%w: %s", ErrPermanent, err)
+		}
+		return runOperation{}, retryable, err
 	}
 
-	var tok struct {
-		AccessToken string `json:"access_token"`
+	if err := json.NewDecoder(resp.Body).Decode(&op); err != nil {
+		return runOperation{}, false, fmt.Errorf("decode run job response: %w", err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
-		return "", fmt.Errorf("decode token response: %w", err)
+	return op, false, nil
+}
+
+// isRetryableStatus reports whether a run-job API response is worth
+// retrying: HTTP 429 (rate limited) or any 5xx (transient server error).
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// runOperation is the long-running-operation envelope the Cloud Run Jobs run
+// API returns: Name identifies the execution, and Done/Error report whether
+// (and how) it has finished.
+type runOperation struct {
+	Name  string `json:"name"`
+	Done  bool   `json:"done"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// operationURL returns the Cloud Run Jobs API endpoint for polling the LRO
+// identified by opName (the run API's response "name" field).
+func (r *CloudRunJobRunner) operationURL(opName string) string {
+	return fmt.Sprintf("https://%s-run.googleapis.com/v2/%s", r.Region, opName)
+}
+
+// pollOperation polls op's LRO until it reports Done, returning the error it
+// carries (if any) once terminal.
+func (r *CloudRunJobRunner) pollOperation(ctx context.Context, op runOperation, token string) error {
+	for !op.Done {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.pollInterval()):
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, r.operationURL(op.Name), nil)
+		if err != nil {
+			return fmt.Errorf("build operation poll request: %w", err)
+		}
+		if err := r.signRequest(ctx, httpReq, token); err != nil {
+			return fmt.Errorf("sign operation poll request: %w", err)
+		}
+
+		resp, err := r.Client.Do(httpReq)
+		if err != nil {
+			return fmt.Errorf("poll operation request: %w", err)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			raw, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return fmt.Errorf("poll operation API returned %d: %s", resp.StatusCode, string(raw))
+		}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&op)
+		resp.Body.Close()
+		if decodeErr != nil {
+			return fmt.Errorf("decode operation poll response: %w", decodeErr)
+		}
 	}
-	if tok.AccessToken == "" {
-		return "", fmt.Errorf("empty access token from metadata server")
+
+	if op.Error != nil {
+		return fmt.Errorf("execution %s failed: %s (code %d)", op.Name, op.Error.Message, op.Error.Code)
 	}
-	return tok.AccessToken, nil
+	return nil
 }
 
-// Execute calls the Cloud Run Jobs API to start an execution of the job,
-// passing the per-video environment variable overrides.
-func (r *CloudRunJobRunner) Execute(ctx context.Context, req ExecuteRequest) error {
-	token, err := r.accessToken(ctx)
+// cancelExecutionURL returns the Cloud Run Jobs API endpoint for cancelling
+// executionName, the fully-qualified resource name Execute returned.
+func (r *CloudRunJobRunner) cancelExecutionURL(executionName string) string {
+	return fmt.Sprintf("https://%s-run.googleapis.com/v2/%s:cancel", r.Region, executionName)
+}
+
+// CancelExecution calls the Cloud Run Jobs API to cancel a running
+// execution, for the job-management HTTP surface's DELETE /jobs/{videoID}
+// route (see handler.NewRouter).
+func (r *CloudRunJobRunner) CancelExecution(ctx context.Context, executionName string) error {
+	token, _, err := r.tokenSource().Token(ctx)
 	if err != nil {
 		return fmt.Errorf("get access token: %w", err)
 	}
 
-	body := buildRunBody(req)
-	bodyBytes, err := json.Marshal(body)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cancelExecutionURL(executionName), nil)
 	if err != nil {
-		return fmt.Errorf("marshal run body: %w", err)
+		return fmt.Errorf("build cancel request: %w", err)
 	}
-
-	httpReq, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		r.runJobURL(),
-		strings.NewReader(string(bodyBytes)),
-	)
-	if err != nil {
-		return fmt.Errorf("build run request: %w", err)
+	if err := r.signRequest(ctx, httpReq, token); err != nil {
+		return fmt.Errorf("sign cancel request: %w", err)
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+token)
 
 	resp, err := r.Client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("run job request: %w", err)
+		return fmt.Errorf("cancel execution request: %w", err)
 	}
 	defer resp.Body.Close()
-
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		raw, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("run job API returned %d: %s", resp.StatusCode, string(raw))
+		return fmt.Errorf("cancel execution API returned %d: %s", resp.StatusCode, string(raw))
 	}
 	return nil
 }
@@ -137,6 +388,11 @@ func (r *CloudRunJobRunner) Execute(ctx context.Context, req ExecuteRequest) err
 // runBody is the JSON payload for the Cloud Run Jobs run API.
 type runBody struct {
 	Overrides runOverrides `json:"overrides"`
+	// Labels annotate the resulting execution, e.g. "attempt" for a job
+	// launch retried by handler.RetryPolicy. Omitted only when
+	// ExecuteRequest.Attempt is zero; handler.NewTriggerHandler's first
+	// attempt sets it to 1, so that attempt is labelled too.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type runOverrides struct {
@@ -154,17 +410,35 @@ type envVar struct {
 
 // buildRunBody constructs the request body that overrides env vars for the job.
 func buildRunBody(req ExecuteRequest) runBody {
-	return runBody{
+	env := []envVar{
+		{Name: "RAW_OBJECT_PATH", Value: req.RawObjectPath},
+		{Name: "VIDEO_ID", Value: req.VideoID},
+		{Name: "HLS_BUCKET", Value: req.HLSBucket},
+	}
+	if req.CEEventID != "" {
+		env = append(env, envVar{Name: "CE_EVENT_ID", Value: req.CEEventID})
+	}
+	if req.CESource != "" {
+		env = append(env, envVar{Name: "CE_SOURCE", Value: req.CESource})
+	}
+	if req.CEType != "" {
+		env = append(env, envVar{Name: "CE_TYPE", Value: req.CEType})
+	}
+	if req.CESubject != "" {
+		env = append(env, envVar{Name: "CE_SUBJECT", Value: req.CESubject})
+	}
+	if req.CETime != "" {
+		env = append(env, envVar{Name: "CE_TIME", Value: req.CETime})
+	}
+	body := runBody{
 		Overrides: runOverrides{
 			ContainerOverrides: []containerOverride{
-				{
-					Env: []envVar{
-						{Name: "RAW_OBJECT_PATH", Value: req.RawObjectPath},
-						{Name: "VIDEO_ID", Value: req.VideoID},
-						{Name: "HLS_BUCKET", Value: req.HLSBucket},
-					},
-				},
+				{Env: env},
 			},
 		},
 	}
+	if req.Attempt > 0 {
+		body.Labels = map[string]string{"attempt": strconv.Itoa(req.Attempt)}
+	}
+	return body
 }
@@ -3,11 +3,13 @@ package jobs_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
 )
@@ -88,7 +90,7 @@ func TestExecute_Success(t *testing.T) {
 		Client:  doer,
 	}
 
-	err := runner.Execute(context.Background(), jobs.ExecuteRequest{
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{
 		RawObjectPath: "raw/abc.mp4",
 		VideoID:       "abc",
 		HLSBucket:     "mytube-hls-output",
@@ -114,7 +116,7 @@ func TestExecute_BearerTokenInRunRequest(t *testing.T) {
 		JobName: "mytube-transcoder",
 		Client:  doer,
 	}
-	_ = runner.Execute(context.Background(), jobs.ExecuteRequest{
+	_, _ = runner.Execute(context.Background(), jobs.ExecuteRequest{
 		RawObjectPath: "raw/uuid.mp4",
 		VideoID:       "uuid",
 		HLSBucket:     "hls-bucket",
@@ -141,7 +143,7 @@ func TestExecute_RunRequestBodyContainsEnvVars(t *testing.T) {
 		JobName: "mytube-transcoder",
 		Client:  doer,
 	}
-	_ = runner.Execute(context.Background(), jobs.ExecuteRequest{
+	_, _ = runner.Execute(context.Background(), jobs.ExecuteRequest{
 		RawObjectPath: "raw/vid.mp4",
 		VideoID:       "vid",
 		HLSBucket:     "my-hls",
@@ -183,6 +185,138 @@ func TestExecute_RunRequestBodyContainsEnvVars(t *testing.T) {
 	}
 }
 
+func TestExecute_RunRequestBodyContainsCEEventIDWhenSet(t *testing.T) {
+	doer := &stubDoer{
+		responses: []*http.Response{
+			okMetadataResponse("tok"),
+			okRunResponse(),
+		},
+	}
+	runner := &jobs.CloudRunJobRunner{
+		Project: "proj",
+		Region:  "us-central1",
+		JobName: "mytube-transcoder",
+		Client:  doer,
+	}
+	_, _ = runner.Execute(context.Background(), jobs.ExecuteRequest{
+		RawObjectPath: "raw/vid.mp4",
+		VideoID:       "vid",
+		HLSBucket:     "my-hls",
+		CEEventID:     "event-42",
+	})
+
+	runReq := doer.calls[1]
+	rawBody, _ := io.ReadAll(runReq.Body)
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(rawBody, &payload); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	container := payload["overrides"].(map[string]interface{})["containerOverrides"].([]interface{})[0].(map[string]interface{})
+	envList := container["env"].([]interface{})
+
+	envMap := make(map[string]string)
+	for _, e := range envList {
+		entry := e.(map[string]interface{})
+		envMap[entry["name"].(string)] = entry["value"].(string)
+	}
+
+	if envMap["CE_EVENT_ID"] != "event-42" {
+		t.Errorf("unexpected CE_EVENT_ID: %q", envMap["CE_EVENT_ID"])
+	}
+}
+
+func TestExecute_RunRequestBodyOmitsCEEventIDWhenUnset(t *testing.T) {
+	doer := &stubDoer{
+		responses: []*http.Response{
+			okMetadataResponse("tok"),
+			okRunResponse(),
+		},
+	}
+	runner := &jobs.CloudRunJobRunner{
+		Project: "proj",
+		Region:  "us-central1",
+		JobName: "mytube-transcoder",
+		Client:  doer,
+	}
+	_, _ = runner.Execute(context.Background(), jobs.ExecuteRequest{
+		RawObjectPath: "raw/vid.mp4",
+		VideoID:       "vid",
+		HLSBucket:     "my-hls",
+	})
+
+	runReq := doer.calls[1]
+	rawBody, _ := io.ReadAll(runReq.Body)
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(rawBody, &payload); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	container := payload["overrides"].(map[string]interface{})["containerOverrides"].([]interface{})[0].(map[string]interface{})
+	envList := container["env"].([]interface{})
+
+	for _, e := range envList {
+		entry := e.(map[string]interface{})
+		if entry["name"].(string) == "CE_EVENT_ID" {
+			t.Errorf("expected no CE_EVENT_ID entry, got %v", entry)
+		}
+	}
+}
+
+func TestExecute_RunRequestBodyContainsRemainingCEMetadataWhenSet(t *testing.T) {
+	doer := &stubDoer{
+		responses: []*http.Response{
+			okMetadataResponse("tok"),
+			okRunResponse(),
+		},
+	}
+	runner := &jobs.CloudRunJobRunner{
+		Project: "proj",
+		Region:  "us-central1",
+		JobName: "mytube-transcoder",
+		Client:  doer,
+	}
+	_, _ = runner.Execute(context.Background(), jobs.ExecuteRequest{
+		RawObjectPath: "raw/vid.mp4",
+		VideoID:       "vid",
+		HLSBucket:     "my-hls",
+		CEEventID:     "event-42",
+		CESource:      "//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads",
+		CEType:        "google.cloud.storage.object.v1.finalized",
+		CESubject:     "objects/raw/vid.mp4",
+		CETime:        "2026-07-29T00:00:00Z",
+	})
+
+	runReq := doer.calls[1]
+	rawBody, _ := io.ReadAll(runReq.Body)
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(rawBody, &payload); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	container := payload["overrides"].(map[string]interface{})["containerOverrides"].([]interface{})[0].(map[string]interface{})
+	envList := container["env"].([]interface{})
+
+	envMap := make(map[string]string)
+	for _, e := range envList {
+		entry := e.(map[string]interface{})
+		envMap[entry["name"].(string)] = entry["value"].(string)
+	}
+
+	if envMap["CE_SOURCE"] != "//storage.googleapis.com/projects/_/buckets/mytube-raw-uploads" {
+		t.Errorf("unexpected CE_SOURCE: %q", envMap["CE_SOURCE"])
+	}
+	if envMap["CE_TYPE"] != "google.cloud.storage.object.v1.finalized" {
+		t.Errorf("unexpected CE_TYPE: %q", envMap["CE_TYPE"])
+	}
+	if envMap["CE_SUBJECT"] != "objects/raw/vid.mp4" {
+		t.Errorf("unexpected CE_SUBJECT: %q", envMap["CE_SUBJECT"])
+	}
+	if envMap["CE_TIME"] != "2026-07-29T00:00:00Z" {
+		t.Errorf("unexpected CE_TIME: %q", envMap["CE_TIME"])
+	}
+}
+
 func TestExecute_RunRequestURLContainsJobName(t *testing.T) {
 	doer := &stubDoer{
 		responses: []*http.Response{
@@ -196,7 +330,7 @@ func TestExecute_RunRequestURLContainsJobName(t *testing.T) {
 		JobName: "special-job",
 		Client:  doer,
 	}
-	_ = runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	_, _ = runner.Execute(context.Background(), jobs.ExecuteRequest{})
 
 	runReq := doer.calls[1]
 	url := runReq.URL.String()
@@ -221,7 +355,7 @@ func TestExecute_MetadataRequestError(t *testing.T) {
 		JobName: "j",
 		Client:  doer,
 	}
-	err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -242,7 +376,7 @@ func TestExecute_MetadataServerNonOK(t *testing.T) {
 		JobName: "j",
 		Client:  doer,
 	}
-	err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
 	if err == nil {
 		t.Fatal("expected error for non-200 metadata response")
 	}
@@ -263,7 +397,7 @@ func TestExecute_MetadataEmptyToken(t *testing.T) {
 		JobName: "j",
 		Client:  doer,
 	}
-	err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
 	if err == nil {
 		t.Fatal("expected error for empty access token")
 	}
@@ -284,7 +418,7 @@ func TestExecute_MetadataInvalidJSON(t *testing.T) {
 		JobName: "j",
 		Client:  doer,
 	}
-	err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
 	if err == nil {
 		t.Fatal("expected error for invalid JSON token response")
 	}
@@ -306,7 +440,7 @@ func TestExecute_RunAPIError(t *testing.T) {
 		JobName: "j",
 		Client:  doer,
 	}
-	err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -328,8 +462,150 @@ func TestExecute_RunAPINonOK(t *testing.T) {
 		JobName: "j",
 		Client:  doer,
 	}
-	err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
 	if err == nil {
 		t.Fatal("expected error for non-2xx run API response")
 	}
+	if doer.index != 2 {
+		t.Errorf("expected no retries for a non-retryable 403, got %d calls", doer.index)
+	}
+	if !errors.Is(err, jobs.ErrPermanent) {
+		t.Errorf("expected a 403 to wrap jobs.ErrPermanent, got %v", err)
+	}
+}
+
+func TestExecute_RunAPI5xx_DoesNotWrapErrPermanent(t *testing.T) {
+	doer := &stubDoer{
+		responses: []*http.Response{
+			okMetadataResponse("tok"),
+			statusResponse(http.StatusInternalServerError, "internal error"),
+			statusResponse(http.StatusInternalServerError, "internal error"),
+		},
+	}
+	runner := &jobs.CloudRunJobRunner{
+		Project:    "p",
+		Region:     "r",
+		JobName:    "j",
+		Client:     doer,
+		MaxRetries: 1,
+	}
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	if err == nil {
+		t.Fatal("expected error after retries are exhausted")
+	}
+	if errors.Is(err, jobs.ErrPermanent) {
+		t.Errorf("expected a 5xx not to wrap jobs.ErrPermanent, got %v", err)
+	}
+}
+
+// ── Execute retry/backoff ─────────────────────────────────────────────────────
+
+func statusResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestExecute_RetriesOn503ThenSucceeds(t *testing.T) {
+	doer := &stubDoer{
+		responses: []*http.Response{
+			okMetadataResponse("tok"),
+			statusResponse(http.StatusServiceUnavailable, "backend unavailable"),
+			okRunResponse(),
+		},
+	}
+	runner := &jobs.CloudRunJobRunner{
+		Project:    "p",
+		Region:     "r",
+		JobName:    "j",
+		Client:     doer,
+		MaxRetries: 1,
+	}
+	if _, err := runner.Execute(context.Background(), jobs.ExecuteRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doer.index != 3 {
+		t.Errorf("expected 3 HTTP calls (token + 2 run attempts), got %d", doer.index)
+	}
+}
+
+func TestExecute_RetriesOn429ExhaustsRetries(t *testing.T) {
+	doer := &stubDoer{
+		responses: []*http.Response{
+			okMetadataResponse("tok"),
+			statusResponse(http.StatusTooManyRequests, "rate limited"),
+			statusResponse(http.StatusTooManyRequests, "rate limited"),
+		},
+	}
+	runner := &jobs.CloudRunJobRunner{
+		Project:    "p",
+		Region:     "r",
+		JobName:    "j",
+		Client:     doer,
+		MaxRetries: 1,
+	}
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	if err == nil {
+		t.Fatal("expected error once retries are exhausted")
+	}
+	if doer.index != 3 {
+		t.Errorf("expected 3 HTTP calls (token + 2 run attempts), got %d", doer.index)
+	}
+}
+
+// ── RunsToTerminal ─────────────────────────────────────────────────────────────
+
+func TestCloudRunJobRunner_RunsToTerminal_FollowsPollUntilTerminal(t *testing.T) {
+	if (&jobs.CloudRunJobRunner{}).RunsToTerminal() {
+		t.Error("expected RunsToTerminal() to be false with PollUntilTerminal unset")
+	}
+	if !(&jobs.CloudRunJobRunner{PollUntilTerminal: true}).RunsToTerminal() {
+		t.Error("expected RunsToTerminal() to be true with PollUntilTerminal set")
+	}
+}
+
+// ── Execute PollUntilTerminal ─────────────────────────────────────────────────
+
+func TestExecute_PollUntilTerminal_WaitsForDone(t *testing.T) {
+	doer := &stubDoer{
+		responses: []*http.Response{
+			okMetadataResponse("tok"),
+			statusResponse(http.StatusOK, `{"name":"projects/p/locations/r/operations/op1","done":false}`),
+			statusResponse(http.StatusOK, `{"name":"projects/p/locations/r/operations/op1","done":false}`),
+			statusResponse(http.StatusOK, `{"name":"projects/p/locations/r/operations/op1","done":true}`),
+		},
+	}
+	runner := &jobs.CloudRunJobRunner{
+		Project:           "p",
+		Region:            "r",
+		JobName:           "j",
+		Client:            doer,
+		PollUntilTerminal: true,
+		PollInterval:      time.Millisecond,
+	}
+	if _, err := runner.Execute(context.Background(), jobs.ExecuteRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doer.index != 4 {
+		t.Errorf("expected 4 HTTP calls (token + run + 2 polls), got %d", doer.index)
+	}
+}
+
+func TestExecute_PollUntilTerminal_PropagatesOperationError(t *testing.T) {
+	doer := &stubDoer{
+		responses: []*http.Response{
+			okMetadataResponse("tok"),
+			statusResponse(http.StatusOK, `{"name":"projects/p/locations/r/operations/op1","done":true,"error":{"code":13,"message":"internal"}}`),
+		},
+	}
+	runner := &jobs.CloudRunJobRunner{
+		Project:           "p",
+		Region:            "r",
+		JobName:           "j",
+		Client:            doer,
+		PollUntilTerminal: true,
+		PollInterval:      time.Millisecond,
+	}
+	_, err := runner.Execute(context.Background(), jobs.ExecuteRequest{})
+	if err == nil {
+		t.Fatal("expected error when the execution's LRO reports an error")
+	}
 }
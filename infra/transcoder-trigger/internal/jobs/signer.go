@@ -0,0 +1,206 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequestSigner attaches authentication to an outgoing HTTP request in place.
+// It lets the trigger dispatch work to providers other than Cloud Run Jobs
+// (AWS Batch, Step Functions, EventBridge) without CloudRunJobRunner itself
+// knowing the auth scheme.
+type RequestSigner interface {
+	Sign(ctx context.Context, req *http.Request) error
+}
+
+// ── OAuth2 bearer (current Cloud Run Jobs behaviour) ───────────────────────
+
+// BearerSigner attaches "Authorization: Bearer <token>" using a TokenSource.
+type BearerSigner struct {
+	Tokens TokenSource
+}
+
+// Sign sets the Authorization header from a freshly fetched (or cached) token.
+func (s *BearerSigner) Sign(ctx context.Context, req *http.Request) error {
+	token, _, err := s.Tokens.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("bearer signer: get token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// ── AWS SigV4 ───────────────────────────────────────────────────────────────
+
+// SigV4Signer signs requests per the AWS Signature Version 4 process, for
+// dispatching to AWS Batch / Step Functions / EventBridge endpoints.
+type SigV4Signer struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+	Service         string
+	// Now defaults to time.Now; overridable in tests for deterministic signatures.
+	Now func() time.Time
+}
+
+// Sign computes the canonical request, string-to-sign, and signing key, then
+// sets the Authorization, X-Amz-Date, and X-Amz-Content-Sha256 headers.
+func (s *SigV4Signer) Sign(_ context.Context, req *http.Request) error {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	dateStamp := t.Format("20060102")
+
+	payloadHash, err := hashRequestBody(req)
+	if err != nil {
+		return fmt.Errorf("sigv4: hash payload: %w", err)
+	}
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", dateStamp, s.Region, s.Service)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := sigV4SigningKey(s.SecretAccessKey, dateStamp, s.Region, s.Service)
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	auth := fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature,
+	)
+	req.Header.Set("Authorization", auth)
+	return nil
+}
+
+// sigV4SigningKey derives the request-specific signing key:
+// HMAC(HMAC(HMAC(HMAC("AWS4"+secret, date), region), service), "aws4_request").
+func sigV4SigningKey(secret, dateStamp, region, service string) []byte {
+	kDate := hmacSHA256([]byte("AWS4"+secret), dateStamp)
+	kRegion := hmacSHA256(kDate, region)
+	kService := hmacSHA256(kRegion, service)
+	return hmacSHA256(kService, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashRequestBody reads and restores req.Body so it can still be sent after
+// hashing, and returns the empty-body hash when there is no body.
+func hashRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return hashHex(nil), nil
+	}
+	body, err := readAndRestoreBody(req)
+	if err != nil {
+		return "", err
+	}
+	return hashHex(body), nil
+}
+
+// readAndRestoreBody drains req.Body and replaces it with a fresh reader over
+// the same bytes so the request can still be sent after hashing.
+func readAndRestoreBody(req *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// canonicalURI returns the URL path, defaulting to "/" per the SigV4 spec.
+func canonicalURI(req *http.Request) string {
+	if req.URL.Path == "" {
+		return "/"
+	}
+	return req.URL.Path
+}
+
+// canonicalizeHeaders returns the SigV4 SignedHeaders and CanonicalHeaders
+// strings: header names lower-cased and sorted lexicographically, including
+// a host header synthesised from req.Host (sorted in with the rest, and
+// replaced by an explicit Host entry in req.Header if there is one).
+func canonicalizeHeaders(req *http.Request) (signedHeaders, canonicalHeaders string) {
+	headers := map[string]string{"host": req.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canon strings.Builder
+	for _, name := range names {
+		canon.WriteString(name)
+		canon.WriteByte(':')
+		canon.WriteString(strings.TrimSpace(headers[name]))
+		canon.WriteByte('\n')
+	}
+	return strings.Join(names, ";"), canon.String()
+}
+
+// ── GCS HMAC (V4 signing with a GCS HMAC key pair) ─────────────────────────
+
+// GCSHMACSigner signs requests using a GCS HMAC access key, which uses the
+// same SigV4-style canonical request process as AWS but against
+// storage.googleapis.com.
+type GCSHMACSigner struct {
+	inner *SigV4Signer
+}
+
+// NewGCSHMACSigner constructs a signer for a GCS HMAC access key/secret pair.
+func NewGCSHMACSigner(accessID, secret string) *GCSHMACSigner {
+	return &GCSHMACSigner{inner: &SigV4Signer{
+		AccessKeyID:     accessID,
+		SecretAccessKey: secret,
+		Region:          "auto",
+		Service:         "storage",
+	}}
+}
+
+// Sign delegates to the shared SigV4 implementation.
+func (s *GCSHMACSigner) Sign(ctx context.Context, req *http.Request) error {
+	return s.inner.Sign(ctx, req)
+}
@@ -0,0 +1,95 @@
+package jobs_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+func TestBearerSigner_SetsAuthorizationHeader(t *testing.T) {
+	signer := &jobs.BearerSigner{Tokens: &recordingTokenSource{token: "abc123"}}
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/run", nil)
+
+	if err := signer.Sign(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer abc123" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer abc123")
+	}
+}
+
+func TestBearerSigner_PropagatesTokenError(t *testing.T) {
+	signer := &jobs.BearerSigner{Tokens: &recordingTokenSource{err: errTokenUnavailable}}
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/run", nil)
+
+	if err := signer.Sign(context.Background(), req); err == nil {
+		t.Fatal("expected error from failing token source")
+	}
+}
+
+var errTokenUnavailable = &tokenErr{"token unavailable"}
+
+type tokenErr struct{ msg string }
+
+func (e *tokenErr) Error() string { return e.msg }
+
+func TestSigV4Signer_SetsExpectedHeaders(t *testing.T) {
+	fixedTime := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	signer := &jobs.SigV4Signer{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+		Service:         "batch",
+		Now:             func() time.Time { return fixedTime },
+	}
+	req, _ := http.NewRequest(http.MethodPost, "https://batch.us-east-1.amazonaws.com/v1/submitjob", strings.NewReader(`{"a":1}`))
+
+	if err := signer.Sign(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240115/us-east-1/batch/aws4_request") {
+		t.Errorf("unexpected Authorization header: %q", auth)
+	}
+	if !strings.Contains(auth, "SignedHeaders=") || !strings.Contains(auth, "Signature=") {
+		t.Errorf("Authorization header missing expected components: %q", auth)
+	}
+	if req.Header.Get("X-Amz-Date") != "20240115T120000Z" {
+		t.Errorf("X-Amz-Date = %q, want %q", req.Header.Get("X-Amz-Date"), "20240115T120000Z")
+	}
+	if req.Header.Get("X-Amz-Content-Sha256") == "" {
+		t.Error("expected X-Amz-Content-Sha256 to be set")
+	}
+}
+
+func TestSigV4Signer_BodyIsReadableAfterSigning(t *testing.T) {
+	signer := &jobs.SigV4Signer{AccessKeyID: "id", SecretAccessKey: "secret", Region: "r", Service: "s"}
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/x", strings.NewReader("payload"))
+
+	if err := signer.Sign(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := make([]byte, 7)
+	n, _ := req.Body.Read(body)
+	if string(body[:n]) != "payload" {
+		t.Errorf("body after signing = %q, want %q", string(body[:n]), "payload")
+	}
+}
+
+func TestGCSHMACSigner_SetsAuthorizationHeader(t *testing.T) {
+	signer := jobs.NewGCSHMACSigner("GOOG1EXAMPLE", "examplesecret")
+	req, _ := http.NewRequest(http.MethodGet, "https://storage.googleapis.com/bucket/object", nil)
+
+	if err := signer.Sign(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=GOOG1EXAMPLE") {
+		t.Errorf("unexpected Authorization header: %q", req.Header.Get("Authorization"))
+	}
+}
@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrAlreadyExists is returned by Store.Create when key already has a
+// recorded execution, regardless of that execution's status.
+var ErrAlreadyExists = errors.New("jobs: idempotency key already recorded")
+
+// ExecutionStatus is the lifecycle state of a recorded job execution.
+type ExecutionStatus string
+
+const (
+	StatusRunning   ExecutionStatus = "running"
+	StatusSucceeded ExecutionStatus = "succeeded"
+	StatusFailed    ExecutionStatus = "failed"
+)
+
+// Store records job executions keyed by an ExecuteRequest.IdempotencyKey,
+// so NewTriggerHandler can short-circuit a duplicate at-least-once GCS
+// delivery instead of launching a second Cloud Run execution for the same
+// (bucket, object, generation). Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Create atomically records key -> executionName in StatusRunning. It
+	// returns ErrAlreadyExists if a row for key is already recorded,
+	// whatever that row's status — a terminal (succeeded/failed) row is
+	// still a duplicate until Sweep removes it.
+	Create(ctx context.Context, key, executionName string) error
+	// UpdateStatus transitions the row for key to status. Called once the
+	// executor's Execute call returns, so a failed row becomes eligible
+	// for Sweep and, eventually, retry.
+	UpdateStatus(ctx context.Context, key string, status ExecutionStatus) error
+	// Sweep deletes StatusFailed rows created before olderThan, returning
+	// how many were removed. See Sweeper, which calls this on a TTL.
+	Sweep(ctx context.Context, olderThan time.Time) (int, error)
+}
+
+// executionRecord is one row tracked by MemoryStore.
+type executionRecord struct {
+	executionName string
+	status        ExecutionStatus
+	createdAt     time.Time
+}
+
+// MemoryStore is an in-memory Store, for local dev (see LocalExecutor) and
+// tests that don't need a real Firestore connection.
+type MemoryStore struct {
+	mu   sync.Mutex
+	rows map[string]executionRecord
+}
+
+// NewMemoryStore constructs an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{rows: make(map[string]executionRecord)}
+}
+
+func (m *MemoryStore) Create(_ context.Context, key, executionName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rows[key]; ok {
+		return ErrAlreadyExists
+	}
+	m.rows[key] = executionRecord{
+		executionName: executionName,
+		status:        StatusRunning,
+		createdAt:     time.Now(),
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateStatus(_ context.Context, key string, status ExecutionStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	row, ok := m.rows[key]
+	if !ok {
+		return fmt.Errorf("jobs: update status: key %q not recorded", key)
+	}
+	row.status = status
+	m.rows[key] = row
+	return nil
+}
+
+func (m *MemoryStore) Sweep(_ context.Context, olderThan time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int
+	for key, row := range m.rows {
+		if row.status == StatusFailed && row.createdAt.Before(olderThan) {
+			delete(m.rows, key)
+			n++
+		}
+	}
+	return n, nil
+}
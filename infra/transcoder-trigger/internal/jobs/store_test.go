@@ -0,0 +1,109 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+func TestMemoryStore_Create_RecordsNewKey(t *testing.T) {
+	s := jobs.NewMemoryStore()
+
+	if err := s.Create(context.Background(), "key-1", "exec-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMemoryStore_Create_DuplicateKeyReturnsErrAlreadyExists(t *testing.T) {
+	s := jobs.NewMemoryStore()
+
+	if err := s.Create(context.Background(), "key-1", "exec-1"); err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	err := s.Create(context.Background(), "key-1", "exec-2")
+	if !errors.Is(err, jobs.ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists, got: %v", err)
+	}
+}
+
+func TestMemoryStore_Create_DuplicateAfterSucceededStillBlocks(t *testing.T) {
+	s := jobs.NewMemoryStore()
+
+	if err := s.Create(context.Background(), "key-1", "exec-1"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := s.UpdateStatus(context.Background(), "key-1", jobs.StatusSucceeded); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+
+	err := s.Create(context.Background(), "key-1", "exec-2")
+	if !errors.Is(err, jobs.ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists for a terminal row, got: %v", err)
+	}
+}
+
+func TestMemoryStore_UpdateStatus_UnknownKeyErrors(t *testing.T) {
+	s := jobs.NewMemoryStore()
+
+	if err := s.UpdateStatus(context.Background(), "missing", jobs.StatusFailed); err == nil {
+		t.Error("expected an error updating an unrecorded key")
+	}
+}
+
+func TestMemoryStore_Sweep_RemovesOnlyExpiredFailedRows(t *testing.T) {
+	s := jobs.NewMemoryStore()
+	ctx := context.Background()
+
+	// key-failed: failed and old enough to sweep.
+	if err := s.Create(ctx, "key-failed", "exec-failed"); err != nil {
+		t.Fatalf("create key-failed: %v", err)
+	}
+	if err := s.UpdateStatus(ctx, "key-failed", jobs.StatusFailed); err != nil {
+		t.Fatalf("update key-failed: %v", err)
+	}
+
+	// key-running: never fails, must survive the sweep regardless of age.
+	if err := s.Create(ctx, "key-running", "exec-running"); err != nil {
+		t.Fatalf("create key-running: %v", err)
+	}
+
+	n, err := s.Sweep(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 swept row, got %d", n)
+	}
+
+	// The swept key is retryable again.
+	if err := s.Create(ctx, "key-failed", "exec-failed-retry"); err != nil {
+		t.Errorf("expected key-failed to be retryable after sweep, got: %v", err)
+	}
+	// The running key was untouched.
+	if err := s.Create(ctx, "key-running", "exec-running-2"); !errors.Is(err, jobs.ErrAlreadyExists) {
+		t.Errorf("expected key-running to still be blocked, got: %v", err)
+	}
+}
+
+func TestMemoryStore_Sweep_LeavesRecentFailuresAlone(t *testing.T) {
+	s := jobs.NewMemoryStore()
+	ctx := context.Background()
+
+	if err := s.Create(ctx, "key-1", "exec-1"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := s.UpdateStatus(ctx, "key-1", jobs.StatusFailed); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+
+	n, err := s.Sweep(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected 0 swept rows for a recent failure, got %d", n)
+	}
+}
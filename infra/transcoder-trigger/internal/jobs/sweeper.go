@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// defaultSweepInterval is how often Sweeper.Run checks for expired failed
+// executions when Interval is unset.
+const defaultSweepInterval = 5 * time.Minute
+
+// Sweeper periodically calls Store.Sweep so a failed execution's
+// idempotency key eventually expires and becomes retryable, rather than
+// permanently blocking every future delivery for that object generation.
+type Sweeper struct {
+	Store Store
+	// TTL is how long a StatusFailed row is kept before Sweep removes it.
+	TTL time.Duration
+	// Interval is how often Run checks for expired rows. Defaults to
+	// defaultSweepInterval when zero.
+	Interval time.Duration
+}
+
+// NewSweeper constructs a Sweeper over store with the given ttl and sweep
+// interval.
+func NewSweeper(store Store, ttl, interval time.Duration) *Sweeper {
+	return &Sweeper{Store: store, TTL: ttl, Interval: interval}
+}
+
+func (s *Sweeper) interval() time.Duration {
+	if s.Interval <= 0 {
+		return defaultSweepInterval
+	}
+	return s.Interval
+}
+
+// Run blocks, calling Store.Sweep every Interval until ctx is canceled.
+// Intended to be started in its own goroutine at process startup.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.Store.Sweep(ctx, time.Now().Add(-s.TTL))
+			if err != nil {
+				log.Printf("jobs: sweep failed executions: %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("jobs: swept %d expired failed executions", n)
+			}
+		}
+	}
+}
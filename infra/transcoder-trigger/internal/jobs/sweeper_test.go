@@ -0,0 +1,57 @@
+package jobs_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+// sweepStubStore records Sweep calls; only Sweep is exercised by Sweeper.
+type sweepStubStore struct {
+	sweeps int32
+}
+
+func (s *sweepStubStore) Create(context.Context, string, string) error { return nil }
+func (s *sweepStubStore) UpdateStatus(context.Context, string, jobs.ExecutionStatus) error {
+	return nil
+}
+func (s *sweepStubStore) Sweep(context.Context, time.Time) (int, error) {
+	atomic.AddInt32(&s.sweeps, 1)
+	return 0, nil
+}
+
+func TestSweeper_Run_SweepsOnEveryInterval(t *testing.T) {
+	store := &sweepStubStore{}
+	sweeper := jobs.NewSweeper(store, time.Hour, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	sweeper.Run(ctx)
+
+	if atomic.LoadInt32(&store.sweeps) < 2 {
+		t.Errorf("expected Sweep to be called at least twice in 30ms at a 5ms interval, got %d", store.sweeps)
+	}
+}
+
+func TestSweeper_Run_ReturnsWhenContextCanceled(t *testing.T) {
+	store := &sweepStubStore{}
+	sweeper := jobs.NewSweeper(store, time.Hour, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sweeper.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return promptly after context cancellation")
+	}
+}
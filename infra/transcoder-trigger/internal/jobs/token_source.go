@@ -0,0 +1,304 @@
+package jobs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// signJWTAssertion builds and signs a JWT bearer assertion per RFC 7523,
+// requesting scope on behalf of issuer, to be exchanged for an access token
+// at audience (the service account's token_uri).
+func signJWTAssertion(issuer, audience, scope string, now time.Time, key *rsa.PrivateKey) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	claims := map[string]any{
+		"iss":   issuer,
+		"scope": scope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("marshal jwt header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("marshal jwt claims: %w", err)
+	}
+
+	signingInput := base64URLEncode(headerJSON) + "." + base64URLEncode(claimsJSON)
+
+	hashed := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", fmt.Errorf("sign jwt: %w", err)
+	}
+
+	return signingInput + "." + base64URLEncode(sig), nil
+}
+
+// base64URLEncode returns the unpadded base64url encoding used by JWT segments.
+func base64URLEncode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// TokenSource returns a bearer token to authenticate Cloud Run Jobs API calls,
+// along with its expiry so callers can decide when to refresh.
+type TokenSource interface {
+	Token(ctx context.Context) (token string, expiry time.Time, err error)
+}
+
+// ── GCE metadata server ────────────────────────────────────────────────────
+
+// GCEMetadataTokenSource fetches a short-lived access token from the GCE
+// metadata server using the instance's attached service account. This is the
+// token source used automatically inside Cloud Run / GCE / GKE.
+type GCEMetadataTokenSource struct {
+	Client HTTPDoer
+}
+
+// NewGCEMetadataTokenSource constructs a GCEMetadataTokenSource using the
+// default http.Client.
+func NewGCEMetadataTokenSource() *GCEMetadataTokenSource {
+	return &GCEMetadataTokenSource{Client: &http.Client{}}
+}
+
+// Token fetches a fresh access token from the metadata server.
+func (s *GCEMetadataTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		"http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token",
+		nil,
+	)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("build metadata request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("metadata request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("metadata server returned %d", resp.StatusCode)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", time.Time{}, fmt.Errorf("decode token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("empty access token from metadata server")
+	}
+	return tok.AccessToken, time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second), nil
+}
+
+// ── service-account JSON key (JWT-assertion → OAuth2 exchange) ────────────
+
+// serviceAccountKey is the subset of a GCP service-account JSON key file
+// needed to mint a signed JWT assertion.
+type serviceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// ServiceAccountKeyTokenSource authenticates using a downloaded service-account
+// JSON key: it signs a JWT assertion with the private key and exchanges it
+// for an OAuth2 access token at TokenURI, per RFC 7523.
+type ServiceAccountKeyTokenSource struct {
+	Client HTTPDoer
+	Key    []byte // raw contents of the service-account JSON key file
+	Scope  string // OAuth2 scope requested, e.g. "https://www.googleapis.com/auth/cloud-platform"
+}
+
+// NewServiceAccountKeyTokenSource constructs a token source from the raw bytes
+// of a service-account JSON key file.
+func NewServiceAccountKeyTokenSource(keyJSON []byte, scope string) *ServiceAccountKeyTokenSource {
+	return &ServiceAccountKeyTokenSource{Client: &http.Client{}, Key: keyJSON, Scope: scope}
+}
+
+// Token mints a signed JWT assertion and exchanges it for an access token.
+func (s *ServiceAccountKeyTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	var key serviceAccountKey
+	if err := json.Unmarshal(s.Key, &key); err != nil {
+		return "", time.Time{}, fmt.Errorf("parse service account key: %w", err)
+	}
+
+	privateKey, err := parsePEMPrivateKey(key.PrivateKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("parse service account private key: %w", err)
+	}
+
+	now := time.Now()
+	assertion, err := signJWTAssertion(key.ClientEmail, key.TokenURI, s.Scope, now, privateKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("sign jwt assertion: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, key.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("build token exchange request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token exchange request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("token exchange returned %d", resp.StatusCode)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", time.Time{}, fmt.Errorf("decode token exchange response: %w", err)
+	}
+	return tok.AccessToken, now.Add(time.Duration(tok.ExpiresIn) * time.Second), nil
+}
+
+// parsePEMPrivateKey decodes a PKCS#8 PEM-encoded RSA private key as found in
+// a service-account JSON key file.
+func parsePEMPrivateKey(pemStr string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse pkcs8 private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+	return rsaKey, nil
+}
+
+// ── Workload Identity Federation (AWS / generic OIDC) ──────────────────────
+
+// WorkloadIdentityFederationTokenSource exchanges an external credential (an
+// AWS signed request or a third-party OIDC ID token) for a short-lived GCP
+// access token via the STS token exchange endpoint, as described by
+// https://cloud.google.com/iam/docs/workload-identity-federation. This lets
+// the trigger authenticate from outside GCP (e.g. GitHub Actions, AWS Lambda)
+// without a downloaded service-account key.
+type WorkloadIdentityFederationTokenSource struct {
+	Client HTTPDoer
+	// STSEndpoint is the Security Token Service token exchange URL, e.g.
+	// "https://sts.googleapis.com/v1/token".
+	STSEndpoint string
+	// Audience identifies the configured workload identity pool provider.
+	Audience string
+	// SubjectTokenFunc produces the external credential (AWS signed GetCallerIdentity
+	// request or raw OIDC ID token) presented as the subject_token.
+	SubjectTokenFunc func(ctx context.Context) (subjectToken, subjectTokenType string, err error)
+}
+
+// Token exchanges the external credential for a GCP access token.
+func (s *WorkloadIdentityFederationTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	subjectToken, subjectTokenType, err := s.SubjectTokenFunc(ctx)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("produce subject token: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:token-exchange")
+	form.Set("audience", s.Audience)
+	form.Set("scope", "https://www.googleapis.com/auth/cloud-platform")
+	form.Set("requested_token_type", "urn:ietf:params:oauth:token-type:access_token")
+	form.Set("subject_token", subjectToken)
+	form.Set("subject_token_type", subjectTokenType)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.STSEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("build sts exchange request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("sts exchange request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("sts exchange returned %d", resp.StatusCode)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", time.Time{}, fmt.Errorf("decode sts exchange response: %w", err)
+	}
+	return tok.AccessToken, time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second), nil
+}
+
+// ── in-memory caching wrapper ───────────────────────────────────────────────
+
+// refreshSkew is how long before expiry a cached token is proactively refreshed.
+const refreshSkew = 60 * time.Second
+
+// CachingTokenSource wraps a TokenSource and reuses the last token until
+// refreshSkew before it expires, avoiding a round-trip on every call.
+type CachingTokenSource struct {
+	mu     sync.Mutex
+	source TokenSource
+	token  string
+	expiry time.Time
+}
+
+// NewCachingTokenSource wraps source with an in-memory cache.
+func NewCachingTokenSource(source TokenSource) *CachingTokenSource {
+	return &CachingTokenSource{source: source}
+}
+
+// Token returns the cached token if it is still valid, otherwise fetches a
+// fresh one from the wrapped source and caches it.
+func (c *CachingTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.expiry.Add(-refreshSkew)) {
+		return c.token, c.expiry, nil
+	}
+
+	token, expiry, err := c.source.Token(ctx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	c.token = token
+	c.expiry = expiry
+	return token, expiry, nil
+}
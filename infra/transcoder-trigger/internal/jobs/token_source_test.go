@@ -0,0 +1,72 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+)
+
+// recordingTokenSource counts how many times Token is called.
+type recordingTokenSource struct {
+	calls  int
+	token  string
+	expiry time.Time
+	err    error
+}
+
+func (s *recordingTokenSource) Token(_ context.Context) (string, time.Time, error) {
+	s.calls++
+	return s.token, s.expiry, s.err
+}
+
+func TestCachingTokenSource_ReusesValidToken(t *testing.T) {
+	inner := &recordingTokenSource{token: "tok1", expiry: time.Now().Add(time.Hour)}
+	cached := jobs.NewCachingTokenSource(inner)
+
+	for i := 0; i < 3; i++ {
+		tok, _, err := cached.Token(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tok != "tok1" {
+			t.Errorf("token = %q, want %q", tok, "tok1")
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("inner source called %d times, want 1", inner.calls)
+	}
+}
+
+func TestCachingTokenSource_RefreshesNearExpiry(t *testing.T) {
+	inner := &recordingTokenSource{token: "tok1", expiry: time.Now().Add(30 * time.Second)}
+	cached := jobs.NewCachingTokenSource(inner)
+
+	if _, _, err := cached.Token(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inner.token = "tok2"
+	inner.expiry = time.Now().Add(time.Hour)
+
+	tok, _, err := cached.Token(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tok != "tok2" {
+		t.Errorf("expected refreshed token tok2 within refresh skew, got %q", tok)
+	}
+	if inner.calls != 2 {
+		t.Errorf("inner source called %d times, want 2", inner.calls)
+	}
+}
+
+func TestCachingTokenSource_PropagatesError(t *testing.T) {
+	inner := &recordingTokenSource{err: errors.New("metadata unavailable")}
+	cached := jobs.NewCachingTokenSource(inner)
+
+	if _, _, err := cached.Token(context.Background()); err == nil {
+		t.Fatal("expected error from wrapped source")
+	}
+}
@@ -0,0 +1,98 @@
+// Package notify publishes transcode lifecycle events so downstream systems
+// (dashboards, alerting, a UI progress indicator) can observe a video's
+// pipeline without polling the job-management HTTP surface (see
+// handler.NewRouter).
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// EventType names a point in a transcode's lifecycle.
+type EventType string
+
+const (
+	// EventStarted is published by TriggerHandler just before its retry
+	// loop first calls Executor.Execute for a video, so it carries no
+	// execution name.
+	EventStarted EventType = "transcode.started"
+	// EventSucceeded is published once the execution has completed
+	// successfully.
+	EventSucceeded EventType = "transcode.succeeded"
+	// EventFailed is published once TriggerHandler's retry loop ends without
+	// a successful execution: its RetryPolicy's attempts are exhausted, an
+	// error is classified permanent, or the request context is done.
+	EventFailed EventType = "transcode.failed"
+)
+
+// Event is the payload Publisher.Publish sends for one lifecycle transition.
+type Event struct {
+	Type          EventType `json:"type"`
+	VideoID       string    `json:"video_id"`
+	RawObject     string    `json:"raw_object"`
+	HLSBucket     string    `json:"hls_bucket"`
+	ExecutionName string    `json:"execution_name"`
+	EventTime     time.Time `json:"event_time"`
+	Attempt       int       `json:"attempt"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Publisher publishes lifecycle Events. Implementations must be safe for
+// concurrent use.
+type Publisher interface {
+	Publish(ctx context.Context, evt Event) error
+}
+
+// NoopPublisher discards every Event. It is the default Publisher so that
+// callers which don't configure one (e.g. existing handler tests) keep
+// working unchanged.
+type NoopPublisher struct{}
+
+// Publish discards evt and always returns nil.
+func (NoopPublisher) Publish(context.Context, Event) error { return nil }
+
+// PubSubPublisher publishes Events to a Pub/Sub topic as JSON, ordered per
+// video via the topic's message ordering (OrderingKey = Event.VideoID) so a
+// transcode.failed event can never be observed ahead of the
+// transcode.started event for the same video.
+type PubSubPublisher struct {
+	topic *pubsub.Topic
+}
+
+// NewPubSubPublisher constructs a PubSubPublisher for the given project and
+// topic ID, enabling message ordering on the topic. The topic must already
+// exist.
+func NewPubSubPublisher(ctx context.Context, projectID, topicID string) (*PubSubPublisher, error) {
+	client, err := pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("create pubsub client: %w", err)
+	}
+	topic := client.Topic(topicID)
+	topic.EnableMessageOrdering = true
+	return &PubSubPublisher{topic: topic}, nil
+}
+
+// Publish JSON-encodes evt and publishes it to the topic with OrderingKey
+// set to evt.VideoID, blocking until the publish completes or ctx is done.
+// On failure it calls ResumePublish for evt.VideoID so a single transient
+// error doesn't permanently wedge publishing for that ordering key.
+func (p *PubSubPublisher) Publish(ctx context.Context, evt Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("marshal lifecycle event: %w", err)
+	}
+	result := p.topic.Publish(ctx, &pubsub.Message{
+		Data:        data,
+		OrderingKey: evt.VideoID,
+	})
+	if _, err := result.Get(ctx); err != nil {
+		p.topic.ResumePublish(evt.VideoID)
+		return fmt.Errorf("publish lifecycle event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package notify_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/notify"
+)
+
+func TestNoopPublisher_Publish_ReturnsNil(t *testing.T) {
+	var p notify.NoopPublisher
+	if err := p.Publish(context.Background(), notify.Event{Type: notify.EventStarted, VideoID: "vid-1"}); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
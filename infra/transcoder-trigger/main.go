@@ -1,35 +1,191 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/redis/go-redis/v9"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 
 	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/handler"
 	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/jobs"
+	"github.com/ai-teammate/mytube/infra/transcoder-trigger/internal/notify"
 )
 
+// defaultSweepTTL is how long a failed execution's idempotency key is kept
+// before it becomes retryable again, when SWEEP_TTL is unset.
+const defaultSweepTTL = 24 * time.Hour
+
 func main() {
-	project := mustEnv("GCP_PROJECT")
-	region := mustEnv("GCP_REGION")
-	jobName := mustEnv("JOB_NAME")
 	hlsBucket := mustEnv("HLS_BUCKET")
 
-	runner := jobs.NewCloudRunJobRunner(project, region, jobName)
+	executor, err := executorFromEnv()
+	if err != nil {
+		log.Fatalf("configure executor: %v", err)
+	}
+
+	store, err := storeFromEnv()
+	if err != nil {
+		log.Fatalf("configure store: %v", err)
+	}
+
+	dedup, err := dedupFromEnv()
+	if err != nil {
+		log.Fatalf("configure dedup: %v", err)
+	}
+
+	publisher, err := publisherFromEnv()
+	if err != nil {
+		log.Fatalf("configure publisher: %v", err)
+	}
+
+	sweeper := jobs.NewSweeper(store, sweepTTLFromEnv(), 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sweeper.Run(ctx)
+
+	jobStore := jobs.NewMemoryJobStore()
+	triggerHandler := handler.NewTriggerHandler(executor, store, hlsBucket, handler.WithDeduper(dedup), handler.WithJobStore(jobStore), handler.WithPublisher(publisher))
+	if audience := os.Getenv("OIDC_AUDIENCE"); audience != "" {
+		opts := []handler.OIDCOption{}
+		if emails := os.Getenv("OIDC_ALLOWED_EMAILS"); emails != "" {
+			opts = append(opts, handler.WithAllowedEmails(strings.Split(emails, ",")...))
+		}
+		triggerHandler = handler.RequireOIDC(handler.NewOIDCVerifier(audience, opts...), triggerHandler)
+	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", handler.NewTriggerHandler(runner, hlsBucket))
+	mux := handler.NewRouter(triggerHandler, executor, jobStore)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	log.Printf("transcoder-trigger listening on :%s (job=%s project=%s region=%s)",
-		port, jobName, project, region)
+	log.Printf("transcoder-trigger listening on :%s", port)
 	log.Fatal(http.ListenAndServe(":"+port, mux))
 }
 
+// storeFromEnv selects a jobs.Store implementation by the STORE environment
+// variable:
+//
+//	STORE — "firestore" (default) or "memory"
+//
+// "firestore" records executions in the project's Firestore database
+// (GCP_PROJECT, optionally FIRESTORE_COLLECTION); "memory" keeps them
+// in-process, for docker-compose and local e2e runs without GCP.
+func storeFromEnv() (jobs.Store, error) {
+	switch mode := os.Getenv("STORE"); mode {
+	case "", "firestore":
+		client, err := firestore.NewClient(context.Background(), mustEnv("GCP_PROJECT"))
+		if err != nil {
+			return nil, fmt.Errorf("create firestore client: %w", err)
+		}
+		return jobs.NewFirestoreStore(client, os.Getenv("FIRESTORE_COLLECTION")), nil
+	case "memory":
+		return jobs.NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown STORE %q (want one of firestore, memory)", mode)
+	}
+}
+
+// dedupFromEnv selects a handler.Deduper implementation by the DEDUP
+// environment variable:
+//
+//	DEDUP — "firestore" (default), "redis", or "memory"
+//
+// "firestore" and "redis" back the fast dedup pre-check with the project's
+// Firestore database (GCP_PROJECT, optionally DEDUP_COLLECTION) or a Redis
+// instance (REDIS_ADDR); "memory" keeps it in-process, for docker-compose
+// and local e2e runs without either.
+func dedupFromEnv() (handler.Deduper, error) {
+	switch mode := os.Getenv("DEDUP"); mode {
+	case "", "firestore":
+		client, err := firestore.NewClient(context.Background(), mustEnv("GCP_PROJECT"))
+		if err != nil {
+			return nil, fmt.Errorf("create firestore client: %w", err)
+		}
+		return handler.NewFirestoreDeduper(client, os.Getenv("DEDUP_COLLECTION")), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{Addr: mustEnv("REDIS_ADDR")})
+		return handler.NewRedisDeduper(client), nil
+	case "memory":
+		return handler.NewMemoryDeduper(0), nil
+	default:
+		return nil, fmt.Errorf("unknown DEDUP %q (want one of firestore, redis, memory)", mode)
+	}
+}
+
+// publisherFromEnv selects a notify.Publisher implementation by the
+// NOTIFY_TOPIC environment variable: when set, lifecycle events are
+// published to that Pub/Sub topic (GCP_PROJECT); when unset, events are
+// discarded by a notify.NoopPublisher so notification stays opt-in.
+func publisherFromEnv() (notify.Publisher, error) {
+	topic := os.Getenv("NOTIFY_TOPIC")
+	if topic == "" {
+		return notify.NoopPublisher{}, nil
+	}
+	return notify.NewPubSubPublisher(context.Background(), mustEnv("GCP_PROJECT"), topic)
+}
+
+// sweepTTLFromEnv parses SWEEP_TTL as a Go duration (e.g. "24h"), falling
+// back to defaultSweepTTL when unset.
+func sweepTTLFromEnv() time.Duration {
+	raw := os.Getenv("SWEEP_TTL")
+	if raw == "" {
+		return defaultSweepTTL
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Fatalf("invalid SWEEP_TTL %q: %v", raw, err)
+	}
+	return ttl
+}
+
+// executorFromEnv selects a jobs.Executor implementation by the EXECUTOR
+// environment variable:
+//
+//	EXECUTOR — "cloudrun" (default), "local", or "kubernetes"
+//
+// "cloudrun" dispatches to the Cloud Run Jobs API (GCP_PROJECT, GCP_REGION,
+// JOB_NAME); "local" runs the transcoder binary as a subprocess
+// (TRANSCODER_BINARY, defaults to "transcoder" on PATH), for docker-compose
+// and local e2e runs without GCP; "kubernetes" creates a batch/v1 Job in the
+// in-cluster API server (KUBE_NAMESPACE, TRANSCODER_IMAGE).
+func executorFromEnv() (handler.JobExecutor, error) {
+	switch mode := os.Getenv("EXECUTOR"); mode {
+	case "", "cloudrun":
+		return jobs.NewCloudRunJobRunner(mustEnv("GCP_PROJECT"), mustEnv("GCP_REGION"), mustEnv("JOB_NAME")), nil
+	case "local":
+		return jobs.NewLocalExecutor(os.Getenv("TRANSCODER_BINARY")), nil
+	case "kubernetes":
+		return newKubernetesExecutor()
+	default:
+		return nil, fmt.Errorf("unknown EXECUTOR %q (want one of cloudrun, local, kubernetes)", mode)
+	}
+}
+
+// newKubernetesExecutor builds a KubernetesExecutor from the in-cluster
+// Kubernetes API config, the only config source relevant to a trigger
+// service running as a Pod in the target cluster.
+func newKubernetesExecutor() (*jobs.KubernetesExecutor, error) {
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return jobs.NewKubernetesExecutor(client, os.Getenv("KUBE_NAMESPACE"), mustEnv("TRANSCODER_IMAGE")), nil
+}
+
 // mustEnv returns the value of the environment variable or fatals.
 func mustEnv(key string) string {
 	v := os.Getenv(key)